@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/lordofthemind/EventifyGo/configs"
+	"github.com/lordofthemind/EventifyGo/internals/initializers"
+	"github.com/lordofthemind/EventifyGo/internals/notifiers"
+	"github.com/lordofthemind/EventifyGo/internals/rbac"
+	"github.com/lordofthemind/EventifyGo/internals/services"
+	"github.com/lordofthemind/EventifyGo/internals/types"
+	"github.com/lordofthemind/mygopher/gophertoken"
+)
+
+// application holds the services every server engine and command is built from
+type application struct {
+	databases    *initializers.Databases
+	tokenManager gophertoken.TokenManager
+
+	superUserService     services.SuperUserServiceInterface
+	authorizationService services.AuthorizationServiceInterface
+	eventService         services.EventServiceInterface
+	lockoutService       services.LockoutServiceInterface
+	authService          services.AuthServiceInterface
+	sessionService       services.SessionServiceInterface
+	passwordResetService services.PasswordResetServiceInterface
+}
+
+// Connect to the configured database and build the services on top of it
+func newApplication(ctx context.Context, config *configs.Config) (*application, error) {
+	// Initialize database (Postgres, MongoDB or none in memory)
+	databases, err := initializers.DatabaseInitializer(ctx, config.Database)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize database: %w", err)
+	}
+	if err := initializers.CheckSchemaVersion(ctx, databases); err != nil {
+		databases.Close(ctx)
+		return nil, err
+	}
+
+	// Setup the repositories of the selected database
+	repos, err := initializers.RepositoryFactory(config.Database.Type, databases)
+	if err != nil {
+		databases.Close(ctx)
+		return nil, err
+	}
+
+	// Access tokens are issued at login and checked by the auth middleware
+	tokenManager, err := gophertoken.NewTokenManager(config.Token.Type, config.Token.SymmetricKey)
+	if err != nil {
+		databases.Close(ctx)
+		return nil, fmt.Errorf("failed to initialize token manager: %w", err)
+	}
+
+	lockoutService := services.NewLockoutService(repos.LoginAttempts, repos.SuperUsers, services.LockoutPolicy{
+		AccountThreshold: config.Lockout.AccountThreshold,
+		IPThreshold:      config.Lockout.IPThreshold,
+		BaseDelay:        config.Lockout.BaseDelay,
+		LockoutDuration:  config.Lockout.Duration,
+		Window:           config.Lockout.Window,
+	})
+
+	app := &application{
+		databases:            databases,
+		tokenManager:         tokenManager,
+		superUserService:     services.NewSuperUserService(repos.SuperUsers),
+		authorizationService: services.NewAuthorizationService(repos.SuperUsers),
+		eventService:         services.NewEventService(repos.Events, repos.SuperUsers, repos.Waitlist),
+		lockoutService:       lockoutService,
+		authService:          services.NewAuthService(repos.SuperUsers, repos.Sessions, repos.RefreshTokens, tokenManager, config.Token.AccessTokenDuration, config.Token.RefreshTokenDuration, lockoutService),
+		sessionService:       services.NewSessionService(repos.Sessions, repos.RefreshTokens, repos.SuperUsers),
+		passwordResetService: services.NewPasswordResetService(repos.SuperUsers, repos.Sessions, repos.RefreshTokens, notifiers.NewLogNotifier(nil), config.PasswordReset.TokenDuration),
+	}
+
+	// The memory database starts empty, without an admin nobody could log in
+	if config.Database.Type == "memory" {
+		if err := app.bootstrapMemoryAdmin(ctx, config.MemoryAdmin); err != nil {
+			databases.Close(ctx)
+			return nil, err
+		}
+	}
+	return app, nil
+}
+
+// Helper function to create the admin of the memory database
+func (a *application) bootstrapMemoryAdmin(ctx context.Context, admin configs.MemoryAdminConfig) error {
+	superUser, err := a.superUserService.BootstrapSuperUser(ctx, &types.SuperUserType{
+		Email:          admin.Email,
+		Username:       admin.Username,
+		FullName:       admin.Username,
+		HashedPassword: admin.Password,
+		Role:           rbac.RoleAdmin,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create the memory database admin: %w", err)
+	}
+
+	log.Printf("Created admin %s (%s) in the memory database", superUser.Username, superUser.ID)
+	return nil
+}
+
+// Close releases the database connections
+func (a *application) Close(ctx context.Context) error {
+	return a.databases.Close(ctx)
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/lordofthemind/EventifyGo/configs"
+	"github.com/lordofthemind/mygopher/mygopherlogger"
+	"github.com/spf13/pflag"
+)
+
+// command is a subcommand of the binary, args are what follows its name
+type command struct {
+	name    string
+	summary string
+	run     func(ctx context.Context, args []string) error
+}
+
+// Helper function to list the subcommands, a function so the commands can refer to the list
+func commands() []command {
+	return []command{
+		{"serve", "serve the API with the gin, fiber or nethttp engine", serveCommand},
+		{"seed", "fill the database with fake SuperUsers and events", seedCommand},
+		{"migrate", "manage the database schema: up, down or status", migrateCommand},
+		{"superuser", "manage SuperUsers from the command line: create", superUserCommand},
+	}
+}
+
+// Execute runs the subcommand named by the first argument, until it finishes or the process is
+// interrupted
+func Execute(args []string) error {
+	if len(args) == 0 {
+		printUsage(os.Stderr)
+		return errors.New("missing command")
+	}
+
+	name := args[0]
+	if name == "help" || name == "-h" || name == "--help" {
+		printUsage(os.Stdout)
+		return nil
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	for _, c := range commands() {
+		if c.name == name {
+			err := c.run(ctx, args[1:])
+			if errors.Is(err, pflag.ErrHelp) {
+				return nil
+			}
+			return err
+		}
+	}
+	printUsage(os.Stderr)
+	return fmt.Errorf("unknown command %q", name)
+}
+
+// Helper function to print the subcommands
+func printUsage(w io.Writer) {
+	fmt.Fprintf(w, "Usage: %s <command> [flags]\n\nCommands:\n", os.Args[0])
+	for _, c := range commands() {
+		fmt.Fprintf(w, "  %-10s %s\n", c.name, c.summary)
+	}
+	fmt.Fprintf(w, "\nRun %s <command> --help for the flags of a command.\n", os.Args[0])
+}
+
+// Helper function to create the flags of a command, with the configuration flags every command
+// shares
+func newFlagSet(name, usage string) *pflag.FlagSet {
+	flags := pflag.NewFlagSet(name, pflag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: %s %s\n\nFlags:\n%s", os.Args[0], usage, flags.FlagUsages())
+	}
+	configs.RegisterFlags(flags)
+	return flags
+}
+
+// Helper function to load the configuration from the parsed flags of a command and send the log
+// to logFileName as well. The returned file must be closed once the command is done.
+func setUpCommand(flags *pflag.FlagSet, logFileName string) (*configs.Config, io.Closer, error) {
+	logFile, err := mygopherlogger.SetUpLoggerFile(logFileName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to initialize logger: %w", err)
+	}
+
+	// Configuration comes from the file, then EVENTIFY_* variables, then the flags
+	config, err := configs.Load(flags)
+	if err != nil {
+		logFile.Close()
+		return nil, nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+	return config, logFile, nil
+}
@@ -2,17 +2,15 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
 	"time"
 
 	"github.com/bxcodec/faker/v4"
 	"github.com/google/uuid"
 	"github.com/lordofthemind/EventifyGo/configs"
+	"github.com/lordofthemind/EventifyGo/internals/initializers"
 	"github.com/lordofthemind/EventifyGo/internals/types"
-	"github.com/lordofthemind/mygopher/gopherlogger"
-	"github.com/lordofthemind/mygopher/gophermongo"
-	"github.com/lordofthemind/mygopher/gopherpostgres"
 	"go.mongodb.org/mongo-driver/mongo"
 	"golang.org/x/exp/rand"
 	"gorm.io/gorm"
@@ -123,49 +121,50 @@ func SeederFactory(dbType string, db interface{}) Seeder {
 	return nil
 }
 
-func DataSeeder() {
+// Seed the configured database
+func seedCommand(ctx context.Context, args []string) error {
+	flags := newFlagSet("seed", "seed [--superusers=N] [--events=N]")
+	superUsers := flags.Int("superusers", 10, "number of fake SuperUsers")
+	events := flags.Int("events", 5, "number of fake events")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
 
-	logFile, err := gopherlogger.SetUpLoggerFile("Seeder.log")
+	config, logFile, err := setUpCommand(flags, "Seeder.log")
 	if err != nil {
-		log.Fatalf("Failed to initialize logger: %v", err)
+		return err
 	}
 	defer logFile.Close()
 
-	err = configs.SeederConfiguration("config.yaml")
-	if err != nil {
-		log.Fatalf("Failed to load configuration file: %v", err)
-	}
+	return DataSeeder(ctx, config, *superUsers, *events)
+}
 
-	// Postgres connection
-	ctx := context.Background()
-	gormDB, err := gopherpostgres.ConnectToPostgresGORM(ctx, configs.PostgresURL, 10*time.Second, 3)
-	if err != nil {
-		log.Fatalf("Failed to connect to PostgreSQL using GORM: %v", err)
+// DataSeeder fills the configured database with fake SuperUsers and events
+func DataSeeder(ctx context.Context, config *configs.Config, superUsers, events int) error {
+	if config.Database.Type == "memory" {
+		return errors.New("the memory database starts empty with every command, there is nothing to seed")
 	}
 
-	err = gopherpostgres.CheckAndEnableUUIDExtension(gormDB)
+	databases, err := initializers.DatabaseInitializer(ctx, config.Database)
 	if err != nil {
-		log.Fatalf("Failed to confirm UUID extension: %v", err)
+		return fmt.Errorf("failed to initialize database: %w", err)
 	}
-
-	// Auto migrate for GORM (Postgres)
-	if err := gormDB.AutoMigrate(&types.SuperUserType{}, &types.EventType{}); err != nil {
-		log.Fatalf("failed to migrate Postgres database: %v", err)
+	defer databases.Close(context.Background())
+	if err := initializers.CheckSchemaVersion(ctx, databases); err != nil {
+		return err
 	}
 
-	// MongoDB connection
-	mongoClient, err := gophermongo.ConnectToMongoDB(ctx, configs.MongoDbURI, 10*time.Second, 3)
-	if err != nil {
-		log.Fatalf("Failed to connect to MongoDB: %v", err)
+	// Seed the database the servers use
+	var seeder Seeder
+	switch config.Database.Type {
+	case "postgres":
+		seeder = SeederFactory("postgres", databases.GormDB)
+	case "mongodb":
+		seeder = SeederFactory("mongodb", databases.MongoDB)
 	}
-	defer mongoClient.Disconnect(ctx)
-
-	mongoDB := gophermongo.GetDatabase(mongoClient, "superuser")
-
-	// Choose the database to seed
-	seeder := SeederFactory("mongodb", mongoDB) // Change to "mongodb" for MongoDB seeding
 
 	// Seed SuperUsers and Events
-	seeder.SeedSuperUsers(10)
-	seeder.SeedEvents(5)
+	seeder.SeedSuperUsers(superUsers)
+	seeder.SeedEvents(events)
+	return nil
 }
@@ -1,74 +1,59 @@
 package cmd
 
 import (
-	"log"
+	"context"
+	"fmt"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/lordofthemind/EventifyGo/configs"
 	"github.com/lordofthemind/EventifyGo/internals/handlers"
-	"github.com/lordofthemind/EventifyGo/internals/initializers"
-	"github.com/lordofthemind/EventifyGo/internals/repositories"
-	"github.com/lordofthemind/EventifyGo/internals/repositories/mongodb"
 	"github.com/lordofthemind/EventifyGo/internals/routes"
-	"github.com/lordofthemind/EventifyGo/internals/services"
-	"github.com/lordofthemind/mygopher/gophermongo"
-	"github.com/lordofthemind/mygopher/mygopherlogger"
+	"github.com/lordofthemind/EventifyGo/pkgs/middlewares"
 )
 
-func FiberServer() {
-	// Set up logger
-	logFile, err := mygopherlogger.SetUpLoggerFile("fiberServer.log")
+// FiberServer serves the API with the Fiber engine until ctx is cancelled
+func FiberServer(ctx context.Context, config *configs.Config) error {
+	app, err := newApplication(ctx, config)
 	if err != nil {
-		log.Fatalf("Failed to initialize logger: %v", err)
+		return err
 	}
-	defer logFile.Close()
+	defer app.Close(context.Background())
 
-	// Load configuration
-	err = configs.MainConfiguration("config.yaml")
-	if err != nil {
-		log.Fatalf("Failed to load configuration file: %v", err)
-	}
-
-	// Initialize database (Postgres or MongoDB)
-	initializers.DatabaseInitializer()
-
-	// Setup repository and service based on the selected database
-	var superUserRepository repositories.SuperUserRepositoryInterface
-
-	switch configs.Database {
-	case "postgres":
-		if configs.GormDB == nil {
-			log.Fatalf("Postgres connection was not initialized")
-		}
-		// Initialize Postgres repository (if applicable)
-		// superUserRepository = postgres.NewPostgresSuperUserRepository(configs.GormDB) // Example placeholder
-	case "mongodb":
-		if configs.MongoClient == nil {
-			log.Fatalf("MongoDB client was not initialized")
-		}
-
-		// Retrieve the specific database for the SuperUser repository
-		superUserDB := gophermongo.GetDatabase(configs.MongoClient, "superuser")
-		superUserRepository = mongodb.NewMongoSuperUserRepository(superUserDB)
-
-		// Similarly, if you need to set up another repository with a different database:
-		// eventDB := gophermongo.GetDatabase(configs.MongoClient, "events")
-		// eventRepository = mongodb.NewMongoEventRepository(eventDB) // Example
-	default:
-		log.Fatalf("Invalid database configuration")
-	}
-
-	// Initialize service and handler
-	superUserService := services.NewSuperUserService(superUserRepository)
-	superUserHandler := handlers.NewSuperUserFiberHandler(superUserService)
+	// Initialize handlers
+	superUserHandler := handlers.NewSuperUserFiberHandler(app.superUserService)
+	eventHandler := handlers.NewEventFiberHandler(app.eventService)
+	lockoutHandler := handlers.NewLockoutFiberHandler(app.lockoutService)
+	authHandler := handlers.NewAuthFiberHandler(app.authService)
+	sessionHandler := handlers.NewSessionFiberHandler(app.sessionService)
+	passwordResetHandler := handlers.NewPasswordResetFiberHandler(app.passwordResetService)
 
 	// Set up Fiber routes
-	app := fiber.New()
-	routes.SetupSuperUserFiberRoutes(app, superUserHandler)
+	// Client IPs come from X-Forwarded-For only behind a trusted proxy
+	fiberConfig := fiber.Config{}
+	if len(config.Server.TrustedProxies) > 0 {
+		fiberConfig.EnableTrustedProxyCheck = true
+		fiberConfig.TrustedProxies = config.Server.TrustedProxies
+		fiberConfig.ProxyHeader = fiber.HeaderXForwardedFor
+	}
+	server := fiber.New(fiberConfig)
+	server.Use(middlewares.RequestIDFiberMiddleware())
+	routes.SetupAuthFiberRoutes(server, authHandler)
+	routes.SetupPasswordResetFiberRoutes(server, passwordResetHandler)
+	routes.SetupSuperUserFiberRoutes(server, superUserHandler, app.tokenManager, app.sessionService, app.authorizationService)
+	routes.SetupLockoutFiberRoutes(server, lockoutHandler, app.tokenManager, app.sessionService, app.authorizationService)
+	routes.SetupSessionFiberRoutes(server, sessionHandler, app.tokenManager, app.sessionService, app.authorizationService)
+	routes.SetupEventFiberRoutes(server, eventHandler, app.tokenManager, app.sessionService, app.authorizationService)
+
+	// Finish the requests in flight once asked to stop
+	go func() {
+		<-ctx.Done()
+		server.ShutdownWithTimeout(shutdownTimeout)
+	}()
 
 	// Start the Fiber server
-	serverAddress := ":8080" // This can be configurable
-	if err := app.Listen(serverAddress); err != nil {
-		log.Fatalf("Failed to start Fiber server: %v", err)
+	serverAddress := fmt.Sprintf(":%d", config.Server.FiberPort)
+	if err := server.Listen(serverAddress); err != nil {
+		return fmt.Errorf("failed to start Fiber server: %w", err)
 	}
+	return nil
 }
@@ -1,78 +1,63 @@
 package cmd
 
 import (
-	"log"
+	"context"
+	"fmt"
+	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/lordofthemind/EventifyGo/configs"
 	"github.com/lordofthemind/EventifyGo/internals/handlers"
-	"github.com/lordofthemind/EventifyGo/internals/initializers"
-	"github.com/lordofthemind/EventifyGo/internals/repositories"
-	"github.com/lordofthemind/EventifyGo/internals/repositories/mongodb"
 	"github.com/lordofthemind/EventifyGo/internals/routes"
-	"github.com/lordofthemind/EventifyGo/internals/services"
 	"github.com/lordofthemind/EventifyGo/pkgs/middlewares"
-	"github.com/lordofthemind/mygopher/gophermongo"
-	"github.com/lordofthemind/mygopher/mygopherlogger"
 )
 
-func GinServer() {
-	// Set up logger
-	logFile, err := mygopherlogger.SetUpLoggerFile("ginServer.log")
+// GinServer serves the API with the Gin engine until ctx is cancelled
+func GinServer(ctx context.Context, config *configs.Config) error {
+	app, err := newApplication(ctx, config)
 	if err != nil {
-		log.Fatalf("Failed to initialize logger: %v", err)
+		return err
 	}
-	defer logFile.Close()
+	defer app.Close(context.Background())
 
-	// Load configuration
-	err = configs.MainConfiguration("config.yaml")
+	router, err := newGinRouter(app, config)
 	if err != nil {
-		log.Fatalf("Failed to load configuration file: %v", err)
+		return err
 	}
 
-	// Initialize database (Postgres or MongoDB)
-	initializers.DatabaseInitializer()
-
-	// Setup repository and service based on the selected database
-	var superUserRepository repositories.SuperUserRepositoryInterface
-
-	switch configs.Database {
-	case "postgres":
-		if configs.GormDB == nil {
-			log.Fatalf("Postgres connection was not initialized")
-		}
-		// Initialize Postgres repository (not shown in your example, but you can add it here)
-		// superUserRepository = postgres.NewPostgresSuperUserRepository(configs.GormDB) // Example
-
-	case "mongodb":
-		if configs.MongoClient == nil {
-			log.Fatalf("MongoDB client was not initialized")
-		}
-
-		// Retrieve the specific database for the SuperUser repository
-		superUserDB := gophermongo.GetDatabase(configs.MongoClient, "superuser")
-		superUserRepository = mongodb.NewMongoSuperUserRepository(superUserDB)
-
-		// Similarly, if you need to set up another repository with a different database:
-		// eventDB := gophermongo.GetDatabase(configs.MongoClient, "events")
-		// eventRepository = mongodb.NewMongoEventRepository(eventDB) // Example
-
-	default:
-		log.Fatalf("Invalid database configuration")
+	// Start the Gin server, like router.Run but able to stop
+	server := &http.Server{
+		Addr:    fmt.Sprintf(":%d", config.Server.GinPort),
+		Handler: router,
 	}
+	if err := serveHTTP(ctx, server); err != nil {
+		return fmt.Errorf("failed to start Gin server: %w", err)
+	}
+	return nil
+}
 
-	// Initialize service and handler
-	superUserService := services.NewSuperUserService(superUserRepository)
-	superUserHandler := handlers.NewSuperUserGinHandler(superUserService)
+// Helper function to set up the Gin routes over the application services
+func newGinRouter(app *application, config *configs.Config) (*gin.Engine, error) {
+	// Initialize handlers
+	superUserHandler := handlers.NewSuperUserGinHandler(app.superUserService)
+	eventHandler := handlers.NewEventGinHandler(app.eventService)
+	lockoutHandler := handlers.NewLockoutGinHandler(app.lockoutService)
+	authHandler := handlers.NewAuthGinHandler(app.authService)
+	sessionHandler := handlers.NewSessionGinHandler(app.sessionService)
+	passwordResetHandler := handlers.NewPasswordResetGinHandler(app.passwordResetService)
 
 	// Set up Gin routes
 	router := gin.Default()
-	router.Use(middlewares.RequestIDGinMiddleware())
-	routes.SetupSuperUserGinRoutes(router, superUserHandler)
-
-	// Start the Gin server
-	serverAddress := ":9090" // This can be configurable
-	if err := router.Run(serverAddress); err != nil {
-		log.Fatalf("Failed to start Gin server: %v", err)
+	if err := router.SetTrustedProxies(config.Server.TrustedProxies); err != nil {
+		return nil, fmt.Errorf("invalid trusted proxies: %w", err)
 	}
+	router.Use(middlewares.RequestIDGinMiddleware())
+	routes.SetupAuthGinRoutes(router, authHandler)
+	routes.SetupPasswordResetGinRoutes(router, passwordResetHandler)
+	routes.SetupSuperUserGinRoutes(router, superUserHandler, app.tokenManager, app.sessionService, app.authorizationService)
+	routes.SetupLockoutGinRoutes(router, lockoutHandler, app.tokenManager, app.sessionService, app.authorizationService)
+	routes.SetupSessionGinRoutes(router, sessionHandler, app.tokenManager, app.sessionService, app.authorizationService)
+	routes.SetupEventGinRoutes(router, eventHandler, app.tokenManager, app.sessionService, app.authorizationService)
+
+	return router, nil
 }
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+
+	"github.com/lordofthemind/EventifyGo/internals/initializers"
+	"github.com/lordofthemind/EventifyGo/internals/migrations"
+)
+
+// Manage the database schema
+func migrateCommand(ctx context.Context, args []string) error {
+	flags := newFlagSet("migrate", "migrate up|down|status [--steps=N]")
+	steps := flags.Int("steps", 1, "number of migrations migrate down reverts")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return errors.New("migrate expects one of up, down or status")
+	}
+	action := flags.Arg(0)
+	if action != "up" && action != "down" && action != "status" {
+		return fmt.Errorf("unknown migrate action %q, expected up, down or status", action)
+	}
+	if *steps < 1 {
+		return fmt.Errorf("steps must be at least 1, got %d", *steps)
+	}
+
+	config, logFile, err := setUpCommand(flags, "Migrate.log")
+	if err != nil {
+		return err
+	}
+	defer logFile.Close()
+
+	databases, err := initializers.DatabaseInitializer(ctx, config.Database)
+	if err != nil {
+		return fmt.Errorf("failed to initialize database: %w", err)
+	}
+	defer databases.Close(context.Background())
+
+	// Only Postgres has a versioned schema, MongoDB indexes and validators are ensured on connect
+	if databases.GormDB == nil {
+		log.Printf("The %s database has no SQL schema to migrate", config.Database.Type)
+		return nil
+	}
+	sqlDB, err := databases.GormDB.DB()
+	if err != nil {
+		return err
+	}
+	migrator := migrations.NewMigrator(sqlDB)
+
+	switch action {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, migration := range applied {
+			log.Printf("Applied migration %d_%s", migration.Version, migration.Name)
+		}
+		if err != nil {
+			return err
+		}
+		log.Printf("The schema is at version %d", migrations.LatestVersion())
+
+	case "down":
+		reverted, err := migrator.Down(ctx, *steps)
+		for _, migration := range reverted {
+			log.Printf("Reverted migration %d_%s", migration.Version, migration.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(reverted) == 0 {
+			log.Println("No migration to revert")
+		}
+
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.AppliedAt != nil {
+				appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05 MST")
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\n", status.Version, status.Name, appliedAt)
+		}
+		w.Flush()
+	}
+	return nil
+}
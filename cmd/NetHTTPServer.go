@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/lordofthemind/EventifyGo/configs"
+)
+
+// shutdownTimeout bounds how long the requests in flight get to finish on shutdown
+const shutdownTimeout = 10 * time.Second
+
+// NetHTTPServer serves the API routes from a plain net/http server with read, write and idle
+// timeouts until ctx is cancelled
+func NetHTTPServer(ctx context.Context, config *configs.Config) error {
+	app, err := newApplication(ctx, config)
+	if err != nil {
+		return err
+	}
+	defer app.Close(context.Background())
+
+	// The Gin router is a plain http.Handler, only its routing is used
+	router, err := newGinRouter(app, config)
+	if err != nil {
+		return err
+	}
+
+	server := &http.Server{
+		Addr:              fmt.Sprintf(":%d", config.Server.GinPort),
+		Handler:           router,
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		WriteTimeout:      30 * time.Second,
+		IdleTimeout:       2 * time.Minute,
+	}
+	if err := serveHTTP(ctx, server); err != nil {
+		return fmt.Errorf("failed to start net/http server: %w", err)
+	}
+	return nil
+}
+
+// Helper function to run a server until ctx is cancelled, then let the requests in flight finish
+func serveHTTP(ctx context.Context, server *http.Server) error {
+	stopped := make(chan error, 1)
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		stopped <- server.Shutdown(shutdownCtx)
+	}()
+
+	log.Printf("Listening on %s", server.Addr)
+	if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return <-stopped
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lordofthemind/EventifyGo/configs"
+)
+
+// Engines the API can be served with
+const (
+	EngineGin     = "gin"
+	EngineFiber   = "fiber"
+	EngineNetHTTP = "nethttp"
+)
+
+// servers starts the API with each engine
+var servers = map[string]func(ctx context.Context, config *configs.Config) error{
+	EngineGin:     GinServer,
+	EngineFiber:   FiberServer,
+	EngineNetHTTP: NetHTTPServer,
+}
+
+// Serve the API until interrupted
+func serveCommand(ctx context.Context, args []string) error {
+	flags := newFlagSet("serve", "serve [--engine=gin|fiber|nethttp] [--port=PORT]")
+	engine := flags.String("engine", EngineGin, "HTTP engine, gin, fiber or nethttp")
+	port := flags.Int("port", 0, "port to listen on, defaults to fiber_port for fiber and gin_port otherwise")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	server, ok := servers[*engine]
+	if !ok {
+		return fmt.Errorf("unknown engine %q, expected gin, fiber or nethttp", *engine)
+	}
+	if flags.Changed("port") && (*port < 1 || *port > 65535) {
+		return fmt.Errorf("port must be between 1 and 65535, got %d", *port)
+	}
+
+	config, logFile, err := setUpCommand(flags, *engine+"Server.log")
+	if err != nil {
+		return err
+	}
+	defer logFile.Close()
+
+	// The flag wins over the port configured for the engine
+	if flags.Changed("port") {
+		if *engine == EngineFiber {
+			config.Server.FiberPort = *port
+		} else {
+			config.Server.GinPort = *port
+		}
+	}
+
+	return server(ctx, config)
+}
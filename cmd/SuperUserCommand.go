@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/lordofthemind/EventifyGo/configs"
+	"github.com/lordofthemind/EventifyGo/internals/types"
+	"github.com/spf13/pflag"
+)
+
+// superUserPasswordEnv holds the password of superuser create when the flag is not given, to keep
+// it out of the shell history
+const superUserPasswordEnv = configs.EnvPrefix + "_SUPERUSER_PASSWORD"
+
+// Manage SuperUsers without going through the API
+func superUserCommand(ctx context.Context, args []string) error {
+	if len(args) == 0 || args[0] != "create" {
+		fmt.Fprintf(os.Stderr, "Usage: %s superuser create --email=EMAIL --username=NAME [flags]\n", os.Args[0])
+		if len(args) > 0 && (args[0] == "-h" || args[0] == "--help") {
+			return pflag.ErrHelp
+		}
+		return errors.New("superuser expects the create action")
+	}
+
+	flags := newFlagSet("superuser create", "superuser create --email=EMAIL --username=NAME [flags]")
+	email := flags.String("email", "", "email of the SuperUser")
+	username := flags.String("username", "", "username of the SuperUser")
+	fullName := flags.String("full-name", "", "full name of the SuperUser")
+	password := flags.String("password", "", "password of the SuperUser, defaults to "+superUserPasswordEnv)
+	role := flags.String("role", "admin", "role of the SuperUser")
+	permissions := flags.StringSlice("permission", nil, "extra permission groups of the SuperUser")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+	if *password == "" {
+		*password = os.Getenv(superUserPasswordEnv)
+	}
+
+	config, logFile, err := setUpCommand(flags, "SuperUser.log")
+	if err != nil {
+		return err
+	}
+	defer logFile.Close()
+	if config.Database.Type == "memory" {
+		return errors.New("the memory database is lost when the command exits, serve creates its admin from the memory_admin_* settings")
+	}
+
+	app, err := newApplication(ctx, config)
+	if err != nil {
+		return err
+	}
+	defer app.Close(context.Background())
+
+	// Nobody can grant the first admin its role over the API, the command line is trusted instead
+	superUser, err := app.superUserService.BootstrapSuperUser(ctx, &types.SuperUserType{
+		Email:            *email,
+		Username:         *username,
+		FullName:         *fullName,
+		HashedPassword:   *password,
+		Role:             *role,
+		PermissionGroups: *permissions,
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Created SuperUser %s (%s) with role %s", superUser.Username, superUser.ID, superUser.Role)
+	return nil
+}
@@ -1,37 +1,302 @@
 package configs
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"os"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/spf13/pflag"
 	"github.com/spf13/viper"
-	"go.mongodb.org/mongo-driver/mongo"
-	"gorm.io/gorm"
 )
 
-var (
-	PostgresURL string
-	MongoDbURI  string
-	GormDB      *gorm.DB
-	MongoDB     *mongo.Database
-	MongoClient *mongo.Client
-	Database    string
-)
+// EnvPrefix prefixes the environment variables overriding the configuration file
+const EnvPrefix = "EVENTIFY"
+
+// DefaultConfigFile is read when no other configuration file is given
+const DefaultConfigFile = "config.yaml"
+
+// Config is the configuration of the application. Keys are flat in the file, the groups only
+// organize the struct.
+type Config struct {
+	Database      DatabaseConfig      `mapstructure:",squash"`
+	Server        ServerConfig        `mapstructure:",squash"`
+	Token         TokenConfig         `mapstructure:",squash"`
+	PasswordReset PasswordResetConfig `mapstructure:",squash"`
+	Lockout       LockoutConfig       `mapstructure:",squash"`
+	MemoryAdmin   MemoryAdminConfig   `mapstructure:",squash"`
+}
+
+// DatabaseConfig selects the database and how to reach it
+type DatabaseConfig struct {
+	Type           string        `mapstructure:"database_type"`
+	PostgresURL    string        `mapstructure:"postgres_url"`
+	MongoDbURI     string        `mapstructure:"mongodb_uri"`
+	MongoDatabase  string        `mapstructure:"mongodb_database"`
+	ConnectTimeout time.Duration `mapstructure:"db_connect_timeout"`
+	ConnectRetries int           `mapstructure:"db_connect_retries"`
+
+	// MongoSchemaValidation installs $jsonSchema validators on the superusers and events collections
+	MongoSchemaValidation bool `mapstructure:"mongodb_schema_validation"`
+}
+
+// ServerConfig sets where the servers listen
+type ServerConfig struct {
+	GinPort   int `mapstructure:"gin_port"`
+	FiberPort int `mapstructure:"fiber_port"`
+
+	// Client IPs are only taken from X-Forwarded-For when the request comes through these proxies
+	TrustedProxies []string `mapstructure:"trusted_proxies"`
+}
+
+// TokenConfig sets how access and refresh tokens are issued
+type TokenConfig struct {
+	Type                 string        `mapstructure:"token_type"`
+	SymmetricKey         string        `mapstructure:"token_symmetric_key"`
+	AccessTokenDuration  time.Duration `mapstructure:"access_token_duration"`
+	RefreshTokenDuration time.Duration `mapstructure:"refresh_token_duration"`
+}
+
+// PasswordResetConfig sets how long a password reset token stays valid
+type PasswordResetConfig struct {
+	TokenDuration time.Duration `mapstructure:"password_reset_token_duration"`
+}
+
+// LockoutConfig sets when failed logins are throttled
+type LockoutConfig struct {
+	AccountThreshold int           `mapstructure:"lockout_account_threshold"`
+	IPThreshold      int           `mapstructure:"lockout_ip_threshold"`
+	BaseDelay        time.Duration `mapstructure:"lockout_base_delay"`
+	Duration         time.Duration `mapstructure:"lockout_duration"`
+	Window           time.Duration `mapstructure:"lockout_window"`
+}
+
+// MemoryAdminConfig is the admin created when serving from the memory database, which starts
+// empty and cannot be reached by the superuser command
+type MemoryAdminConfig struct {
+	Email    string `mapstructure:"memory_admin_email"`
+	Username string `mapstructure:"memory_admin_username"`
+	Password string `mapstructure:"memory_admin_password"`
+}
+
+// setting is a configuration key with its default, it can be set by flag as well
+type setting struct {
+	key          string
+	defaultValue interface{}
+	usage        string
+}
+
+// settings lists every configuration key
+var settings = []setting{
+	{"database_type", "mongodb", "database backend, postgres, mongodb or memory"},
+	{"postgres_url", "", "Postgres connection URL"},
+	{"mongodb_uri", "", "MongoDB connection URI"},
+	{"mongodb_database", "superuser", "MongoDB database name"},
+	{"mongodb_schema_validation", true, "install $jsonSchema validators on MongoDB collections"},
+	{"db_connect_timeout", 10 * time.Second, "timeout of a database connection attempt"},
+	{"db_connect_retries", 3, "number of database connection attempts"},
+	{"gin_port", 9090, "port of the Gin server"},
+	{"fiber_port", 8080, "port of the Fiber server"},
+	{"trusted_proxies", []string{}, "proxies allowed to set X-Forwarded-For"},
+	{"token_type", "jwt", "access token format, jwt or paseto"},
+	{"token_symmetric_key", "", "key signing access tokens"},
+	{"access_token_duration", 15 * time.Minute, "lifetime of an access token"},
+	{"refresh_token_duration", 168 * time.Hour, "lifetime of a refresh token"},
+	{"password_reset_token_duration", time.Hour, "lifetime of a password reset token"},
+	{"lockout_account_threshold", 5, "failed logins locking an account, 0 disables"},
+	{"lockout_ip_threshold", 20, "failed logins locking a client IP, 0 disables"},
+	{"lockout_base_delay", time.Second, "first delay after the free failed logins"},
+	{"lockout_duration", 15 * time.Minute, "how long a lockout lasts"},
+	{"lockout_window", 15 * time.Minute, "how long failed logins are remembered"},
+	{"memory_admin_email", "admin@eventify.local", "email of the admin created for the memory database"},
+	{"memory_admin_username", "admin", "username of the admin created for the memory database"},
+	{"memory_admin_password", "", "password of the admin created for the memory database"},
+}
 
-func MainConfiguration(configFile string) error {
-	viper.SetConfigFile(configFile)
+// RegisterFlags adds a flag for the configuration file and one for every setting, named after
+// its key with dashes
+func RegisterFlags(flags *pflag.FlagSet) {
+	flags.String("config", DefaultConfigFile, "configuration file, empty to only use environment and flags")
+	for _, s := range settings {
+		name := flagName(s.key)
+		switch value := s.defaultValue.(type) {
+		case string:
+			flags.String(name, value, s.usage)
+		case int:
+			flags.Int(name, value, s.usage)
+		case bool:
+			flags.Bool(name, value, s.usage)
+		case time.Duration:
+			flags.Duration(name, value, s.usage)
+		case []string:
+			flags.StringSlice(name, value, s.usage)
+		}
+	}
+}
+
+// Load reads the configuration file, then the EVENTIFY_* environment variables, then the flags
+// set on the command line, each overriding the previous one, and validates the result. Flags
+// must come from RegisterFlags, nil skips them.
+func Load(flags *pflag.FlagSet) (*Config, error) {
+	v := viper.New()
+	for _, s := range settings {
+		v.SetDefault(s.key, s.defaultValue)
+	}
+
+	v.SetEnvPrefix(EnvPrefix)
+	v.AutomaticEnv()
+
+	configFile := DefaultConfigFile
+	if flags != nil {
+		for _, s := range settings {
+			// Unset flags keep their lower priority than the file and the environment
+			if err := v.BindPFlag(s.key, flags.Lookup(flagName(s.key))); err != nil {
+				return nil, fmt.Errorf("failed to bind flag %s: %w", flagName(s.key), err)
+			}
+		}
+		if flag := flags.Lookup("config"); flag != nil {
+			configFile = flag.Value.String()
+		}
+	}
+	if fromEnv, ok := os.LookupEnv(EnvPrefix + "_CONFIG"); ok && (flags == nil || !flags.Changed("config")) {
+		configFile = fromEnv
+	}
 
-	// Attempt to read the config file
-	if err := viper.ReadInConfig(); err != nil {
-		return fmt.Errorf("error reading config.yaml file: %w", err)
+	if configFile != "" {
+		v.SetConfigFile(configFile)
+		if err := v.ReadInConfig(); err != nil {
+			return nil, fmt.Errorf("error reading configuration file %s: %w", configFile, err)
+		}
 	}
 
-	PostgresURL = viper.GetString("postgres_url")
-	MongoDbURI = viper.GetString("mongodb_uri")
-	Database = viper.GetString("database_type")
+	var config Config
+	if err := v.Unmarshal(&config); err != nil {
+		return nil, fmt.Errorf("error decoding configuration: %w", err)
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
 
 	log.Println("Main Configuration Done!!")
 
-	return nil
+	return &config, nil
+}
+
+// placeholderKeyMarkers give away a token key that was never replaced by a real secret
+var placeholderKeyMarkers = []string{"changeme", "change_me", "change-me", "placeholder", "your-secret", "your_secret"}
+
+// Validate reports every invalid setting at once
+func (c *Config) Validate() error {
+	var problems []error
+	invalid := func(key, format string, args ...interface{}) {
+		problems = append(problems, fmt.Errorf("%s (%s): %s", key, envName(key), fmt.Sprintf(format, args...)))
+	}
+
+	switch c.Database.Type {
+	case "postgres":
+		if c.Database.PostgresURL == "" {
+			invalid("postgres_url", "is required when database_type is postgres")
+		}
+	case "mongodb":
+		if c.Database.MongoDbURI == "" {
+			invalid("mongodb_uri", "is required when database_type is mongodb")
+		}
+		if c.Database.MongoDatabase == "" {
+			invalid("mongodb_database", "is required when database_type is mongodb")
+		}
+	case "memory":
+		// Nothing to connect to, the data is lost on exit and an admin is created at start
+		if c.MemoryAdmin.Email == "" {
+			invalid("memory_admin_email", "is required when database_type is memory")
+		}
+		if c.MemoryAdmin.Username == "" {
+			invalid("memory_admin_username", "is required when database_type is memory")
+		}
+		if len(c.MemoryAdmin.Password) < 8 {
+			invalid("memory_admin_password", "must be at least 8 characters when database_type is memory")
+		}
+	default:
+		invalid("database_type", "must be postgres, mongodb or memory, got %q", c.Database.Type)
+	}
+	if c.Database.ConnectTimeout <= 0 {
+		invalid("db_connect_timeout", "must be positive, got %s", c.Database.ConnectTimeout)
+	}
+	if c.Database.ConnectRetries < 1 {
+		invalid("db_connect_retries", "must be at least 1, got %d", c.Database.ConnectRetries)
+	}
+
+	for key, port := range map[string]int{"gin_port": c.Server.GinPort, "fiber_port": c.Server.FiberPort} {
+		if port < 1 || port > 65535 {
+			invalid(key, "must be between 1 and 65535, got %d", port)
+		}
+	}
+
+	if c.Token.Type != "jwt" && c.Token.Type != "paseto" {
+		invalid("token_type", "must be jwt or paseto, got %q", c.Token.Type)
+	}
+	switch key := c.Token.SymmetricKey; {
+	case key == "":
+		invalid("token_symmetric_key", "is required, set it to a random secret")
+	case isPlaceholderKey(key):
+		invalid("token_symmetric_key", "is a placeholder, set it to a random secret")
+	case c.Token.Type == "jwt" && len(key) < 32:
+		invalid("token_symmetric_key", "must be at least 32 characters for jwt tokens")
+	case c.Token.Type == "paseto" && len(key) != 32:
+		invalid("token_symmetric_key", "must be exactly 32 characters for paseto tokens")
+	}
+
+	durations := map[string]time.Duration{
+		"access_token_duration":         c.Token.AccessTokenDuration,
+		"refresh_token_duration":        c.Token.RefreshTokenDuration,
+		"password_reset_token_duration": c.PasswordReset.TokenDuration,
+		"lockout_base_delay":            c.Lockout.BaseDelay,
+		"lockout_duration":              c.Lockout.Duration,
+		"lockout_window":                c.Lockout.Window,
+	}
+	for key, duration := range durations {
+		if duration <= 0 {
+			invalid(key, "must be positive, got %s", duration)
+		}
+	}
+	if c.Token.RefreshTokenDuration > 0 && c.Token.RefreshTokenDuration < c.Token.AccessTokenDuration {
+		invalid("refresh_token_duration", "must not be shorter than access_token_duration")
+	}
+
+	if c.Lockout.AccountThreshold < 0 {
+		invalid("lockout_account_threshold", "must not be negative, got %d", c.Lockout.AccountThreshold)
+	}
+	if c.Lockout.IPThreshold < 0 {
+		invalid("lockout_ip_threshold", "must not be negative, got %d", c.Lockout.IPThreshold)
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	// The checks over maps run in random order
+	sort.Slice(problems, func(i, j int) bool { return problems[i].Error() < problems[j].Error() })
+	return fmt.Errorf("invalid configuration:\n%w", errors.Join(problems...))
+}
+
+// Helper function to recognize a token key copied from an example instead of generated
+func isPlaceholderKey(key string) bool {
+	lower := strings.ToLower(key)
+	for _, marker := range placeholderKeyMarkers {
+		if strings.Contains(lower, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// Helper function to name the flag of a key
+func flagName(key string) string {
+	return strings.ReplaceAll(key, "_", "-")
+}
 
+// Helper function to name the environment variable of a key
+func envName(key string) string {
+	return EnvPrefix + "_" + strings.ToUpper(key)
 }
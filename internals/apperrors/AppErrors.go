@@ -0,0 +1,59 @@
+package apperrors
+
+import "errors"
+
+// Kinds of errors shared by every layer. Repositories and services return errors that match
+// one of these through errors.Is, the handlers translate the kind into an HTTP status.
+var (
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+	ErrValidation   = errors.New("validation error")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+
+	ErrTooManyRequests = errors.New("too many requests")
+)
+
+// kindError is an error with its own message that still matches its kind
+type kindError struct {
+	kind    error
+	message string
+}
+
+func (e *kindError) Error() string {
+	return e.message
+}
+
+func (e *kindError) Unwrap() error {
+	return e.kind
+}
+
+// NotFound returns a new error of kind ErrNotFound
+func NotFound(message string) error {
+	return &kindError{kind: ErrNotFound, message: message}
+}
+
+// Conflict returns a new error of kind ErrConflict
+func Conflict(message string) error {
+	return &kindError{kind: ErrConflict, message: message}
+}
+
+// Validation returns a new error of kind ErrValidation
+func Validation(message string) error {
+	return &kindError{kind: ErrValidation, message: message}
+}
+
+// Unauthorized returns a new error of kind ErrUnauthorized
+func Unauthorized(message string) error {
+	return &kindError{kind: ErrUnauthorized, message: message}
+}
+
+// Forbidden returns a new error of kind ErrForbidden
+func Forbidden(message string) error {
+	return &kindError{kind: ErrForbidden, message: message}
+}
+
+// TooManyRequests returns a new error of kind ErrTooManyRequests
+func TooManyRequests(message string) error {
+	return &kindError{kind: ErrTooManyRequests, message: message}
+}
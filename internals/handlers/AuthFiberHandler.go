@@ -1 +1,99 @@
 package handlers
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/lordofthemind/EventifyGo/internals/responses"
+	"github.com/lordofthemind/EventifyGo/internals/services"
+	"github.com/lordofthemind/EventifyGo/pkgs/middlewares"
+)
+
+type AuthFiberHandler struct {
+	service services.AuthServiceInterface
+}
+
+func NewAuthFiberHandler(service services.AuthServiceInterface) *AuthFiberHandler {
+	return &AuthFiberHandler{service: service}
+}
+
+// Login handler, sets the auth cookie of the SuperUser
+func (h *AuthFiberHandler) LoginHandler(c *fiber.Ctx) error {
+	var request loginRequest
+	if err := c.BodyParser(&request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(responses.NewFiberResponse(c, fiber.StatusBadRequest, "Invalid input", nil, err.Error()))
+	}
+
+	result, err := h.service.Login(c.UserContext(), request.Identifier, request.Password, request.Code, fiberClientInfo(c))
+	if err != nil {
+		if seconds, ok := retryAfter(err); ok {
+			c.Set(fiber.HeaderRetryAfter, seconds)
+		}
+		status := responses.ErrorStatus(err)
+		return c.Status(status).JSON(responses.NewFiberResponse(c, status, "Login failed", nil, err.Error()))
+	}
+
+	setFiberAuthCookies(c, result)
+	return c.Status(fiber.StatusOK).JSON(responses.NewFiberResponse(c, fiber.StatusOK, "Logged in successfully", result, nil))
+}
+
+// Refresh handler, trades the refresh cookie for new auth and refresh cookies
+func (h *AuthFiberHandler) RefreshHandler(c *fiber.Ctx) error {
+	result, err := h.service.Refresh(c.UserContext(), c.Cookies(refreshCookieName), fiberClientInfo(c))
+	if err != nil {
+		status := responses.ErrorStatus(err)
+		if status == fiber.StatusUnauthorized {
+			clearFiberAuthCookies(c)
+		}
+		return c.Status(status).JSON(responses.NewFiberResponse(c, status, "Refresh failed", nil, err.Error()))
+	}
+
+	setFiberAuthCookies(c, result)
+	return c.Status(fiber.StatusOK).JSON(responses.NewFiberResponse(c, fiber.StatusOK, "Tokens refreshed successfully", result, nil))
+}
+
+// Logout handler, revokes the refresh token and clears the cookies
+func (h *AuthFiberHandler) LogoutHandler(c *fiber.Ctx) error {
+	if err := h.service.Logout(c.UserContext(), c.Cookies(refreshCookieName)); err != nil {
+		status := responses.ErrorStatus(err)
+		return c.Status(status).JSON(responses.NewFiberResponse(c, status, "Logout failed", nil, err.Error()))
+	}
+
+	clearFiberAuthCookies(c)
+	return c.Status(fiber.StatusOK).JSON(responses.NewFiberResponse(c, fiber.StatusOK, "Logged out successfully", nil, nil))
+}
+
+// Helper function to describe the device of a request. Fiber reuses the memory of request
+// values, so they are copied before the services store them.
+func fiberClientInfo(c *fiber.Ctx) services.ClientInfo {
+	return services.ClientInfo{
+		IP:        utils.CopyString(c.IP()),
+		UserAgent: utils.CopyString(c.Get(fiber.HeaderUserAgent)),
+	}
+}
+
+// Helper function to set the auth and refresh cookies of a login or refresh
+func setFiberAuthCookies(c *fiber.Ctx, result *services.LoginResult) {
+	c.Cookie(authCookie(c, middlewares.AuthCookieName, result.Token, "/", result.ExpiresAt))
+	c.Cookie(authCookie(c, refreshCookieName, result.RefreshToken, refreshCookiePath, result.RefreshExpiresAt))
+}
+
+// Helper function to clear the auth and refresh cookies
+func clearFiberAuthCookies(c *fiber.Ctx) {
+	c.Cookie(authCookie(c, middlewares.AuthCookieName, "", "/", time.Unix(0, 0)))
+	c.Cookie(authCookie(c, refreshCookieName, "", refreshCookiePath, time.Unix(0, 0)))
+}
+
+// Helper function to build a cookie with the same attributes as the Gin handler
+func authCookie(c *fiber.Ctx, name, value, path string, expiresAt time.Time) *fiber.Cookie {
+	return &fiber.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     path,
+		Expires:  expiresAt,
+		Secure:   c.Protocol() == "https",
+		HTTPOnly: true,
+		SameSite: fiber.CookieSameSiteLaxMode,
+	}
+}
@@ -1 +1,100 @@
 package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lordofthemind/EventifyGo/internals/responses"
+	"github.com/lordofthemind/EventifyGo/internals/services"
+	"github.com/lordofthemind/EventifyGo/pkgs/middlewares"
+)
+
+type AuthGinHandler struct {
+	service services.AuthServiceInterface
+}
+
+func NewAuthGinHandler(service services.AuthServiceInterface) *AuthGinHandler {
+	return &AuthGinHandler{service: service}
+}
+
+// Login handler, sets the auth cookie of the SuperUser
+func (h *AuthGinHandler) LoginHandler(c *gin.Context) {
+	var request loginRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		response := responses.NewGinResponse(c, http.StatusBadRequest, "Invalid input", nil, err.Error())
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	result, err := h.service.Login(c.Request.Context(), request.Identifier, request.Password, request.Code, ginClientInfo(c))
+	if err != nil {
+		if seconds, ok := retryAfter(err); ok {
+			c.Header("Retry-After", seconds)
+		}
+		status := responses.ErrorStatus(err)
+		response := responses.NewGinResponse(c, status, "Login failed", nil, err.Error())
+		c.JSON(status, response)
+		return
+	}
+
+	setGinAuthCookies(c, result)
+
+	response := responses.NewGinResponse(c, http.StatusOK, "Logged in successfully", result, nil)
+	c.JSON(http.StatusOK, response)
+}
+
+// Refresh handler, trades the refresh cookie for new auth and refresh cookies
+func (h *AuthGinHandler) RefreshHandler(c *gin.Context) {
+	refreshToken, _ := c.Cookie(refreshCookieName)
+
+	result, err := h.service.Refresh(c.Request.Context(), refreshToken, ginClientInfo(c))
+	if err != nil {
+		status := responses.ErrorStatus(err)
+		if status == http.StatusUnauthorized {
+			clearGinAuthCookies(c)
+		}
+		response := responses.NewGinResponse(c, status, "Refresh failed", nil, err.Error())
+		c.JSON(status, response)
+		return
+	}
+
+	setGinAuthCookies(c, result)
+
+	response := responses.NewGinResponse(c, http.StatusOK, "Tokens refreshed successfully", result, nil)
+	c.JSON(http.StatusOK, response)
+}
+
+// Logout handler, revokes the refresh token and clears the cookies
+func (h *AuthGinHandler) LogoutHandler(c *gin.Context) {
+	refreshToken, _ := c.Cookie(refreshCookieName)
+	if err := h.service.Logout(c.Request.Context(), refreshToken); err != nil {
+		status := responses.ErrorStatus(err)
+		response := responses.NewGinResponse(c, status, "Logout failed", nil, err.Error())
+		c.JSON(status, response)
+		return
+	}
+
+	clearGinAuthCookies(c)
+
+	response := responses.NewGinResponse(c, http.StatusOK, "Logged out successfully", nil, nil)
+	c.JSON(http.StatusOK, response)
+}
+
+// Helper function to describe the device of a request
+func ginClientInfo(c *gin.Context) services.ClientInfo {
+	return services.ClientInfo{IP: c.ClientIP(), UserAgent: c.Request.UserAgent()}
+}
+
+// Helper function to set the auth and refresh cookies of a login or refresh
+func setGinAuthCookies(c *gin.Context, result *services.LoginResult) {
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(middlewares.AuthCookieName, result.Token, cookieMaxAge(result.ExpiresAt), "/", "", c.Request.TLS != nil, true)
+	c.SetCookie(refreshCookieName, result.RefreshToken, cookieMaxAge(result.RefreshExpiresAt), refreshCookiePath, "", c.Request.TLS != nil, true)
+}
+
+// Helper function to clear the auth and refresh cookies
+func clearGinAuthCookies(c *gin.Context) {
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(middlewares.AuthCookieName, "", -1, "/", "", c.Request.TLS != nil, true)
+	c.SetCookie(refreshCookieName, "", -1, refreshCookiePath, "", c.Request.TLS != nil, true)
+}
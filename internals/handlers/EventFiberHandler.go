@@ -0,0 +1,455 @@
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/lordofthemind/EventifyGo/internals/responses"
+	"github.com/lordofthemind/EventifyGo/internals/services"
+	"github.com/lordofthemind/EventifyGo/internals/types"
+)
+
+type EventFiberHandler struct {
+	service services.EventServiceInterface
+}
+
+func NewEventFiberHandler(service services.EventServiceInterface) *EventFiberHandler {
+	return &EventFiberHandler{service: service}
+}
+
+// Create Event handler
+func (h *EventFiberHandler) CreateEventHandler(c *fiber.Ctx) error {
+	loc, err := parseZone(c.Query("tz"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(responses.NewFiberResponse(c, fiber.StatusBadRequest, "Invalid time zone", nil, err.Error()))
+	}
+
+	var event types.EventType
+	if err := c.BodyParser(&event); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(responses.NewFiberResponse(c, fiber.StatusBadRequest, "Invalid input", nil, err.Error()))
+	}
+
+	createdEvent, err := h.service.CreateEvent(c.UserContext(), fiberViewerID(c), &event)
+	if err != nil {
+		status := responses.ErrorStatus(err)
+		return c.Status(status).JSON(responses.NewFiberResponse(c, status, "Failed to create Event", nil, err.Error()))
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(responses.NewFiberResponse(c, fiber.StatusCreated, "Event created successfully", createdEvent.In(loc), nil))
+}
+
+// Import Events handler, takes a multipart "file" upload in iCalendar or CSV format. The
+// Events are organized by the authenticated SuperUser.
+func (h *EventFiberHandler) ImportEventsHandler(c *fiber.Ctx) error {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(responses.NewFiberResponse(c, fiber.StatusBadRequest, "Invalid input", nil, err.Error()))
+	}
+
+	capacity, err := parseImportCapacity(c.FormValue("capacity"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(responses.NewFiberResponse(c, fiber.StatusBadRequest, "Invalid input", nil, err.Error()))
+	}
+	dryRun := c.QueryBool("dry_run", false)
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(responses.NewFiberResponse(c, fiber.StatusBadRequest, "Failed to read file", nil, err.Error()))
+	}
+	defer file.Close()
+
+	result, err := h.service.ImportEvents(c.UserContext(), file, services.ImportOptions{
+		Format:          importFormat(c.FormValue("format"), fileHeader.Filename),
+		OrganizerID:     fiberViewerID(c),
+		DefaultCapacity: capacity,
+		DryRun:          dryRun,
+	})
+	if err != nil {
+		status := responses.ErrorStatus(err)
+		return c.Status(status).JSON(responses.NewFiberResponse(c, status, "Failed to import Events", nil, err.Error()))
+	}
+
+	message := "Events imported"
+	if dryRun {
+		message = "Import preview"
+	}
+	return c.Status(fiber.StatusOK).JSON(responses.NewFiberResponse(c, fiber.StatusOK, message, result, nil))
+}
+
+// Get Event by ID handler
+func (h *EventFiberHandler) GetEventByIDHandler(c *fiber.Ctx) error {
+	loc, err := parseZone(c.Query("tz"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(responses.NewFiberResponse(c, fiber.StatusBadRequest, "Invalid time zone", nil, err.Error()))
+	}
+
+	param, isICS := splitICSParam(c.Params("id"))
+	id, err := uuid.Parse(param)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(responses.NewFiberResponse(c, fiber.StatusBadRequest, "Invalid ID format", nil, err.Error()))
+	}
+
+	if isICS {
+		return h.exportEventCalendar(c, id)
+	}
+
+	event, err := h.service.GetEventByID(c.UserContext(), fiberViewerID(c), id)
+	if err != nil {
+		status := responses.ErrorStatus(err)
+		return c.Status(status).JSON(responses.NewFiberResponse(c, status, "Failed to retrieve Event", nil, err.Error()))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(responses.NewFiberResponse(c, fiber.StatusOK, "Event retrieved successfully", event.In(loc), nil))
+}
+
+// Helper function to serve an Event as an iCalendar file
+func (h *EventFiberHandler) exportEventCalendar(c *fiber.Ctx, id uuid.UUID) error {
+	data, err := h.service.ExportEventCalendar(c.UserContext(), fiberViewerID(c), id)
+	if err != nil {
+		status := responses.ErrorStatus(err)
+		return c.Status(status).JSON(responses.NewFiberResponse(c, status, "Failed to export Event", nil, err.Error()))
+	}
+
+	c.Set(fiber.HeaderContentType, icsContentType)
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(icsContentDisposition, id.String()+icsSuffix))
+	return c.Status(fiber.StatusOK).Send(data)
+}
+
+// Export SuperUser calendar feed handler
+func (h *EventFiberHandler) ExportSuperUserCalendarHandler(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(responses.NewFiberResponse(c, fiber.StatusBadRequest, "Invalid ID format", nil, err.Error()))
+	}
+
+	data, err := h.service.ExportSuperUserCalendar(c.UserContext(), fiberViewerID(c), id)
+	if err != nil {
+		status := responses.ErrorStatus(err)
+		return c.Status(status).JSON(responses.NewFiberResponse(c, status, "Failed to export calendar", nil, err.Error()))
+	}
+
+	c.Set(fiber.HeaderContentType, icsContentType)
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(icsContentDisposition, "calendar"+icsSuffix))
+	return c.Status(fiber.StatusOK).Send(data)
+}
+
+// Update Event handler
+func (h *EventFiberHandler) UpdateEventHandler(c *fiber.Ctx) error {
+	loc, err := parseZone(c.Query("tz"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(responses.NewFiberResponse(c, fiber.StatusBadRequest, "Invalid time zone", nil, err.Error()))
+	}
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(responses.NewFiberResponse(c, fiber.StatusBadRequest, "Invalid ID format", nil, err.Error()))
+	}
+
+	var event types.EventType
+	if err := c.BodyParser(&event); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(responses.NewFiberResponse(c, fiber.StatusBadRequest, "Invalid input", nil, err.Error()))
+	}
+	event.EventID = id
+
+	updatedEvent, err := h.service.UpdateEvent(c.UserContext(), fiberViewerID(c), &event)
+	if err != nil {
+		status := responses.ErrorStatus(err)
+		return c.Status(status).JSON(responses.NewFiberResponse(c, status, "Failed to update Event", nil, err.Error()))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(responses.NewFiberResponse(c, fiber.StatusOK, "Event updated successfully", updatedEvent.In(loc), nil))
+}
+
+// Delete Event handler
+func (h *EventFiberHandler) DeleteEventHandler(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(responses.NewFiberResponse(c, fiber.StatusBadRequest, "Invalid ID format", nil, err.Error()))
+	}
+
+	if err := h.service.DeleteEvent(c.UserContext(), fiberViewerID(c), id); err != nil {
+		status := responses.ErrorStatus(err)
+		return c.Status(status).JSON(responses.NewFiberResponse(c, status, "Failed to delete Event", nil, err.Error()))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(responses.NewFiberResponse(c, fiber.StatusOK, "Event deleted successfully", nil, nil))
+}
+
+// Change Event status handler
+func (h *EventFiberHandler) ChangeEventStatusHandler(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(responses.NewFiberResponse(c, fiber.StatusBadRequest, "Invalid ID format", nil, err.Error()))
+	}
+
+	var request statusRequest
+	if err := c.BodyParser(&request); err != nil || request.Status == "" {
+		message := "status is required"
+		if err != nil {
+			message = err.Error()
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(responses.NewFiberResponse(c, fiber.StatusBadRequest, "Invalid input", nil, message))
+	}
+
+	event, err := h.service.ChangeEventStatus(c.UserContext(), fiberViewerID(c), id, request.Status)
+	if err != nil {
+		status := responses.ErrorStatus(err)
+		return c.Status(status).JSON(responses.NewFiberResponse(c, status, "Failed to change Event status", nil, err.Error()))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(responses.NewFiberResponse(c, fiber.StatusOK, "Event status changed", event, nil))
+}
+
+// List Events handler
+func (h *EventFiberHandler) ListEventsHandler(c *fiber.Ctx) error {
+	loc, err := parseZone(c.Query("tz"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(responses.NewFiberResponse(c, fiber.StatusBadRequest, "Invalid time zone", nil, err.Error()))
+	}
+
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	limit, _ := strconv.Atoi(c.Query("limit", "10"))
+	sortBy := c.Query("sort_by", "date")
+
+	// A from/to window expands recurring events into their occurrences
+	from, to, windowed, err := parseTimeWindow(c.Query("from"), c.Query("to"), loc)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(responses.NewFiberResponse(c, fiber.StatusBadRequest, "Invalid time window", nil, err.Error()))
+	}
+
+	var events []*types.EventType
+	if windowed {
+		events, err = h.service.ListEventOccurrences(c.UserContext(), fiberViewerID(c), "", from, to, page, limit)
+	} else {
+		events, err = h.service.ListEvents(c.UserContext(), fiberViewerID(c), page, limit, sortBy)
+	}
+	if err != nil {
+		status := responses.ErrorStatus(err)
+		return c.Status(status).JSON(responses.NewFiberResponse(c, status, "Failed to list Events", nil, err.Error()))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(responses.NewFiberResponse(c, fiber.StatusOK, "Events retrieved successfully", renderEvents(events, loc), nil))
+}
+
+// Search Events handler
+func (h *EventFiberHandler) SearchEventsHandler(c *fiber.Ctx) error {
+	loc, err := parseZone(c.Query("tz"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(responses.NewFiberResponse(c, fiber.StatusBadRequest, "Invalid time zone", nil, err.Error()))
+	}
+
+	searchQuery := c.Query("q")
+	page, _ := strconv.Atoi(c.Query("page", "1"))
+	limit, _ := strconv.Atoi(c.Query("limit", "10"))
+	sortBy := c.Query("sort_by", "date")
+
+	// A from/to window expands recurring events into their occurrences
+	from, to, windowed, err := parseTimeWindow(c.Query("from"), c.Query("to"), loc)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(responses.NewFiberResponse(c, fiber.StatusBadRequest, "Invalid time window", nil, err.Error()))
+	}
+
+	var events []*types.EventType
+	if windowed {
+		events, err = h.service.ListEventOccurrences(c.UserContext(), fiberViewerID(c), searchQuery, from, to, page, limit)
+	} else {
+		events, err = h.service.SearchEvents(c.UserContext(), fiberViewerID(c), searchQuery, page, limit, sortBy)
+	}
+	if err != nil {
+		status := responses.ErrorStatus(err)
+		return c.Status(status).JSON(responses.NewFiberResponse(c, status, "Failed to search Events", nil, err.Error()))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(responses.NewFiberResponse(c, fiber.StatusOK, "Events retrieved successfully", renderEvents(events, loc), nil))
+}
+
+// Count Events handler
+func (h *EventFiberHandler) CountEventsHandler(c *fiber.Ctx) error {
+	searchQuery := c.Query("q")
+
+	count, err := h.service.CountEvents(c.UserContext(), fiberViewerID(c), searchQuery)
+	if err != nil {
+		status := responses.ErrorStatus(err)
+		return c.Status(status).JSON(responses.NewFiberResponse(c, status, "Failed to count Events", nil, err.Error()))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(responses.NewFiberResponse(c, fiber.StatusOK, "Events counted successfully", fiber.Map{"count": count}, nil))
+}
+
+// List occurrences of a recurring Event handler
+func (h *EventFiberHandler) ListOccurrencesHandler(c *fiber.Ctx) error {
+	loc, err := parseZone(c.Query("tz"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(responses.NewFiberResponse(c, fiber.StatusBadRequest, "Invalid time zone", nil, err.Error()))
+	}
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(responses.NewFiberResponse(c, fiber.StatusBadRequest, "Invalid ID format", nil, err.Error()))
+	}
+
+	from, to, windowed, err := parseTimeWindow(c.Query("from"), c.Query("to"), loc)
+	if err != nil || !windowed {
+		return c.Status(fiber.StatusBadRequest).JSON(responses.NewFiberResponse(c, fiber.StatusBadRequest, "Invalid time window", nil, "from and to are required RFC 3339 times"))
+	}
+
+	occurrences, err := h.service.ListSeriesOccurrences(c.UserContext(), fiberViewerID(c), id, from, to)
+	if err != nil {
+		status := responses.ErrorStatus(err)
+		return c.Status(status).JSON(responses.NewFiberResponse(c, status, "Failed to retrieve occurrences", nil, err.Error()))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(responses.NewFiberResponse(c, fiber.StatusOK, "Occurrences retrieved successfully", renderEvents(occurrences, loc), nil))
+}
+
+// Override a single occurrence of a recurring Event handler
+func (h *EventFiberHandler) OverrideOccurrenceHandler(c *fiber.Ctx) error {
+	loc, err := parseZone(c.Query("tz"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(responses.NewFiberResponse(c, fiber.StatusBadRequest, "Invalid time zone", nil, err.Error()))
+	}
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(responses.NewFiberResponse(c, fiber.StatusBadRequest, "Invalid ID format", nil, err.Error()))
+	}
+
+	recurrenceID, err := time.Parse(time.RFC3339, c.Params("occurrence"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(responses.NewFiberResponse(c, fiber.StatusBadRequest, "Invalid occurrence format", nil, err.Error()))
+	}
+
+	var override types.EventType
+	if err := c.BodyParser(&override); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(responses.NewFiberResponse(c, fiber.StatusBadRequest, "Invalid input", nil, err.Error()))
+	}
+
+	event, err := h.service.OverrideOccurrence(c.UserContext(), fiberViewerID(c), id, recurrenceID, &override)
+	if err != nil {
+		status := responses.ErrorStatus(err)
+		return c.Status(status).JSON(responses.NewFiberResponse(c, status, "Failed to override occurrence", nil, err.Error()))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(responses.NewFiberResponse(c, fiber.StatusOK, "Occurrence overridden successfully", event.In(loc), nil))
+}
+
+// Cancel a single occurrence of a recurring Event handler
+func (h *EventFiberHandler) CancelOccurrenceHandler(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(responses.NewFiberResponse(c, fiber.StatusBadRequest, "Invalid ID format", nil, err.Error()))
+	}
+
+	recurrenceID, err := time.Parse(time.RFC3339, c.Params("occurrence"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(responses.NewFiberResponse(c, fiber.StatusBadRequest, "Invalid occurrence format", nil, err.Error()))
+	}
+
+	if err := h.service.CancelOccurrence(c.UserContext(), fiberViewerID(c), id, recurrenceID); err != nil {
+		status := responses.ErrorStatus(err)
+		return c.Status(status).JSON(responses.NewFiberResponse(c, status, "Failed to cancel occurrence", nil, err.Error()))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(responses.NewFiberResponse(c, fiber.StatusOK, "Occurrence cancelled", nil, nil))
+}
+
+// Register attendee handler
+func (h *EventFiberHandler) RegisterAttendeeHandler(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(responses.NewFiberResponse(c, fiber.StatusBadRequest, "Invalid ID format", nil, err.Error()))
+	}
+
+	// SuperUsers only ever register themselves
+	result, err := h.service.RegisterAttendee(c.UserContext(), id, fiberViewerID(c))
+	if err != nil {
+		status := responses.ErrorStatus(err)
+		return c.Status(status).JSON(responses.NewFiberResponse(c, status, "Failed to register attendee", nil, err.Error()))
+	}
+
+	if result.Status == services.RegistrationStatusWaitlisted {
+		return c.Status(fiber.StatusAccepted).JSON(responses.NewFiberResponse(c, fiber.StatusAccepted, "Event is full, added to the waitlist", result, nil))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(responses.NewFiberResponse(c, fiber.StatusOK, "Attendee registered", result, nil))
+}
+
+// Unregister attendee handler
+func (h *EventFiberHandler) UnregisterAttendeeHandler(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(responses.NewFiberResponse(c, fiber.StatusBadRequest, "Invalid ID format", nil, err.Error()))
+	}
+
+	attendeeID, err := uuid.Parse(c.Params("attendeeId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(responses.NewFiberResponse(c, fiber.StatusBadRequest, "Invalid attendee ID format", nil, err.Error()))
+	}
+
+	if err := h.service.UnregisterAttendee(c.UserContext(), fiberViewerID(c), id, attendeeID); err != nil {
+		status := responses.ErrorStatus(err)
+		return c.Status(status).JSON(responses.NewFiberResponse(c, status, "Failed to unregister attendee", nil, err.Error()))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(responses.NewFiberResponse(c, fiber.StatusOK, "Attendee unregistered", nil, nil))
+}
+
+// List waitlist handler
+func (h *EventFiberHandler) ListWaitlistHandler(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(responses.NewFiberResponse(c, fiber.StatusBadRequest, "Invalid ID format", nil, err.Error()))
+	}
+
+	entries, err := h.service.ListWaitlist(c.UserContext(), fiberViewerID(c), id)
+	if err != nil {
+		status := responses.ErrorStatus(err)
+		return c.Status(status).JSON(responses.NewFiberResponse(c, status, "Failed to retrieve waitlist", nil, err.Error()))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(responses.NewFiberResponse(c, fiber.StatusOK, "Waitlist retrieved successfully", entries, nil))
+}
+
+// Get waitlist position handler
+func (h *EventFiberHandler) GetWaitlistPositionHandler(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(responses.NewFiberResponse(c, fiber.StatusBadRequest, "Invalid ID format", nil, err.Error()))
+	}
+
+	userID, err := uuid.Parse(c.Params("userId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(responses.NewFiberResponse(c, fiber.StatusBadRequest, "Invalid user ID format", nil, err.Error()))
+	}
+
+	entry, err := h.service.GetWaitlistPosition(c.UserContext(), fiberViewerID(c), id, userID)
+	if err != nil {
+		status := responses.ErrorStatus(err)
+		return c.Status(status).JSON(responses.NewFiberResponse(c, status, "Failed to retrieve waitlist position", nil, err.Error()))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(responses.NewFiberResponse(c, fiber.StatusOK, "Waitlist position retrieved successfully", entry, nil))
+}
+
+// Leave waitlist handler
+func (h *EventFiberHandler) LeaveWaitlistHandler(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(responses.NewFiberResponse(c, fiber.StatusBadRequest, "Invalid ID format", nil, err.Error()))
+	}
+
+	userID, err := uuid.Parse(c.Params("userId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(responses.NewFiberResponse(c, fiber.StatusBadRequest, "Invalid user ID format", nil, err.Error()))
+	}
+
+	if err := h.service.LeaveWaitlist(c.UserContext(), fiberViewerID(c), id, userID); err != nil {
+		status := responses.ErrorStatus(err)
+		return c.Status(status).JSON(responses.NewFiberResponse(c, status, "Failed to leave waitlist", nil, err.Error()))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(responses.NewFiberResponse(c, fiber.StatusOK, "Left the waitlist", nil, nil))
+}
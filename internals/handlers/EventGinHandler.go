@@ -0,0 +1,579 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lordofthemind/EventifyGo/internals/responses"
+	"github.com/lordofthemind/EventifyGo/internals/services"
+	"github.com/lordofthemind/EventifyGo/internals/types"
+)
+
+type EventGinHandler struct {
+	service services.EventServiceInterface
+}
+
+func NewEventGinHandler(service services.EventServiceInterface) *EventGinHandler {
+	return &EventGinHandler{service: service}
+}
+
+// Create Event handler
+func (h *EventGinHandler) CreateEventHandler(c *gin.Context) {
+	loc, err := parseZone(c.Query("tz"))
+	if err != nil {
+		response := responses.NewGinResponse(c, http.StatusBadRequest, "Invalid time zone", nil, err.Error())
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	var event types.EventType
+	if err := c.ShouldBindJSON(&event); err != nil {
+		response := responses.NewGinResponse(c, http.StatusBadRequest, "Invalid input", nil, err.Error())
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	createdEvent, err := h.service.CreateEvent(c.Request.Context(), ginViewerID(c), &event)
+	if err != nil {
+		status := responses.ErrorStatus(err)
+		response := responses.NewGinResponse(c, status, "Failed to create Event", nil, err.Error())
+		c.JSON(status, response)
+		return
+	}
+
+	response := responses.NewGinResponse(c, http.StatusCreated, "Event created successfully", createdEvent.In(loc), nil)
+	c.JSON(http.StatusCreated, response)
+}
+
+// Import Events handler, takes a multipart "file" upload in iCalendar or CSV format. The
+// Events are organized by the authenticated SuperUser.
+func (h *EventGinHandler) ImportEventsHandler(c *gin.Context) {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		response := responses.NewGinResponse(c, http.StatusBadRequest, "Invalid input", nil, err.Error())
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	capacity, err := parseImportCapacity(c.PostForm("capacity"))
+	if err != nil {
+		response := responses.NewGinResponse(c, http.StatusBadRequest, "Invalid input", nil, err.Error())
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+	dryRun, _ := strconv.ParseBool(c.DefaultQuery("dryRun", "false"))
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		response := responses.NewGinResponse(c, http.StatusBadRequest, "Failed to read file", nil, err.Error())
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+	defer file.Close()
+
+	result, err := h.service.ImportEvents(c.Request.Context(), file, services.ImportOptions{
+		Format:          importFormat(c.PostForm("format"), fileHeader.Filename),
+		OrganizerID:     ginViewerID(c),
+		DefaultCapacity: capacity,
+		DryRun:          dryRun,
+	})
+	if err != nil {
+		status := responses.ErrorStatus(err)
+		response := responses.NewGinResponse(c, status, "Failed to import Events", nil, err.Error())
+		c.JSON(status, response)
+		return
+	}
+
+	message := "Events imported"
+	if dryRun {
+		message = "Import preview"
+	}
+	response := responses.NewGinResponse(c, http.StatusOK, message, result, nil)
+	c.JSON(http.StatusOK, response)
+}
+
+// Get Event by ID handler
+func (h *EventGinHandler) GetEventByIDHandler(c *gin.Context) {
+	loc, err := parseZone(c.Query("tz"))
+	if err != nil {
+		response := responses.NewGinResponse(c, http.StatusBadRequest, "Invalid time zone", nil, err.Error())
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	// Gin cannot route /events/:id.ics separately, so the suffix is handled here
+	param, isICS := splitICSParam(c.Param("id"))
+	id, err := uuid.Parse(param)
+	if err != nil {
+		response := responses.NewGinResponse(c, http.StatusBadRequest, "Invalid ID format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	if isICS {
+		h.exportEventCalendar(c, id)
+		return
+	}
+
+	event, err := h.service.GetEventByID(c.Request.Context(), ginViewerID(c), id)
+	if err != nil {
+		status := responses.ErrorStatus(err)
+		response := responses.NewGinResponse(c, status, "Failed to retrieve Event", nil, err.Error())
+		c.JSON(status, response)
+		return
+	}
+
+	response := responses.NewGinResponse(c, http.StatusOK, "Event retrieved successfully", event.In(loc), nil)
+	c.JSON(http.StatusOK, response)
+}
+
+// Helper function to serve an Event as an iCalendar file
+func (h *EventGinHandler) exportEventCalendar(c *gin.Context, id uuid.UUID) {
+	data, err := h.service.ExportEventCalendar(c.Request.Context(), ginViewerID(c), id)
+	if err != nil {
+		status := responses.ErrorStatus(err)
+		response := responses.NewGinResponse(c, status, "Failed to export Event", nil, err.Error())
+		c.JSON(status, response)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(icsContentDisposition, id.String()+icsSuffix))
+	c.Data(http.StatusOK, icsContentType, data)
+}
+
+// Export SuperUser calendar feed handler
+func (h *EventGinHandler) ExportSuperUserCalendarHandler(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response := responses.NewGinResponse(c, http.StatusBadRequest, "Invalid ID format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	data, err := h.service.ExportSuperUserCalendar(c.Request.Context(), ginViewerID(c), id)
+	if err != nil {
+		status := responses.ErrorStatus(err)
+		response := responses.NewGinResponse(c, status, "Failed to export calendar", nil, err.Error())
+		c.JSON(status, response)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(icsContentDisposition, "calendar"+icsSuffix))
+	c.Data(http.StatusOK, icsContentType, data)
+}
+
+// Update Event handler
+func (h *EventGinHandler) UpdateEventHandler(c *gin.Context) {
+	loc, err := parseZone(c.Query("tz"))
+	if err != nil {
+		response := responses.NewGinResponse(c, http.StatusBadRequest, "Invalid time zone", nil, err.Error())
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response := responses.NewGinResponse(c, http.StatusBadRequest, "Invalid ID format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	var event types.EventType
+	if err := c.ShouldBindJSON(&event); err != nil {
+		response := responses.NewGinResponse(c, http.StatusBadRequest, "Invalid input", nil, err.Error())
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+	event.EventID = id
+
+	updatedEvent, err := h.service.UpdateEvent(c.Request.Context(), ginViewerID(c), &event)
+	if err != nil {
+		status := responses.ErrorStatus(err)
+		response := responses.NewGinResponse(c, status, "Failed to update Event", nil, err.Error())
+		c.JSON(status, response)
+		return
+	}
+
+	response := responses.NewGinResponse(c, http.StatusOK, "Event updated successfully", updatedEvent.In(loc), nil)
+	c.JSON(http.StatusOK, response)
+}
+
+// Delete Event handler
+func (h *EventGinHandler) DeleteEventHandler(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response := responses.NewGinResponse(c, http.StatusBadRequest, "Invalid ID format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	if err := h.service.DeleteEvent(c.Request.Context(), ginViewerID(c), id); err != nil {
+		status := responses.ErrorStatus(err)
+		response := responses.NewGinResponse(c, status, "Failed to delete Event", nil, err.Error())
+		c.JSON(status, response)
+		return
+	}
+
+	response := responses.NewGinResponse(c, http.StatusOK, "Event deleted successfully", nil, nil)
+	c.JSON(http.StatusOK, response)
+}
+
+// Change Event status handler
+func (h *EventGinHandler) ChangeEventStatusHandler(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response := responses.NewGinResponse(c, http.StatusBadRequest, "Invalid ID format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	var request statusRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		response := responses.NewGinResponse(c, http.StatusBadRequest, "Invalid input", nil, err.Error())
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	event, err := h.service.ChangeEventStatus(c.Request.Context(), ginViewerID(c), id, request.Status)
+	if err != nil {
+		status := responses.ErrorStatus(err)
+		response := responses.NewGinResponse(c, status, "Failed to change Event status", nil, err.Error())
+		c.JSON(status, response)
+		return
+	}
+
+	response := responses.NewGinResponse(c, http.StatusOK, "Event status changed", event, nil)
+	c.JSON(http.StatusOK, response)
+}
+
+// List Events handler
+func (h *EventGinHandler) ListEventsHandler(c *gin.Context) {
+	loc, err := parseZone(c.Query("tz"))
+	if err != nil {
+		response := responses.NewGinResponse(c, http.StatusBadRequest, "Invalid time zone", nil, err.Error())
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	sortBy := c.DefaultQuery("sortBy", "date")
+
+	// A from/to window expands recurring events into their occurrences
+	from, to, windowed, err := parseTimeWindow(c.Query("from"), c.Query("to"), loc)
+	if err != nil {
+		response := responses.NewGinResponse(c, http.StatusBadRequest, "Invalid time window", nil, err.Error())
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	var events []*types.EventType
+	if windowed {
+		events, err = h.service.ListEventOccurrences(c.Request.Context(), ginViewerID(c), "", from, to, page, limit)
+	} else {
+		events, err = h.service.ListEvents(c.Request.Context(), ginViewerID(c), page, limit, sortBy)
+	}
+	if err != nil {
+		status := responses.ErrorStatus(err)
+		response := responses.NewGinResponse(c, status, "Failed to list Events", nil, err.Error())
+		c.JSON(status, response)
+		return
+	}
+
+	response := responses.NewGinResponse(c, http.StatusOK, "Events retrieved successfully", renderEvents(events, loc), nil)
+	c.JSON(http.StatusOK, response)
+}
+
+// Search Events handler
+func (h *EventGinHandler) SearchEventsHandler(c *gin.Context) {
+	loc, err := parseZone(c.Query("tz"))
+	if err != nil {
+		response := responses.NewGinResponse(c, http.StatusBadRequest, "Invalid time zone", nil, err.Error())
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	searchQuery := c.Query("q")
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	sortBy := c.DefaultQuery("sortBy", "date")
+
+	// A from/to window expands recurring events into their occurrences
+	from, to, windowed, err := parseTimeWindow(c.Query("from"), c.Query("to"), loc)
+	if err != nil {
+		response := responses.NewGinResponse(c, http.StatusBadRequest, "Invalid time window", nil, err.Error())
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	var events []*types.EventType
+	if windowed {
+		events, err = h.service.ListEventOccurrences(c.Request.Context(), ginViewerID(c), searchQuery, from, to, page, limit)
+	} else {
+		events, err = h.service.SearchEvents(c.Request.Context(), ginViewerID(c), searchQuery, page, limit, sortBy)
+	}
+	if err != nil {
+		status := responses.ErrorStatus(err)
+		response := responses.NewGinResponse(c, status, "Failed to search Events", nil, err.Error())
+		c.JSON(status, response)
+		return
+	}
+
+	response := responses.NewGinResponse(c, http.StatusOK, "Events retrieved successfully", renderEvents(events, loc), nil)
+	c.JSON(http.StatusOK, response)
+}
+
+// Count Events handler
+func (h *EventGinHandler) CountEventsHandler(c *gin.Context) {
+	searchQuery := c.Query("q")
+
+	count, err := h.service.CountEvents(c.Request.Context(), ginViewerID(c), searchQuery)
+	if err != nil {
+		status := responses.ErrorStatus(err)
+		response := responses.NewGinResponse(c, status, "Failed to count Events", nil, err.Error())
+		c.JSON(status, response)
+		return
+	}
+
+	response := responses.NewGinResponse(c, http.StatusOK, "Events counted successfully", gin.H{"count": count}, nil)
+	c.JSON(http.StatusOK, response)
+}
+
+// List occurrences of a recurring Event handler
+func (h *EventGinHandler) ListOccurrencesHandler(c *gin.Context) {
+	loc, err := parseZone(c.Query("tz"))
+	if err != nil {
+		response := responses.NewGinResponse(c, http.StatusBadRequest, "Invalid time zone", nil, err.Error())
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response := responses.NewGinResponse(c, http.StatusBadRequest, "Invalid ID format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	from, to, windowed, err := parseTimeWindow(c.Query("from"), c.Query("to"), loc)
+	if err != nil || !windowed {
+		response := responses.NewGinResponse(c, http.StatusBadRequest, "Invalid time window", nil, "from and to are required RFC 3339 times")
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	occurrences, err := h.service.ListSeriesOccurrences(c.Request.Context(), ginViewerID(c), id, from, to)
+	if err != nil {
+		status := responses.ErrorStatus(err)
+		response := responses.NewGinResponse(c, status, "Failed to retrieve occurrences", nil, err.Error())
+		c.JSON(status, response)
+		return
+	}
+
+	response := responses.NewGinResponse(c, http.StatusOK, "Occurrences retrieved successfully", renderEvents(occurrences, loc), nil)
+	c.JSON(http.StatusOK, response)
+}
+
+// Override a single occurrence of a recurring Event handler
+func (h *EventGinHandler) OverrideOccurrenceHandler(c *gin.Context) {
+	loc, err := parseZone(c.Query("tz"))
+	if err != nil {
+		response := responses.NewGinResponse(c, http.StatusBadRequest, "Invalid time zone", nil, err.Error())
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response := responses.NewGinResponse(c, http.StatusBadRequest, "Invalid ID format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	recurrenceID, err := time.Parse(time.RFC3339, c.Param("occurrence"))
+	if err != nil {
+		response := responses.NewGinResponse(c, http.StatusBadRequest, "Invalid occurrence format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	var override types.EventType
+	if err := c.ShouldBindJSON(&override); err != nil {
+		response := responses.NewGinResponse(c, http.StatusBadRequest, "Invalid input", nil, err.Error())
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	event, err := h.service.OverrideOccurrence(c.Request.Context(), ginViewerID(c), id, recurrenceID, &override)
+	if err != nil {
+		status := responses.ErrorStatus(err)
+		response := responses.NewGinResponse(c, status, "Failed to override occurrence", nil, err.Error())
+		c.JSON(status, response)
+		return
+	}
+
+	response := responses.NewGinResponse(c, http.StatusOK, "Occurrence overridden successfully", event.In(loc), nil)
+	c.JSON(http.StatusOK, response)
+}
+
+// Cancel a single occurrence of a recurring Event handler
+func (h *EventGinHandler) CancelOccurrenceHandler(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response := responses.NewGinResponse(c, http.StatusBadRequest, "Invalid ID format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	recurrenceID, err := time.Parse(time.RFC3339, c.Param("occurrence"))
+	if err != nil {
+		response := responses.NewGinResponse(c, http.StatusBadRequest, "Invalid occurrence format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	if err := h.service.CancelOccurrence(c.Request.Context(), ginViewerID(c), id, recurrenceID); err != nil {
+		status := responses.ErrorStatus(err)
+		response := responses.NewGinResponse(c, status, "Failed to cancel occurrence", nil, err.Error())
+		c.JSON(status, response)
+		return
+	}
+
+	response := responses.NewGinResponse(c, http.StatusOK, "Occurrence cancelled", nil, nil)
+	c.JSON(http.StatusOK, response)
+}
+
+// Register attendee handler
+func (h *EventGinHandler) RegisterAttendeeHandler(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response := responses.NewGinResponse(c, http.StatusBadRequest, "Invalid ID format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	// SuperUsers only ever register themselves
+	result, err := h.service.RegisterAttendee(c.Request.Context(), id, ginViewerID(c))
+	if err != nil {
+		status := responses.ErrorStatus(err)
+		response := responses.NewGinResponse(c, status, "Failed to register attendee", nil, err.Error())
+		c.JSON(status, response)
+		return
+	}
+
+	if result.Status == services.RegistrationStatusWaitlisted {
+		response := responses.NewGinResponse(c, http.StatusAccepted, "Event is full, added to the waitlist", result, nil)
+		c.JSON(http.StatusAccepted, response)
+		return
+	}
+
+	response := responses.NewGinResponse(c, http.StatusOK, "Attendee registered", result, nil)
+	c.JSON(http.StatusOK, response)
+}
+
+// Unregister attendee handler
+func (h *EventGinHandler) UnregisterAttendeeHandler(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response := responses.NewGinResponse(c, http.StatusBadRequest, "Invalid ID format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	attendeeID, err := uuid.Parse(c.Param("attendeeId"))
+	if err != nil {
+		response := responses.NewGinResponse(c, http.StatusBadRequest, "Invalid attendee ID format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	if err := h.service.UnregisterAttendee(c.Request.Context(), ginViewerID(c), id, attendeeID); err != nil {
+		status := responses.ErrorStatus(err)
+		response := responses.NewGinResponse(c, status, "Failed to unregister attendee", nil, err.Error())
+		c.JSON(status, response)
+		return
+	}
+
+	response := responses.NewGinResponse(c, http.StatusOK, "Attendee unregistered", nil, nil)
+	c.JSON(http.StatusOK, response)
+}
+
+// List waitlist handler
+func (h *EventGinHandler) ListWaitlistHandler(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response := responses.NewGinResponse(c, http.StatusBadRequest, "Invalid ID format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	entries, err := h.service.ListWaitlist(c.Request.Context(), ginViewerID(c), id)
+	if err != nil {
+		status := responses.ErrorStatus(err)
+		response := responses.NewGinResponse(c, status, "Failed to retrieve waitlist", nil, err.Error())
+		c.JSON(status, response)
+		return
+	}
+
+	response := responses.NewGinResponse(c, http.StatusOK, "Waitlist retrieved successfully", entries, nil)
+	c.JSON(http.StatusOK, response)
+}
+
+// Get waitlist position handler
+func (h *EventGinHandler) GetWaitlistPositionHandler(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response := responses.NewGinResponse(c, http.StatusBadRequest, "Invalid ID format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	userID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		response := responses.NewGinResponse(c, http.StatusBadRequest, "Invalid user ID format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	entry, err := h.service.GetWaitlistPosition(c.Request.Context(), ginViewerID(c), id, userID)
+	if err != nil {
+		status := responses.ErrorStatus(err)
+		response := responses.NewGinResponse(c, status, "Failed to retrieve waitlist position", nil, err.Error())
+		c.JSON(status, response)
+		return
+	}
+
+	response := responses.NewGinResponse(c, http.StatusOK, "Waitlist position retrieved successfully", entry, nil)
+	c.JSON(http.StatusOK, response)
+}
+
+// Leave waitlist handler
+func (h *EventGinHandler) LeaveWaitlistHandler(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response := responses.NewGinResponse(c, http.StatusBadRequest, "Invalid ID format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	userID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		response := responses.NewGinResponse(c, http.StatusBadRequest, "Invalid user ID format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	if err := h.service.LeaveWaitlist(c.Request.Context(), ginViewerID(c), id, userID); err != nil {
+		status := responses.ErrorStatus(err)
+		response := responses.NewGinResponse(c, status, "Failed to leave waitlist", nil, err.Error())
+		c.JSON(status, response)
+		return
+	}
+
+	response := responses.NewGinResponse(c, http.StatusOK, "Left the waitlist", nil, nil)
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/lordofthemind/EventifyGo/internals/types"
+	"github.com/lordofthemind/EventifyGo/pkgs/middlewares"
+)
+
+const (
+	icsSuffix             = ".ics"
+	icsContentType        = "text/calendar; charset=utf-8"
+	icsContentDisposition = "attachment; filename=%q"
+)
+
+// splitICSParam strips the ".ics" suffix from a path parameter and reports whether it was present.
+func splitICSParam(param string) (string, bool) {
+	if strings.HasSuffix(param, icsSuffix) {
+		return strings.TrimSuffix(param, icsSuffix), true
+	}
+	return param, false
+}
+
+// parseImportCapacity reads the optional default capacity of an import upload
+func parseImportCapacity(capacityParam string) (int, error) {
+	if capacityParam == "" {
+		return 0, nil
+	}
+	capacity, err := strconv.Atoi(capacityParam)
+	if err != nil {
+		return 0, fmt.Errorf("invalid capacity: %w", err)
+	}
+	return capacity, nil
+}
+
+// importFormat picks the import format from an explicit value or the uploaded file name
+func importFormat(format, filename string) string {
+	if format != "" {
+		return strings.ToLower(format)
+	}
+	return strings.TrimPrefix(strings.ToLower(filepath.Ext(filename)), ".")
+}
+
+// statusRequest is the body of a lifecycle status change
+type statusRequest struct {
+	Status string `json:"status" binding:"required"`
+}
+
+// ginViewerID returns the authenticated SuperUser of a Gin request, uuid.Nil for anonymous callers
+func ginViewerID(c *gin.Context) uuid.UUID {
+	if id, ok := c.Get(middlewares.SuperUserIDKey); ok {
+		if viewerID, ok := id.(uuid.UUID); ok {
+			return viewerID
+		}
+	}
+	return uuid.Nil
+}
+
+// fiberViewerID returns the authenticated SuperUser of a Fiber request, uuid.Nil for anonymous callers
+func fiberViewerID(c *fiber.Ctx) uuid.UUID {
+	if viewerID, ok := c.Locals(middlewares.SuperUserIDKey).(uuid.UUID); ok {
+		return viewerID
+	}
+	return uuid.Nil
+}
+
+// parseZone reads the optional "tz" query parameter, an IANA time zone to render times in.
+// Without it every event is rendered in its own zone.
+func parseZone(name string) (*time.Location, error) {
+	if name == "" {
+		return nil, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil || name == "Local" {
+		return nil, fmt.Errorf("unknown time zone %q", name)
+	}
+	return loc, nil
+}
+
+// renderEvents returns copies of the events with their times rendered in loc
+func renderEvents(events []*types.EventType, loc *time.Location) []*types.EventType {
+	rendered := make([]*types.EventType, 0, len(events))
+	for _, event := range events {
+		rendered = append(rendered, event.In(loc))
+	}
+	return rendered
+}
+
+// parseTimeWindow parses the optional "from" and "to" query parameters, RFC 3339 times or
+// YYYY-MM-DD dates. Dates are days in loc (UTC when nil) and "to" includes its whole day, so
+// the window follows the local calendar across DST changes.
+// ok is false when neither is given, both are required otherwise.
+func parseTimeWindow(fromParam, toParam string, loc *time.Location) (from, to time.Time, ok bool, err error) {
+	if fromParam == "" && toParam == "" {
+		return time.Time{}, time.Time{}, false, nil
+	}
+	if fromParam == "" || toParam == "" {
+		return time.Time{}, time.Time{}, false, errors.New("both from and to are required")
+	}
+
+	from, err = parseWindowBound(fromParam, loc, false)
+	if err != nil {
+		return time.Time{}, time.Time{}, false, fmt.Errorf("invalid from: %w", err)
+	}
+	to, err = parseWindowBound(toParam, loc, true)
+	if err != nil {
+		return time.Time{}, time.Time{}, false, fmt.Errorf("invalid to: %w", err)
+	}
+	return from, to, true, nil
+}
+
+// parseWindowBound parses a single window bound, a date used as the end covers that day
+func parseWindowBound(value string, loc *time.Location, end bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+	day, err := time.ParseInLocation(time.DateOnly, value, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expected an RFC 3339 time or a YYYY-MM-DD date, got %q", value)
+	}
+	if end {
+		// AddDate works on the calendar, a 23 or 25 hour day still ends at midnight
+		day = day.AddDate(0, 0, 1)
+	}
+	return day, nil
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/lordofthemind/EventifyGo/internals/responses"
+	"github.com/lordofthemind/EventifyGo/internals/services"
+)
+
+type LockoutFiberHandler struct {
+	service services.LockoutServiceInterface
+}
+
+func NewLockoutFiberHandler(service services.LockoutServiceInterface) *LockoutFiberHandler {
+	return &LockoutFiberHandler{service: service}
+}
+
+// Get the failed logins and lockout of SuperUser
+func (h *LockoutFiberHandler) GetSuperUserLockoutHandler(c *fiber.Ctx) error {
+	idParam := c.Params("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(responses.NewFiberResponse(c, fiber.StatusBadRequest, "Invalid ID format", nil, err.Error()))
+	}
+
+	lockout, err := h.service.GetSuperUserLockout(c.UserContext(), id)
+	if err != nil {
+		status := responses.ErrorStatus(err)
+		return c.Status(status).JSON(responses.NewFiberResponse(c, status, "Failed to retrieve lockout", nil, err.Error()))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(responses.NewFiberResponse(c, fiber.StatusOK, "Lockout retrieved successfully", lockout, nil))
+}
+
+// Unlock SuperUser, clearing their failed logins
+func (h *LockoutFiberHandler) UnlockSuperUserHandler(c *fiber.Ctx) error {
+	idParam := c.Params("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(responses.NewFiberResponse(c, fiber.StatusBadRequest, "Invalid ID format", nil, err.Error()))
+	}
+
+	if err := h.service.UnlockSuperUser(c.UserContext(), id); err != nil {
+		status := responses.ErrorStatus(err)
+		return c.Status(status).JSON(responses.NewFiberResponse(c, status, "Failed to unlock SuperUser", nil, err.Error()))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(responses.NewFiberResponse(c, fiber.StatusOK, "SuperUser unlocked", nil, nil))
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lordofthemind/EventifyGo/internals/responses"
+	"github.com/lordofthemind/EventifyGo/internals/services"
+)
+
+type LockoutGinHandler struct {
+	service services.LockoutServiceInterface
+}
+
+func NewLockoutGinHandler(service services.LockoutServiceInterface) *LockoutGinHandler {
+	return &LockoutGinHandler{service: service}
+}
+
+// Get the failed logins and lockout of SuperUser
+func (h *LockoutGinHandler) GetSuperUserLockoutHandler(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		response := responses.NewGinResponse(c, http.StatusBadRequest, "Invalid ID format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	lockout, err := h.service.GetSuperUserLockout(c.Request.Context(), id)
+	if err != nil {
+		status := responses.ErrorStatus(err)
+		response := responses.NewGinResponse(c, status, "Failed to retrieve lockout", nil, err.Error())
+		c.JSON(status, response)
+		return
+	}
+
+	response := responses.NewGinResponse(c, http.StatusOK, "Lockout retrieved successfully", lockout, nil)
+	c.JSON(http.StatusOK, response)
+}
+
+// Unlock SuperUser, clearing their failed logins
+func (h *LockoutGinHandler) UnlockSuperUserHandler(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		response := responses.NewGinResponse(c, http.StatusBadRequest, "Invalid ID format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	if err := h.service.UnlockSuperUser(c.Request.Context(), id); err != nil {
+		status := responses.ErrorStatus(err)
+		response := responses.NewGinResponse(c, status, "Failed to unlock SuperUser", nil, err.Error())
+		c.JSON(status, response)
+		return
+	}
+
+	response := responses.NewGinResponse(c, http.StatusOK, "SuperUser unlocked", nil, nil)
+	c.JSON(http.StatusOK, response)
+}
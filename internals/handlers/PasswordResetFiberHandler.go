@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/lordofthemind/EventifyGo/internals/responses"
+	"github.com/lordofthemind/EventifyGo/internals/services"
+)
+
+type PasswordResetFiberHandler struct {
+	service services.PasswordResetServiceInterface
+}
+
+func NewPasswordResetFiberHandler(service services.PasswordResetServiceInterface) *PasswordResetFiberHandler {
+	return &PasswordResetFiberHandler{service: service}
+}
+
+// Request a password reset, the token is delivered out of band and never returned here
+func (h *PasswordResetFiberHandler) RequestPasswordResetHandler(c *fiber.Ctx) error {
+	var request passwordResetRequest
+	if err := c.BodyParser(&request); err != nil || request.Email == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(responses.NewFiberResponse(c, fiber.StatusBadRequest, "Invalid input", nil, "Email is missing or invalid"))
+	}
+
+	if err := h.service.RequestPasswordReset(c.UserContext(), request.Email); err != nil {
+		status := responses.ErrorStatus(err)
+		return c.Status(status).JSON(responses.NewFiberResponse(c, status, "Failed to request password reset", nil, err.Error()))
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(responses.NewFiberResponse(c, fiber.StatusAccepted, "If the account exists, a reset token has been sent", nil, nil))
+}
+
+// Confirm a password reset with the token and a new password
+func (h *PasswordResetFiberHandler) ConfirmPasswordResetHandler(c *fiber.Ctx) error {
+	var request passwordResetConfirmRequest
+	if err := c.BodyParser(&request); err != nil || request.Token == "" || request.NewPassword == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(responses.NewFiberResponse(c, fiber.StatusBadRequest, "Invalid input", nil, "Token or new password is missing"))
+	}
+
+	if err := h.service.ConfirmPasswordReset(c.UserContext(), request.Token, request.NewPassword); err != nil {
+		status := responses.ErrorStatus(err)
+		return c.Status(status).JSON(responses.NewFiberResponse(c, status, "Failed to reset password", nil, err.Error()))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(responses.NewFiberResponse(c, fiber.StatusOK, "Password reset successfully", nil, nil))
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lordofthemind/EventifyGo/internals/responses"
+	"github.com/lordofthemind/EventifyGo/internals/services"
+)
+
+type PasswordResetGinHandler struct {
+	service services.PasswordResetServiceInterface
+}
+
+func NewPasswordResetGinHandler(service services.PasswordResetServiceInterface) *PasswordResetGinHandler {
+	return &PasswordResetGinHandler{service: service}
+}
+
+// Request a password reset, the token is delivered out of band and never returned here
+func (h *PasswordResetGinHandler) RequestPasswordResetHandler(c *gin.Context) {
+	var request passwordResetRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		response := responses.NewGinResponse(c, http.StatusBadRequest, "Invalid input", nil, err.Error())
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	if err := h.service.RequestPasswordReset(c.Request.Context(), request.Email); err != nil {
+		status := responses.ErrorStatus(err)
+		response := responses.NewGinResponse(c, status, "Failed to request password reset", nil, err.Error())
+		c.JSON(status, response)
+		return
+	}
+
+	response := responses.NewGinResponse(c, http.StatusAccepted, "If the account exists, a reset token has been sent", nil, nil)
+	c.JSON(http.StatusAccepted, response)
+}
+
+// Confirm a password reset with the token and a new password
+func (h *PasswordResetGinHandler) ConfirmPasswordResetHandler(c *gin.Context) {
+	var request passwordResetConfirmRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		response := responses.NewGinResponse(c, http.StatusBadRequest, "Invalid input", nil, err.Error())
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	if err := h.service.ConfirmPasswordReset(c.Request.Context(), request.Token, request.NewPassword); err != nil {
+		status := responses.ErrorStatus(err)
+		response := responses.NewGinResponse(c, status, "Failed to reset password", nil, err.Error())
+		c.JSON(status, response)
+		return
+	}
+
+	response := responses.NewGinResponse(c, http.StatusOK, "Password reset successfully", nil, nil)
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/lordofthemind/EventifyGo/internals/responses"
+	"github.com/lordofthemind/EventifyGo/internals/services"
+	"github.com/lordofthemind/EventifyGo/pkgs/middlewares"
+)
+
+type SessionFiberHandler struct {
+	service services.SessionServiceInterface
+}
+
+func NewSessionFiberHandler(service services.SessionServiceInterface) *SessionFiberHandler {
+	return &SessionFiberHandler{service: service}
+}
+
+// List the active sessions of SuperUser, marking the one of the request
+func (h *SessionFiberHandler) ListSessionsHandler(c *fiber.Ctx) error {
+	idParam := c.Params("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(responses.NewFiberResponse(c, fiber.StatusBadRequest, "Invalid ID format", nil, err.Error()))
+	}
+
+	sessions, err := h.service.ListSessions(c.UserContext(), id)
+	if err != nil {
+		status := responses.ErrorStatus(err)
+		return c.Status(status).JSON(responses.NewFiberResponse(c, status, "Failed to retrieve sessions", nil, err.Error()))
+	}
+
+	currentID := c.Locals(middlewares.SessionIDKey)
+	for _, session := range sessions {
+		session.Current = session.ID == currentID
+	}
+
+	return c.Status(fiber.StatusOK).JSON(responses.NewFiberResponse(c, fiber.StatusOK, "Sessions retrieved successfully", sessions, nil))
+}
+
+// Revoke a session of SuperUser
+func (h *SessionFiberHandler) RevokeSessionHandler(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(responses.NewFiberResponse(c, fiber.StatusBadRequest, "Invalid ID format", nil, err.Error()))
+	}
+	sessionID, err := uuid.Parse(c.Params("sessionID"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(responses.NewFiberResponse(c, fiber.StatusBadRequest, "Invalid session ID format", nil, err.Error()))
+	}
+
+	if err := h.service.RevokeSession(c.UserContext(), id, sessionID); err != nil {
+		status := responses.ErrorStatus(err)
+		return c.Status(status).JSON(responses.NewFiberResponse(c, status, "Failed to revoke session", nil, err.Error()))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(responses.NewFiberResponse(c, fiber.StatusOK, "Session revoked successfully", nil, nil))
+}
+
+// Revoke every session of SuperUser
+func (h *SessionFiberHandler) RevokeAllSessionsHandler(c *fiber.Ctx) error {
+	idParam := c.Params("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(responses.NewFiberResponse(c, fiber.StatusBadRequest, "Invalid ID format", nil, err.Error()))
+	}
+
+	if err := h.service.RevokeAllSessions(c.UserContext(), id); err != nil {
+		status := responses.ErrorStatus(err)
+		return c.Status(status).JSON(responses.NewFiberResponse(c, status, "Failed to revoke sessions", nil, err.Error()))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(responses.NewFiberResponse(c, fiber.StatusOK, "Sessions revoked successfully", nil, nil))
+}
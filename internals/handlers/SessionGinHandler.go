@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lordofthemind/EventifyGo/internals/responses"
+	"github.com/lordofthemind/EventifyGo/internals/services"
+	"github.com/lordofthemind/EventifyGo/pkgs/middlewares"
+)
+
+type SessionGinHandler struct {
+	service services.SessionServiceInterface
+}
+
+func NewSessionGinHandler(service services.SessionServiceInterface) *SessionGinHandler {
+	return &SessionGinHandler{service: service}
+}
+
+// List the active sessions of SuperUser, marking the one of the request
+func (h *SessionGinHandler) ListSessionsHandler(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		response := responses.NewGinResponse(c, http.StatusBadRequest, "Invalid ID format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	sessions, err := h.service.ListSessions(c.Request.Context(), id)
+	if err != nil {
+		status := responses.ErrorStatus(err)
+		response := responses.NewGinResponse(c, status, "Failed to retrieve sessions", nil, err.Error())
+		c.JSON(status, response)
+		return
+	}
+
+	currentID, _ := c.Get(middlewares.SessionIDKey)
+	for _, session := range sessions {
+		session.Current = session.ID == currentID
+	}
+
+	response := responses.NewGinResponse(c, http.StatusOK, "Sessions retrieved successfully", sessions, nil)
+	c.JSON(http.StatusOK, response)
+}
+
+// Revoke a session of SuperUser
+func (h *SessionGinHandler) RevokeSessionHandler(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		response := responses.NewGinResponse(c, http.StatusBadRequest, "Invalid ID format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+	sessionID, err := uuid.Parse(c.Param("sessionID"))
+	if err != nil {
+		response := responses.NewGinResponse(c, http.StatusBadRequest, "Invalid session ID format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	if err := h.service.RevokeSession(c.Request.Context(), id, sessionID); err != nil {
+		status := responses.ErrorStatus(err)
+		response := responses.NewGinResponse(c, status, "Failed to revoke session", nil, err.Error())
+		c.JSON(status, response)
+		return
+	}
+
+	response := responses.NewGinResponse(c, http.StatusOK, "Session revoked successfully", nil, nil)
+	c.JSON(http.StatusOK, response)
+}
+
+// Revoke every session of SuperUser
+func (h *SessionGinHandler) RevokeAllSessionsHandler(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		response := responses.NewGinResponse(c, http.StatusBadRequest, "Invalid ID format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	if err := h.service.RevokeAllSessions(c.Request.Context(), id); err != nil {
+		status := responses.ErrorStatus(err)
+		response := responses.NewGinResponse(c, status, "Failed to revoke sessions", nil, err.Error())
+		c.JSON(status, response)
+		return
+	}
+
+	response := responses.NewGinResponse(c, http.StatusOK, "Sessions revoked successfully", nil, nil)
+	c.JSON(http.StatusOK, response)
+}
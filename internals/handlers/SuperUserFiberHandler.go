@@ -1,14 +1,12 @@
 package handlers
 
 import (
-	"context"
 	"strconv"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 	"github.com/lordofthemind/EventifyGo/internals/responses"
 	"github.com/lordofthemind/EventifyGo/internals/services"
-	"github.com/lordofthemind/EventifyGo/internals/types"
 )
 
 type SuperUserFiberHandler struct {
@@ -21,14 +19,15 @@ func NewSuperUserFiberHandler(service services.SuperUserServiceInterface) *Super
 
 // Create SuperUser handler
 func (h *SuperUserFiberHandler) CreateSuperUserHandler(c *fiber.Ctx) error {
-	var superUser types.SuperUserType
-	if err := c.BodyParser(&superUser); err != nil {
+	var request createSuperUserRequest
+	if err := c.BodyParser(&request); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(responses.NewFiberResponse(c, fiber.StatusBadRequest, "Invalid input", nil, err.Error()))
 	}
 
-	createdSuperUser, err := h.service.CreateSuperUser(context.Background(), &superUser)
+	createdSuperUser, err := h.service.CreateSuperUser(c.UserContext(), fiberViewerID(c), request.toSuperUser())
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(responses.NewFiberResponse(c, fiber.StatusInternalServerError, "Failed to create SuperUser", nil, err.Error()))
+		status := responses.ErrorStatus(err)
+		return c.Status(status).JSON(responses.NewFiberResponse(c, status, "Failed to create SuperUser", nil, err.Error()))
 	}
 
 	return c.Status(fiber.StatusCreated).JSON(responses.NewFiberResponse(c, fiber.StatusCreated, "SuperUser created successfully", createdSuperUser, nil))
@@ -36,13 +35,10 @@ func (h *SuperUserFiberHandler) CreateSuperUserHandler(c *fiber.Ctx) error {
 
 // GetAllSuperUsersHandler retrieves all SuperUsers and returns them in a Fiber response
 func (h *SuperUserFiberHandler) GetAllSuperUsersHandler(c *fiber.Ctx) error {
-	superUsers, err := h.service.GetAllSuperUsers(context.Background())
+	superUsers, err := h.service.GetAllSuperUsers(c.UserContext())
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(responses.NewFiberResponse(c, fiber.StatusInternalServerError, "Failed to retrieve SuperUsers", nil, err.Error()))
-	}
-
-	if len(superUsers) == 0 {
-		return c.Status(fiber.StatusNotFound).JSON(responses.NewFiberResponse(c, fiber.StatusNotFound, "No SuperUsers found", nil, nil))
+		status := responses.ErrorStatus(err)
+		return c.Status(status).JSON(responses.NewFiberResponse(c, status, "Failed to retrieve SuperUsers", nil, err.Error()))
 	}
 
 	return c.Status(fiber.StatusOK).JSON(responses.NewFiberResponse(c, fiber.StatusOK, "SuperUsers retrieved successfully", superUsers, nil))
@@ -56,9 +52,10 @@ func (h *SuperUserFiberHandler) GetSuperUserByIDHandler(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(responses.NewFiberResponse(c, fiber.StatusBadRequest, "Invalid ID format", nil, err.Error()))
 	}
 
-	superUser, err := h.service.GetSuperUserByID(context.Background(), id)
+	superUser, err := h.service.GetSuperUserByID(c.UserContext(), id)
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(responses.NewFiberResponse(c, fiber.StatusNotFound, "SuperUser not found", nil, err.Error()))
+		status := responses.ErrorStatus(err)
+		return c.Status(status).JSON(responses.NewFiberResponse(c, status, "Failed to retrieve SuperUser", nil, err.Error()))
 	}
 
 	return c.Status(fiber.StatusOK).JSON(responses.NewFiberResponse(c, fiber.StatusOK, "SuperUser retrieved successfully", superUser, nil))
@@ -68,9 +65,10 @@ func (h *SuperUserFiberHandler) GetSuperUserByIDHandler(c *fiber.Ctx) error {
 func (h *SuperUserFiberHandler) GetSuperUserByEmailHandler(c *fiber.Ctx) error {
 	email := c.Params("email")
 
-	superUser, err := h.service.GetSuperUserByEmail(context.Background(), email)
+	superUser, err := h.service.GetSuperUserByEmail(c.UserContext(), email)
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(responses.NewFiberResponse(c, fiber.StatusNotFound, "SuperUser not found", nil, err.Error()))
+		status := responses.ErrorStatus(err)
+		return c.Status(status).JSON(responses.NewFiberResponse(c, status, "Failed to retrieve SuperUser", nil, err.Error()))
 	}
 
 	return c.Status(fiber.StatusOK).JSON(responses.NewFiberResponse(c, fiber.StatusOK, "SuperUser retrieved successfully", superUser, nil))
@@ -80,15 +78,33 @@ func (h *SuperUserFiberHandler) GetSuperUserByEmailHandler(c *fiber.Ctx) error {
 func (h *SuperUserFiberHandler) GetSuperUserByUsernameHandler(c *fiber.Ctx) error {
 	username := c.Params("username")
 
-	superUser, err := h.service.GetSuperUserByUsername(context.Background(), username)
+	superUser, err := h.service.GetSuperUserByUsername(c.UserContext(), username)
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(responses.NewFiberResponse(c, fiber.StatusNotFound, "SuperUser not found", nil, err.Error()))
+		status := responses.ErrorStatus(err)
+		return c.Status(status).JSON(responses.NewFiberResponse(c, status, "Failed to retrieve SuperUser", nil, err.Error()))
 	}
 
 	return c.Status(fiber.StatusOK).JSON(responses.NewFiberResponse(c, fiber.StatusOK, "SuperUser retrieved successfully", superUser, nil))
 }
 
-// Enable 2FA for SuperUser
+// Start 2FA setup for SuperUser, returns the secret and its otpauth:// URI
+func (h *SuperUserFiberHandler) Setup2FAForSuperUserHandler(c *fiber.Ctx) error {
+	idParam := c.Params("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(responses.NewFiberResponse(c, fiber.StatusBadRequest, "Invalid ID format", nil, err.Error()))
+	}
+
+	setup, err := h.service.Setup2FAForSuperUser(c.UserContext(), fiberViewerID(c), id)
+	if err != nil {
+		status := responses.ErrorStatus(err)
+		return c.Status(status).JSON(responses.NewFiberResponse(c, status, "Failed to start 2FA setup", nil, err.Error()))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(responses.NewFiberResponse(c, fiber.StatusOK, "2FA setup started, confirm it with a code", setup, nil))
+}
+
+// Enable 2FA for SuperUser with a first code from the authenticator app
 func (h *SuperUserFiberHandler) Enable2FAForSuperUserHandler(c *fiber.Ctx) error {
 	idParam := c.Params("id")
 	id, err := uuid.Parse(idParam)
@@ -96,18 +112,52 @@ func (h *SuperUserFiberHandler) Enable2FAForSuperUserHandler(c *fiber.Ctx) error
 		return c.Status(fiber.StatusBadRequest).JSON(responses.NewFiberResponse(c, fiber.StatusBadRequest, "Invalid ID format", nil, err.Error()))
 	}
 
-	var body struct {
-		Secret string `json:"secret"`
+	var body twoFactorCodeRequest
+	if err := c.BodyParser(&body); err != nil || body.Code == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(responses.NewFiberResponse(c, fiber.StatusBadRequest, "Invalid input", nil, "Code is missing or invalid"))
 	}
-	if err := c.BodyParser(&body); err != nil || body.Secret == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(responses.NewFiberResponse(c, fiber.StatusBadRequest, "Invalid input", nil, "Secret is missing or invalid"))
+
+	codes, err := h.service.Enable2FAForSuperUser(c.UserContext(), fiberViewerID(c), id, body.Code)
+	if err != nil {
+		status := responses.ErrorStatus(err)
+		return c.Status(status).JSON(responses.NewFiberResponse(c, status, "Failed to enable 2FA", nil, err.Error()))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(responses.NewFiberResponse(c, fiber.StatusOK, "2FA enabled, store the recovery codes safely", fiber.Map{"recovery_codes": codes}, nil))
+}
+
+// Regenerate the 2FA recovery codes of SuperUser, the previous codes stop working
+func (h *SuperUserFiberHandler) RegenerateRecoveryCodesHandler(c *fiber.Ctx) error {
+	idParam := c.Params("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(responses.NewFiberResponse(c, fiber.StatusBadRequest, "Invalid ID format", nil, err.Error()))
+	}
+
+	codes, err := h.service.RegenerateRecoveryCodes(c.UserContext(), fiberViewerID(c), id)
+	if err != nil {
+		status := responses.ErrorStatus(err)
+		return c.Status(status).JSON(responses.NewFiberResponse(c, status, "Failed to regenerate recovery codes", nil, err.Error()))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(responses.NewFiberResponse(c, fiber.StatusOK, "Recovery codes regenerated, store them safely", fiber.Map{"recovery_codes": codes}, nil))
+}
+
+// Count the unused 2FA recovery codes of SuperUser
+func (h *SuperUserFiberHandler) CountRecoveryCodesHandler(c *fiber.Ctx) error {
+	idParam := c.Params("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(responses.NewFiberResponse(c, fiber.StatusBadRequest, "Invalid ID format", nil, err.Error()))
 	}
 
-	if err := h.service.Enable2FAForSuperUser(context.Background(), id, body.Secret); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(responses.NewFiberResponse(c, fiber.StatusInternalServerError, "Failed to enable 2FA", nil, err.Error()))
+	remaining, err := h.service.CountRecoveryCodes(c.UserContext(), id)
+	if err != nil {
+		status := responses.ErrorStatus(err)
+		return c.Status(status).JSON(responses.NewFiberResponse(c, status, "Failed to count recovery codes", nil, err.Error()))
 	}
 
-	return c.Status(fiber.StatusOK).JSON(responses.NewFiberResponse(c, fiber.StatusOK, "2FA enabled", nil, nil))
+	return c.Status(fiber.StatusOK).JSON(responses.NewFiberResponse(c, fiber.StatusOK, "Recovery codes counted successfully", fiber.Map{"remaining": remaining}, nil))
 }
 
 // Disable 2FA for SuperUser
@@ -118,8 +168,16 @@ func (h *SuperUserFiberHandler) Disable2FAForSuperUserHandler(c *fiber.Ctx) erro
 		return c.Status(fiber.StatusBadRequest).JSON(responses.NewFiberResponse(c, fiber.StatusBadRequest, "Invalid ID format", nil, err.Error()))
 	}
 
-	if err := h.service.Disable2FAForSuperUser(context.Background(), id); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(responses.NewFiberResponse(c, fiber.StatusInternalServerError, "Failed to disable 2FA", nil, err.Error()))
+	var body disableTwoFactorRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&body); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(responses.NewFiberResponse(c, fiber.StatusBadRequest, "Invalid input", nil, err.Error()))
+		}
+	}
+
+	if err := h.service.Disable2FAForSuperUser(c.UserContext(), fiberViewerID(c), id, body.Code); err != nil {
+		status := responses.ErrorStatus(err)
+		return c.Status(status).JSON(responses.NewFiberResponse(c, status, "Failed to disable 2FA", nil, err.Error()))
 	}
 
 	return c.Status(fiber.StatusOK).JSON(responses.NewFiberResponse(c, fiber.StatusOK, "2FA disabled", nil, nil))
@@ -127,9 +185,10 @@ func (h *SuperUserFiberHandler) Disable2FAForSuperUserHandler(c *fiber.Ctx) erro
 
 // Get all 2FA-enabled SuperUsers
 func (h *SuperUserFiberHandler) GetAll2FAEnabledSuperUsersHandler(c *fiber.Ctx) error {
-	superUsers, err := h.service.GetAll2FAEnabledSuperUsers(context.Background())
+	superUsers, err := h.service.GetAll2FAEnabledSuperUsers(c.UserContext())
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(responses.NewFiberResponse(c, fiber.StatusInternalServerError, "Failed to retrieve 2FA-enabled SuperUsers", nil, err.Error()))
+		status := responses.ErrorStatus(err)
+		return c.Status(status).JSON(responses.NewFiberResponse(c, status, "Failed to retrieve 2FA-enabled SuperUsers", nil, err.Error()))
 	}
 
 	return c.Status(fiber.StatusOK).JSON(responses.NewFiberResponse(c, fiber.StatusOK, "2FA-enabled SuperUsers retrieved", superUsers, nil))
@@ -150,8 +209,9 @@ func (h *SuperUserFiberHandler) UpdateSuperUserRoleHandler(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(responses.NewFiberResponse(c, fiber.StatusBadRequest, "Invalid input", nil, "Role is missing or invalid"))
 	}
 
-	if err := h.service.UpdateSuperUserRole(context.Background(), id, body.Role); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(responses.NewFiberResponse(c, fiber.StatusInternalServerError, "Failed to update role", nil, err.Error()))
+	if err := h.service.UpdateSuperUserRole(c.UserContext(), fiberViewerID(c), id, body.Role); err != nil {
+		status := responses.ErrorStatus(err)
+		return c.Status(status).JSON(responses.NewFiberResponse(c, status, "Failed to update role", nil, err.Error()))
 	}
 
 	return c.Status(fiber.StatusOK).JSON(responses.NewFiberResponse(c, fiber.StatusOK, "Role updated", nil, nil))
@@ -165,70 +225,42 @@ func (h *SuperUserFiberHandler) UpdateSuperUserPermissionsHandler(c *fiber.Ctx)
 		return c.Status(fiber.StatusBadRequest).JSON(responses.NewFiberResponse(c, fiber.StatusBadRequest, "Invalid ID format", nil, err.Error()))
 	}
 
+	// An empty list revokes every permission group
 	var body struct {
 		Permissions []string `json:"permissions"`
 	}
-	if err := c.BodyParser(&body); err != nil || len(body.Permissions) == 0 {
+	if err := c.BodyParser(&body); err != nil || body.Permissions == nil {
 		return c.Status(fiber.StatusBadRequest).JSON(responses.NewFiberResponse(c, fiber.StatusBadRequest, "Invalid input", nil, "Permissions are missing or invalid"))
 	}
 
-	if err := h.service.UpdateSuperUserPermissions(context.Background(), id, body.Permissions); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(responses.NewFiberResponse(c, fiber.StatusInternalServerError, "Failed to update permissions", nil, err.Error()))
+	if err := h.service.UpdateSuperUserPermissions(c.UserContext(), fiberViewerID(c), id, body.Permissions); err != nil {
+		status := responses.ErrorStatus(err)
+		return c.Status(status).JSON(responses.NewFiberResponse(c, status, "Failed to update permissions", nil, err.Error()))
 	}
 
 	return c.Status(fiber.StatusOK).JSON(responses.NewFiberResponse(c, fiber.StatusOK, "Permissions updated", nil, nil))
 }
 
-// Update specific SuperUser field
-func (h *SuperUserFiberHandler) UpdateSuperUserFieldHandler(c *fiber.Ctx) error {
+// Patch SuperUser with a JSON Merge Patch or, by its content type, a JSON Patch
+func (h *SuperUserFiberHandler) PatchSuperUserHandler(c *fiber.Ctx) error {
 	idParam := c.Params("id")
 	id, err := uuid.Parse(idParam)
 	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(responses.NewFiberResponse(c, fiber.StatusBadRequest, "Invalid ID format", nil, err.Error()))
 	}
 
-	field := c.Params("field")
-	var value interface{}
-	if err := c.BodyParser(&value); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(responses.NewFiberResponse(c, fiber.StatusBadRequest, "Invalid input", nil, "Field value is invalid"))
-	}
-
-	if err := h.service.UpdateSuperUserField(context.Background(), id, field, value); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(responses.NewFiberResponse(c, fiber.StatusInternalServerError, "Failed to update field", nil, err.Error()))
+	format, ok := patchFormat(c.Get(fiber.HeaderContentType))
+	if !ok {
+		return c.Status(fiber.StatusUnsupportedMediaType).JSON(responses.NewFiberResponse(c, fiber.StatusUnsupportedMediaType, "Unsupported patch format", nil, unsupportedPatchMessage))
 	}
 
-	return c.Status(fiber.StatusOK).JSON(responses.NewFiberResponse(c, fiber.StatusOK, "Field updated", nil, nil))
-}
-
-// Generate and set reset token
-func (h *SuperUserFiberHandler) GenerateAndSetResetTokenHandler(c *fiber.Ctx) error {
-	idParam := c.Params("id")
-	id, err := uuid.Parse(idParam)
+	superUser, err := h.service.PatchSuperUser(c.UserContext(), fiberViewerID(c), id, format, c.Body())
 	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(responses.NewFiberResponse(c, fiber.StatusBadRequest, "Invalid ID format", nil, err.Error()))
-	}
-
-	token, err := h.service.GenerateAndSetResetToken(context.Background(), id)
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(responses.NewFiberResponse(c, fiber.StatusInternalServerError, "Failed to generate reset token", nil, err.Error()))
-	}
-
-	return c.Status(fiber.StatusOK).JSON(responses.NewFiberResponse(c, fiber.StatusOK, "Reset token generated", map[string]string{"reset_token": token}, nil))
-}
-
-// Clear reset token
-func (h *SuperUserFiberHandler) ClearResetTokenHandler(c *fiber.Ctx) error {
-	idParam := c.Params("id")
-	id, err := uuid.Parse(idParam)
-	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(responses.NewFiberResponse(c, fiber.StatusBadRequest, "Invalid ID format", nil, err.Error()))
-	}
-
-	if err := h.service.ClearResetToken(context.Background(), id); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(responses.NewFiberResponse(c, fiber.StatusInternalServerError, "Failed to clear reset token", nil, err.Error()))
+		status := responses.ErrorStatus(err)
+		return c.Status(status).JSON(responses.NewFiberResponse(c, status, "Failed to patch SuperUser", nil, err.Error()))
 	}
 
-	return c.Status(fiber.StatusOK).JSON(responses.NewFiberResponse(c, fiber.StatusOK, "Reset token cleared", nil, nil))
+	return c.Status(fiber.StatusOK).JSON(responses.NewFiberResponse(c, fiber.StatusOK, "SuperUser updated successfully", superUser, nil))
 }
 
 // Delete SuperUser by ID
@@ -239,8 +271,9 @@ func (h *SuperUserFiberHandler) DeleteSuperUserByIDHandler(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(responses.NewFiberResponse(c, fiber.StatusBadRequest, "Invalid ID format", nil, err.Error()))
 	}
 
-	if err := h.service.DeleteSuperUserByID(context.Background(), id); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(responses.NewFiberResponse(c, fiber.StatusInternalServerError, "Failed to delete SuperUser", nil, err.Error()))
+	if err := h.service.DeleteSuperUserByID(c.UserContext(), fiberViewerID(c), id); err != nil {
+		status := responses.ErrorStatus(err)
+		return c.Status(status).JSON(responses.NewFiberResponse(c, status, "Failed to delete SuperUser", nil, err.Error()))
 	}
 
 	return c.Status(fiber.StatusOK).JSON(responses.NewFiberResponse(c, fiber.StatusOK, "SuperUser deleted", nil, nil))
@@ -253,9 +286,10 @@ func (h *SuperUserFiberHandler) SearchSuperUsersHandler(c *fiber.Ctx) error {
 	limit, _ := strconv.Atoi(c.Query("limit", "10"))
 	sortBy := c.Query("sort_by", "created_at")
 
-	superUsers, err := h.service.SearchSuperUsers(context.Background(), searchQuery, page, limit, sortBy)
+	superUsers, err := h.service.SearchSuperUsers(c.UserContext(), searchQuery, page, limit, sortBy)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(responses.NewFiberResponse(c, fiber.StatusInternalServerError, "Failed to search SuperUsers", nil, err.Error()))
+		status := responses.ErrorStatus(err)
+		return c.Status(status).JSON(responses.NewFiberResponse(c, status, "Failed to search SuperUsers", nil, err.Error()))
 	}
 
 	return c.Status(fiber.StatusOK).JSON(responses.NewFiberResponse(c, fiber.StatusOK, "SuperUsers search successful", superUsers, nil))
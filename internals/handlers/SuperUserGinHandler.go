@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"io"
 	"net/http"
 	"strconv"
 
@@ -8,7 +9,6 @@ import (
 	"github.com/google/uuid"
 	"github.com/lordofthemind/EventifyGo/internals/responses" // Import the responses package
 	"github.com/lordofthemind/EventifyGo/internals/services"
-	"github.com/lordofthemind/EventifyGo/internals/types"
 )
 
 type SuperUserGinHandler struct {
@@ -21,19 +21,19 @@ func NewSuperUserGinHandler(service services.SuperUserServiceInterface) *SuperUs
 
 // Create SuperUser handler
 func (h *SuperUserGinHandler) CreateSuperUserHandler(c *gin.Context) {
-	var superUser types.SuperUserType
-	if err := c.ShouldBindJSON(&superUser); err != nil {
+	var request createSuperUserRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
 		// Use standardized response for invalid input
 		response := responses.NewGinResponse(c, http.StatusBadRequest, "Invalid input", nil, err.Error())
 		c.JSON(http.StatusBadRequest, response)
 		return
 	}
 
-	createdSuperUser, err := h.service.CreateSuperUser(c.Request.Context(), &superUser)
+	createdSuperUser, err := h.service.CreateSuperUser(c.Request.Context(), ginViewerID(c), request.toSuperUser())
 	if err != nil {
-		// Use standardized response for internal server errors
-		response := responses.NewGinResponse(c, http.StatusInternalServerError, "Failed to create SuperUser", nil, err.Error())
-		c.JSON(http.StatusInternalServerError, response)
+		status := responses.ErrorStatus(err)
+		response := responses.NewGinResponse(c, status, "Failed to create SuperUser", nil, err.Error())
+		c.JSON(status, response)
 		return
 	}
 
@@ -46,16 +46,9 @@ func (h *SuperUserGinHandler) CreateSuperUserHandler(c *gin.Context) {
 func (h *SuperUserGinHandler) GetAllSuperUsersHandler(c *gin.Context) {
 	superUsers, err := h.service.GetAllSuperUsers(c.Request.Context())
 	if err != nil {
-		// Use standardized response for internal server errors
-		response := responses.NewGinResponse(c, http.StatusInternalServerError, "Failed to retrieve SuperUsers", nil, err.Error())
-		c.JSON(http.StatusInternalServerError, response)
-		return
-	}
-
-	// If no SuperUsers are found, return a standardized response for empty result
-	if len(superUsers) == 0 {
-		response := responses.NewGinResponse(c, http.StatusNotFound, "No SuperUsers found", nil, nil)
-		c.JSON(http.StatusNotFound, response)
+		status := responses.ErrorStatus(err)
+		response := responses.NewGinResponse(c, status, "Failed to retrieve SuperUsers", nil, err.Error())
+		c.JSON(status, response)
 		return
 	}
 
@@ -77,9 +70,9 @@ func (h *SuperUserGinHandler) GetSuperUserByIDHandler(c *gin.Context) {
 
 	superUser, err := h.service.GetSuperUserByID(c.Request.Context(), id)
 	if err != nil {
-		// Use standardized response for not found error
-		response := responses.NewGinResponse(c, http.StatusNotFound, "SuperUser not found", nil, err.Error())
-		c.JSON(http.StatusNotFound, response)
+		status := responses.ErrorStatus(err)
+		response := responses.NewGinResponse(c, status, "Failed to retrieve SuperUser", nil, err.Error())
+		c.JSON(status, response)
 		return
 	}
 
@@ -94,8 +87,9 @@ func (h *SuperUserGinHandler) GetSuperUserByEmailHandler(c *gin.Context) {
 
 	superUser, err := h.service.GetSuperUserByEmail(c.Request.Context(), email)
 	if err != nil {
-		response := responses.NewGinResponse(c, http.StatusNotFound, "SuperUser not found", nil, err.Error())
-		c.JSON(http.StatusNotFound, response)
+		status := responses.ErrorStatus(err)
+		response := responses.NewGinResponse(c, status, "Failed to retrieve SuperUser", nil, err.Error())
+		c.JSON(status, response)
 		return
 	}
 
@@ -109,8 +103,9 @@ func (h *SuperUserGinHandler) GetSuperUserByUsernameHandler(c *gin.Context) {
 
 	superUser, err := h.service.GetSuperUserByUsername(c.Request.Context(), username)
 	if err != nil {
-		response := responses.NewGinResponse(c, http.StatusNotFound, "SuperUser not found", nil, err.Error())
-		c.JSON(http.StatusNotFound, response)
+		status := responses.ErrorStatus(err)
+		response := responses.NewGinResponse(c, status, "Failed to retrieve SuperUser", nil, err.Error())
+		c.JSON(status, response)
 		return
 	}
 
@@ -118,8 +113,8 @@ func (h *SuperUserGinHandler) GetSuperUserByUsernameHandler(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
-// Enable 2FA for SuperUser
-func (h *SuperUserGinHandler) Enable2FAForSuperUserHandler(c *gin.Context) {
+// Start 2FA setup for SuperUser, returns the secret and its otpauth:// URI
+func (h *SuperUserGinHandler) Setup2FAForSuperUserHandler(c *gin.Context) {
 	idParam := c.Param("id")
 	id, err := uuid.Parse(idParam)
 	if err != nil {
@@ -128,27 +123,49 @@ func (h *SuperUserGinHandler) Enable2FAForSuperUserHandler(c *gin.Context) {
 		return
 	}
 
-	var body struct {
-		Secret string `json:"secret"`
+	setup, err := h.service.Setup2FAForSuperUser(c.Request.Context(), ginViewerID(c), id)
+	if err != nil {
+		status := responses.ErrorStatus(err)
+		response := responses.NewGinResponse(c, status, "Failed to start 2FA setup", nil, err.Error())
+		c.JSON(status, response)
+		return
+	}
+
+	response := responses.NewGinResponse(c, http.StatusOK, "2FA setup started, confirm it with a code", setup, nil)
+	c.JSON(http.StatusOK, response)
+}
+
+// Enable 2FA for SuperUser with a first code from the authenticator app
+func (h *SuperUserGinHandler) Enable2FAForSuperUserHandler(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
+	if err != nil {
+		response := responses.NewGinResponse(c, http.StatusBadRequest, "Invalid ID format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, response)
+		return
 	}
-	if err := c.ShouldBindJSON(&body); err != nil || body.Secret == "" {
+
+	var body twoFactorCodeRequest
+	if err := c.ShouldBindJSON(&body); err != nil {
 		response := responses.NewGinResponse(c, http.StatusBadRequest, "Invalid input", nil, err.Error())
 		c.JSON(http.StatusBadRequest, response)
 		return
 	}
 
-	if err := h.service.Enable2FAForSuperUser(c.Request.Context(), id, body.Secret); err != nil {
-		response := responses.NewGinResponse(c, http.StatusInternalServerError, "Failed to enable 2FA", nil, err.Error())
-		c.JSON(http.StatusInternalServerError, response)
+	codes, err := h.service.Enable2FAForSuperUser(c.Request.Context(), ginViewerID(c), id, body.Code)
+	if err != nil {
+		status := responses.ErrorStatus(err)
+		response := responses.NewGinResponse(c, status, "Failed to enable 2FA", nil, err.Error())
+		c.JSON(status, response)
 		return
 	}
 
-	response := responses.NewGinResponse(c, http.StatusOK, "2FA enabled", nil, nil)
+	response := responses.NewGinResponse(c, http.StatusOK, "2FA enabled, store the recovery codes safely", gin.H{"recovery_codes": codes}, nil)
 	c.JSON(http.StatusOK, response)
 }
 
-// Disable 2FA for SuperUser
-func (h *SuperUserGinHandler) Disable2FAForSuperUserHandler(c *gin.Context) {
+// Regenerate the 2FA recovery codes of SuperUser, the previous codes stop working
+func (h *SuperUserGinHandler) RegenerateRecoveryCodesHandler(c *gin.Context) {
 	idParam := c.Param("id")
 	id, err := uuid.Parse(idParam)
 	if err != nil {
@@ -157,31 +174,42 @@ func (h *SuperUserGinHandler) Disable2FAForSuperUserHandler(c *gin.Context) {
 		return
 	}
 
-	if err := h.service.Disable2FAForSuperUser(c.Request.Context(), id); err != nil {
-		response := responses.NewGinResponse(c, http.StatusInternalServerError, "Failed to disable 2FA", nil, err.Error())
-		c.JSON(http.StatusInternalServerError, response)
+	codes, err := h.service.RegenerateRecoveryCodes(c.Request.Context(), ginViewerID(c), id)
+	if err != nil {
+		status := responses.ErrorStatus(err)
+		response := responses.NewGinResponse(c, status, "Failed to regenerate recovery codes", nil, err.Error())
+		c.JSON(status, response)
 		return
 	}
 
-	response := responses.NewGinResponse(c, http.StatusOK, "2FA disabled", nil, nil)
+	response := responses.NewGinResponse(c, http.StatusOK, "Recovery codes regenerated, store them safely", gin.H{"recovery_codes": codes}, nil)
 	c.JSON(http.StatusOK, response)
 }
 
-// Get all 2FA-enabled SuperUsers
-func (h *SuperUserGinHandler) GetAll2FAEnabledSuperUsersHandler(c *gin.Context) {
-	superUsers, err := h.service.GetAll2FAEnabledSuperUsers(c.Request.Context())
+// Count the unused 2FA recovery codes of SuperUser
+func (h *SuperUserGinHandler) CountRecoveryCodesHandler(c *gin.Context) {
+	idParam := c.Param("id")
+	id, err := uuid.Parse(idParam)
 	if err != nil {
-		response := responses.NewGinResponse(c, http.StatusInternalServerError, "Failed to retrieve SuperUsers", nil, err.Error())
-		c.JSON(http.StatusInternalServerError, response)
+		response := responses.NewGinResponse(c, http.StatusBadRequest, "Invalid ID format", nil, err.Error())
+		c.JSON(http.StatusBadRequest, response)
 		return
 	}
 
-	response := responses.NewGinResponse(c, http.StatusOK, "SuperUsers retrieved successfully", superUsers, nil)
+	remaining, err := h.service.CountRecoveryCodes(c.Request.Context(), id)
+	if err != nil {
+		status := responses.ErrorStatus(err)
+		response := responses.NewGinResponse(c, status, "Failed to count recovery codes", nil, err.Error())
+		c.JSON(status, response)
+		return
+	}
+
+	response := responses.NewGinResponse(c, http.StatusOK, "Recovery codes counted successfully", gin.H{"remaining": remaining}, nil)
 	c.JSON(http.StatusOK, response)
 }
 
-// Update SuperUser role
-func (h *SuperUserGinHandler) UpdateSuperUserRoleHandler(c *gin.Context) {
+// Disable 2FA for SuperUser
+func (h *SuperUserGinHandler) Disable2FAForSuperUserHandler(c *gin.Context) {
 	idParam := c.Param("id")
 	id, err := uuid.Parse(idParam)
 	if err != nil {
@@ -190,27 +218,42 @@ func (h *SuperUserGinHandler) UpdateSuperUserRoleHandler(c *gin.Context) {
 		return
 	}
 
-	var body struct {
-		Role string `json:"role"`
+	var body disableTwoFactorRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&body); err != nil {
+			response := responses.NewGinResponse(c, http.StatusBadRequest, "Invalid input", nil, err.Error())
+			c.JSON(http.StatusBadRequest, response)
+			return
+		}
 	}
-	if err := c.ShouldBindJSON(&body); err != nil || body.Role == "" {
-		response := responses.NewGinResponse(c, http.StatusBadRequest, "Invalid input", nil, err.Error())
-		c.JSON(http.StatusBadRequest, response)
+
+	if err := h.service.Disable2FAForSuperUser(c.Request.Context(), ginViewerID(c), id, body.Code); err != nil {
+		status := responses.ErrorStatus(err)
+		response := responses.NewGinResponse(c, status, "Failed to disable 2FA", nil, err.Error())
+		c.JSON(status, response)
 		return
 	}
 
-	if err := h.service.UpdateSuperUserRole(c.Request.Context(), id, body.Role); err != nil {
-		response := responses.NewGinResponse(c, http.StatusInternalServerError, "Failed to update role", nil, err.Error())
-		c.JSON(http.StatusInternalServerError, response)
+	response := responses.NewGinResponse(c, http.StatusOK, "2FA disabled", nil, nil)
+	c.JSON(http.StatusOK, response)
+}
+
+// Get all 2FA-enabled SuperUsers
+func (h *SuperUserGinHandler) GetAll2FAEnabledSuperUsersHandler(c *gin.Context) {
+	superUsers, err := h.service.GetAll2FAEnabledSuperUsers(c.Request.Context())
+	if err != nil {
+		status := responses.ErrorStatus(err)
+		response := responses.NewGinResponse(c, status, "Failed to retrieve SuperUsers", nil, err.Error())
+		c.JSON(status, response)
 		return
 	}
 
-	response := responses.NewGinResponse(c, http.StatusOK, "Role updated", nil, nil)
+	response := responses.NewGinResponse(c, http.StatusOK, "SuperUsers retrieved successfully", superUsers, nil)
 	c.JSON(http.StatusOK, response)
 }
 
-// Update SuperUser permissions
-func (h *SuperUserGinHandler) UpdateSuperUserPermissionsHandler(c *gin.Context) {
+// Update SuperUser role
+func (h *SuperUserGinHandler) UpdateSuperUserRoleHandler(c *gin.Context) {
 	idParam := c.Param("id")
 	id, err := uuid.Parse(idParam)
 	if err != nil {
@@ -220,26 +263,27 @@ func (h *SuperUserGinHandler) UpdateSuperUserPermissionsHandler(c *gin.Context)
 	}
 
 	var body struct {
-		Permissions []string `json:"permissions"`
+		Role string `json:"role" binding:"required"`
 	}
-	if err := c.ShouldBindJSON(&body); err != nil || len(body.Permissions) == 0 {
+	if err := c.ShouldBindJSON(&body); err != nil {
 		response := responses.NewGinResponse(c, http.StatusBadRequest, "Invalid input", nil, err.Error())
 		c.JSON(http.StatusBadRequest, response)
 		return
 	}
 
-	if err := h.service.UpdateSuperUserPermissions(c.Request.Context(), id, body.Permissions); err != nil {
-		response := responses.NewGinResponse(c, http.StatusInternalServerError, "Failed to update permissions", nil, err.Error())
-		c.JSON(http.StatusInternalServerError, response)
+	if err := h.service.UpdateSuperUserRole(c.Request.Context(), ginViewerID(c), id, body.Role); err != nil {
+		status := responses.ErrorStatus(err)
+		response := responses.NewGinResponse(c, status, "Failed to update role", nil, err.Error())
+		c.JSON(status, response)
 		return
 	}
 
-	response := responses.NewGinResponse(c, http.StatusOK, "Permissions updated", nil, nil)
+	response := responses.NewGinResponse(c, http.StatusOK, "Role updated", nil, nil)
 	c.JSON(http.StatusOK, response)
 }
 
-// Update specific SuperUser field
-func (h *SuperUserGinHandler) UpdateSuperUserFieldHandler(c *gin.Context) {
+// Update SuperUser permissions
+func (h *SuperUserGinHandler) UpdateSuperUserPermissionsHandler(c *gin.Context) {
 	idParam := c.Param("id")
 	id, err := uuid.Parse(idParam)
 	if err != nil {
@@ -248,26 +292,29 @@ func (h *SuperUserGinHandler) UpdateSuperUserFieldHandler(c *gin.Context) {
 		return
 	}
 
-	field := c.Param("field")
-	var value interface{}
-	if err := c.ShouldBindJSON(&value); err != nil {
-		response := responses.NewGinResponse(c, http.StatusBadRequest, "Invalid input", nil, err.Error())
+	// An empty list revokes every permission group
+	var body struct {
+		Permissions []string `json:"permissions"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil || body.Permissions == nil {
+		response := responses.NewGinResponse(c, http.StatusBadRequest, "Invalid input", nil, "Permissions are missing or invalid")
 		c.JSON(http.StatusBadRequest, response)
 		return
 	}
 
-	if err := h.service.UpdateSuperUserField(c.Request.Context(), id, field, value); err != nil {
-		response := responses.NewGinResponse(c, http.StatusInternalServerError, "Failed to update field", nil, err.Error())
-		c.JSON(http.StatusInternalServerError, response)
+	if err := h.service.UpdateSuperUserPermissions(c.Request.Context(), ginViewerID(c), id, body.Permissions); err != nil {
+		status := responses.ErrorStatus(err)
+		response := responses.NewGinResponse(c, status, "Failed to update permissions", nil, err.Error())
+		c.JSON(status, response)
 		return
 	}
 
-	response := responses.NewGinResponse(c, http.StatusOK, "Field updated", nil, nil)
+	response := responses.NewGinResponse(c, http.StatusOK, "Permissions updated", nil, nil)
 	c.JSON(http.StatusOK, response)
 }
 
-// Generate and set reset token
-func (h *SuperUserGinHandler) GenerateAndSetResetTokenHandler(c *gin.Context) {
+// Patch SuperUser with a JSON Merge Patch or, by its content type, a JSON Patch
+func (h *SuperUserGinHandler) PatchSuperUserHandler(c *gin.Context) {
 	idParam := c.Param("id")
 	id, err := uuid.Parse(idParam)
 	if err != nil {
@@ -276,34 +323,29 @@ func (h *SuperUserGinHandler) GenerateAndSetResetTokenHandler(c *gin.Context) {
 		return
 	}
 
-	token, err := h.service.GenerateAndSetResetToken(c.Request.Context(), id)
-	if err != nil {
-		response := responses.NewGinResponse(c, http.StatusInternalServerError, "Failed to generate reset token", nil, err.Error())
-		c.JSON(http.StatusInternalServerError, response)
+	format, ok := patchFormat(c.GetHeader("Content-Type"))
+	if !ok {
+		response := responses.NewGinResponse(c, http.StatusUnsupportedMediaType, "Unsupported patch format", nil, unsupportedPatchMessage)
+		c.JSON(http.StatusUnsupportedMediaType, response)
 		return
 	}
 
-	response := responses.NewGinResponse(c, http.StatusOK, "Reset token generated", gin.H{"reset_token": token}, nil)
-	c.JSON(http.StatusOK, response)
-}
-
-// Clear reset token
-func (h *SuperUserGinHandler) ClearResetTokenHandler(c *gin.Context) {
-	idParam := c.Param("id")
-	id, err := uuid.Parse(idParam)
+	patch, err := io.ReadAll(c.Request.Body)
 	if err != nil {
-		response := responses.NewGinResponse(c, http.StatusBadRequest, "Invalid ID format", nil, err.Error())
+		response := responses.NewGinResponse(c, http.StatusBadRequest, "Invalid input", nil, err.Error())
 		c.JSON(http.StatusBadRequest, response)
 		return
 	}
 
-	if err := h.service.ClearResetToken(c.Request.Context(), id); err != nil {
-		response := responses.NewGinResponse(c, http.StatusInternalServerError, "Failed to clear reset token", nil, err.Error())
-		c.JSON(http.StatusInternalServerError, response)
+	superUser, err := h.service.PatchSuperUser(c.Request.Context(), ginViewerID(c), id, format, patch)
+	if err != nil {
+		status := responses.ErrorStatus(err)
+		response := responses.NewGinResponse(c, status, "Failed to patch SuperUser", nil, err.Error())
+		c.JSON(status, response)
 		return
 	}
 
-	response := responses.NewGinResponse(c, http.StatusOK, "Reset token cleared", nil, nil)
+	response := responses.NewGinResponse(c, http.StatusOK, "SuperUser updated successfully", superUser, nil)
 	c.JSON(http.StatusOK, response)
 }
 
@@ -317,9 +359,10 @@ func (h *SuperUserGinHandler) DeleteSuperUserByIDHandler(c *gin.Context) {
 		return
 	}
 
-	if err := h.service.DeleteSuperUserByID(c.Request.Context(), id); err != nil {
-		response := responses.NewGinResponse(c, http.StatusInternalServerError, "Failed to delete SuperUser", nil, err.Error())
-		c.JSON(http.StatusInternalServerError, response)
+	if err := h.service.DeleteSuperUserByID(c.Request.Context(), ginViewerID(c), id); err != nil {
+		status := responses.ErrorStatus(err)
+		response := responses.NewGinResponse(c, status, "Failed to delete SuperUser", nil, err.Error())
+		c.JSON(status, response)
 		return
 	}
 
@@ -336,8 +379,9 @@ func (h *SuperUserGinHandler) SearchSuperUsersHandler(c *gin.Context) {
 
 	superUsers, err := h.service.SearchSuperUsers(c.Request.Context(), searchQuery, page, limit, sortBy)
 	if err != nil {
-		response := responses.NewGinResponse(c, http.StatusInternalServerError, "Failed to search SuperUsers", nil, err.Error())
-		c.JSON(http.StatusInternalServerError, response)
+		status := responses.ErrorStatus(err)
+		response := responses.NewGinResponse(c, status, "Failed to search SuperUsers", nil, err.Error())
+		c.JSON(status, response)
 		return
 	}
 
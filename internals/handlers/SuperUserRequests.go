@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"errors"
+	"mime"
+	"strconv"
+	"time"
+
+	"github.com/lordofthemind/EventifyGo/internals/services"
+	"github.com/lordofthemind/EventifyGo/internals/types"
+)
+
+// createSuperUserRequest is the body of a SuperUser creation, the password is not part of
+// the JSON form of a SuperUser
+type createSuperUserRequest struct {
+	types.SuperUserType
+	Password string `json:"password"`
+}
+
+// toSuperUser returns the SuperUser to create, the service hashes the password
+func (r *createSuperUserRequest) toSuperUser() *types.SuperUserType {
+	superUser := r.SuperUserType
+	superUser.HashedPassword = r.Password
+	return &superUser
+}
+
+// loginRequest is the body of a login, identifier is an email or a username and code the
+// TOTP or recovery code of a SuperUser with 2FA enabled
+type loginRequest struct {
+	Identifier string `json:"identifier" binding:"required"`
+	Password   string `json:"password" binding:"required"`
+	Code       string `json:"code"`
+}
+
+// twoFactorCodeRequest is the body confirming a 2FA setup
+type twoFactorCodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// disableTwoFactorRequest is the optional body turning off 2FA, SuperUsers acting on their
+// own account confirm it with a TOTP or recovery code
+type disableTwoFactorRequest struct {
+	Code string `json:"code"`
+}
+
+// passwordResetRequest is the body asking for a password reset token
+type passwordResetRequest struct {
+	Email string `json:"email" binding:"required"`
+}
+
+// passwordResetConfirmRequest is the body setting a new password with a reset token
+type passwordResetConfirmRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required"`
+}
+
+// unsupportedPatchMessage lists the content types accepted by the SuperUser patch endpoint
+const unsupportedPatchMessage = "Use application/merge-patch+json or application/json-patch+json"
+
+// patchFormat reads the patch format from a Content-Type header, plain JSON is a merge patch
+func patchFormat(contentType string) (services.PatchFormat, bool) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return 0, false
+	}
+	switch mediaType {
+	case "application/merge-patch+json", "application/json":
+		return services.MergePatch, true
+	case "application/json-patch+json":
+		return services.JSONPatch, true
+	}
+	return 0, false
+}
+
+// retryAfter returns the Retry-After header value, in seconds, of a throttled login
+func retryAfter(err error) (string, bool) {
+	var throttled *services.LoginThrottledError
+	if !errors.As(err, &throttled) {
+		return "", false
+	}
+	return strconv.Itoa(int(throttled.RetryAfter / time.Second)), true
+}
+
+// refreshCookieName is the cookie carrying the refresh token, it is only sent to the auth endpoints
+const (
+	refreshCookieName = "SuperUserRefresh"
+	refreshCookiePath = "/auth"
+)
+
+// cookieMaxAge returns the lifetime in seconds of a cookie expiring at expiresAt
+func cookieMaxAge(expiresAt time.Time) int {
+	return int(time.Until(expiresAt).Seconds())
+}
@@ -2,47 +2,94 @@ package initializers
 
 import (
 	"context"
-	"log"
-	"time"
+	"fmt"
 
 	"github.com/lordofthemind/EventifyGo/configs"
-	"github.com/lordofthemind/EventifyGo/internals/types"
+	"github.com/lordofthemind/EventifyGo/internals/migrations"
+	"github.com/lordofthemind/EventifyGo/internals/repositories/mongodb"
 	"github.com/lordofthemind/mygopher/gophermongo"
 	"github.com/lordofthemind/mygopher/gopherpostgres"
+	"go.mongodb.org/mongo-driver/mongo"
+	"gorm.io/gorm"
 )
 
-func DatabaseInitializer() {
-	ctx := context.Background()
+// Databases holds the connections opened for the configured database type, the others are nil
+type Databases struct {
+	GormDB      *gorm.DB
+	MongoClient *mongo.Client
+	MongoDB     *mongo.Database
+}
 
-	if configs.Database == "postgres" {
-		// Initialize PostgreSQL
-		gormDB, err := gopherpostgres.ConnectToPostgresGORM(ctx, configs.PostgresURL, 10*time.Second, 3)
+// Close releases the connections
+func (d *Databases) Close(ctx context.Context) error {
+	if d.MongoClient != nil {
+		if err := d.MongoClient.Disconnect(ctx); err != nil {
+			return err
+		}
+	}
+	if d.GormDB != nil {
+		sqlDB, err := d.GormDB.DB()
 		if err != nil {
-			log.Fatalf("Failed to connect to PostgreSQL using GORM: %v", err)
+			return err
 		}
+		return sqlDB.Close()
+	}
+	return nil
+}
 
-		err = gopherpostgres.CheckAndEnableUUIDExtension(gormDB)
+func DatabaseInitializer(ctx context.Context, config configs.DatabaseConfig) (*Databases, error) {
+	switch config.Type {
+	case "postgres":
+		// Initialize PostgreSQL
+		gormDB, err := gopherpostgres.ConnectToPostgresGORM(ctx, config.PostgresURL, config.ConnectTimeout, config.ConnectRetries)
 		if err != nil {
-			log.Fatalf("Failed to confirm UUID extension: %v", err)
+			return nil, fmt.Errorf("failed to connect to PostgreSQL using GORM: %w", err)
 		}
 
-		// Auto migrate for GORM (Postgres)
-		if err := gormDB.AutoMigrate(&types.SuperUserType{}, &types.EventType{}); err != nil {
-			log.Fatalf("Failed to migrate Postgres database: %v", err)
+		err = gopherpostgres.CheckAndEnableUUIDExtension(gormDB)
+		if err != nil {
+			return nil, fmt.Errorf("failed to confirm UUID extension: %w", err)
 		}
 
-		// Set global GormDB
-		configs.GormDB = gormDB
-	}
+		return &Databases{GormDB: gormDB}, nil
 
-	if configs.Database == "mongodb" {
+	case "mongodb":
 		// Initialize MongoDB client
-		mongoClient, err := gophermongo.ConnectToMongoDB(ctx, configs.MongoDbURI, 10*time.Second, 3)
+		mongoClient, err := gophermongo.ConnectToMongoDB(ctx, config.MongoDbURI, config.ConnectTimeout, config.ConnectRetries)
 		if err != nil {
-			log.Fatalf("Failed to connect to MongoDB: %v", err)
+			return nil, fmt.Errorf("failed to connect to MongoDB: %w", err)
+		}
+
+		// Indexes, and validators when enabled, are declared next to the repositories
+		mongoDB := gophermongo.GetDatabase(mongoClient, config.MongoDatabase)
+		if err := mongodb.EnsureCollections(ctx, mongoDB, config.MongoSchemaValidation); err != nil {
+			mongoClient.Disconnect(ctx)
+			return nil, fmt.Errorf("failed to prepare MongoDB collections: %w", err)
 		}
 
-		// Set global MongoClient
-		configs.MongoClient = mongoClient
+		return &Databases{
+			MongoClient: mongoClient,
+			MongoDB:     mongoDB,
+		}, nil
+
+	case "memory":
+		// The in-memory repositories need no connection
+		return &Databases{}, nil
+	}
+
+	return nil, fmt.Errorf("unknown database type %q", config.Type)
+}
+
+// CheckSchemaVersion fails unless the Postgres schema was migrated to the version this binary
+// expects, the schema is only changed by the migrate command
+func CheckSchemaVersion(ctx context.Context, databases *Databases) error {
+	if databases.GormDB == nil {
+		return nil
+	}
+
+	sqlDB, err := databases.GormDB.DB()
+	if err != nil {
+		return err
 	}
+	return migrations.NewMigrator(sqlDB).CheckVersion(ctx)
 }
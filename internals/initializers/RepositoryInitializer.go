@@ -0,0 +1,61 @@
+package initializers
+
+import (
+	"fmt"
+
+	"github.com/lordofthemind/EventifyGo/internals/repositories"
+	"github.com/lordofthemind/EventifyGo/internals/repositories/inmemorydb"
+	"github.com/lordofthemind/EventifyGo/internals/repositories/mongodb"
+	"github.com/lordofthemind/EventifyGo/internals/repositories/postgresdb"
+)
+
+// Repositories holds one repository of every kind, all on the same backend
+type Repositories struct {
+	SuperUsers    repositories.SuperUserRepositoryInterface
+	Events        repositories.EventRepositoryInterface
+	Waitlist      repositories.WaitlistRepositoryInterface
+	RefreshTokens repositories.RefreshTokenRepositoryInterface
+	Sessions      repositories.SessionRepositoryInterface
+
+	// Failed logins only matter for a short while, they are kept in memory whatever the backend
+	LoginAttempts repositories.LoginAttemptRepositoryInterface
+}
+
+// RepositoryFactory builds the repositories of a database type on the connections opened by
+// DatabaseInitializer. The memory type needs no connection, its data is lost on exit.
+func RepositoryFactory(databaseType string, databases *Databases) (*Repositories, error) {
+	switch databaseType {
+	case "postgres":
+		return &Repositories{
+			SuperUsers:    postgresdb.NewPostgresSuperUserRepository(databases.GormDB),
+			Events:        postgresdb.NewPostgresEventRepository(databases.GormDB),
+			Waitlist:      postgresdb.NewPostgresWaitlistRepository(databases.GormDB),
+			RefreshTokens: postgresdb.NewPostgresRefreshTokenRepository(databases.GormDB),
+			Sessions:      postgresdb.NewPostgresSessionRepository(databases.GormDB),
+			LoginAttempts: inmemorydb.NewInMemoryLoginAttemptRepository(),
+		}, nil
+
+	case "mongodb":
+		// Events live alongside SuperUsers in the same database
+		return &Repositories{
+			SuperUsers:    mongodb.NewMongoSuperUserRepository(databases.MongoDB),
+			Events:        mongodb.NewMongoEventRepository(databases.MongoDB),
+			Waitlist:      mongodb.NewMongoWaitlistRepository(databases.MongoDB),
+			RefreshTokens: mongodb.NewMongoRefreshTokenRepository(databases.MongoDB),
+			Sessions:      mongodb.NewMongoSessionRepository(databases.MongoDB),
+			LoginAttempts: inmemorydb.NewInMemoryLoginAttemptRepository(),
+		}, nil
+
+	case "memory":
+		return &Repositories{
+			SuperUsers:    inmemorydb.NewInMemorySuperUserRepository(),
+			Events:        inmemorydb.NewInMemoryEventRepository(),
+			Waitlist:      inmemorydb.NewInMemoryWaitlistRepository(),
+			RefreshTokens: inmemorydb.NewInMemoryRefreshTokenRepository(),
+			Sessions:      inmemorydb.NewInMemorySessionRepository(),
+			LoginAttempts: inmemorydb.NewInMemoryLoginAttemptRepository(),
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unknown database type %q", databaseType)
+}
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS session_types;
+DROP TABLE IF EXISTS refresh_token_types;
+DROP TABLE IF EXISTS waitlist_entry_types;
+DROP TABLE IF EXISTS event_types;
+DROP TABLE IF EXISTS super_user_types;
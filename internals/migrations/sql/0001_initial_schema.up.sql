@@ -0,0 +1,83 @@
+-- Baseline matching the schema GORM AutoMigrate created, so databases it set up adopt it as is
+CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
+
+CREATE TABLE IF NOT EXISTS super_user_types (
+    id uuid DEFAULT uuid_generate_v4() PRIMARY KEY,
+    role text NOT NULL DEFAULT 'guest',
+    email text NOT NULL,
+    full_name text NOT NULL,
+    username text NOT NULL,
+    hashed_password text NOT NULL,
+    created_at timestamptz,
+    updated_at timestamptz,
+    reset_token_hash text,
+    reset_token_expires timestamptz,
+    is2_fa_enabled boolean DEFAULT false,
+    two_factor_secret text,
+    last_totp_step bigint NOT NULL DEFAULT 0,
+    recovery_code_hashes text[],
+    permission_groups text[],
+    CONSTRAINT uni_super_user_types_email UNIQUE (email),
+    CONSTRAINT uni_super_user_types_username UNIQUE (username)
+);
+CREATE INDEX IF NOT EXISTS idx_super_user_types_reset_token_hash ON super_user_types (reset_token_hash);
+
+CREATE TABLE IF NOT EXISTS event_types (
+    event_id uuid DEFAULT uuid_generate_v4() PRIMARY KEY,
+    name text NOT NULL,
+    description text,
+    date timestamptz NOT NULL,
+    location varchar(255),
+    capacity bigint NOT NULL,
+    created_at timestamptz,
+    updated_at timestamptz,
+    organizer_id uuid NOT NULL,
+    attendees uuid[],
+    status varchar(16),
+    end_date timestamptz,
+    time_zone varchar(64),
+    all_day boolean NOT NULL DEFAULT false,
+    external_id varchar(255),
+    recurrence_rule text,
+    recurrence_ex_dates jsonb,
+    series_id uuid,
+    recurrence_id timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_event_types_status ON event_types (status);
+CREATE INDEX IF NOT EXISTS idx_event_external_id ON event_types (external_id);
+CREATE INDEX IF NOT EXISTS idx_event_types_series_id ON event_types (series_id);
+
+CREATE TABLE IF NOT EXISTS waitlist_entry_types (
+    id uuid DEFAULT uuid_generate_v4() PRIMARY KEY,
+    event_id uuid NOT NULL,
+    user_id uuid NOT NULL,
+    created_at timestamptz NOT NULL
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_waitlist_event_user ON waitlist_entry_types (event_id, user_id);
+CREATE INDEX IF NOT EXISTS idx_waitlist_event_order ON waitlist_entry_types (event_id, created_at);
+
+CREATE TABLE IF NOT EXISTS refresh_token_types (
+    id uuid DEFAULT uuid_generate_v4() PRIMARY KEY,
+    family_id uuid NOT NULL,
+    super_user_id uuid NOT NULL,
+    token_hash text NOT NULL,
+    created_at timestamptz NOT NULL,
+    expires_at timestamptz NOT NULL,
+    rotated_at timestamptz,
+    revoked_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_refresh_token_types_family_id ON refresh_token_types (family_id);
+CREATE INDEX IF NOT EXISTS idx_refresh_token_types_super_user_id ON refresh_token_types (super_user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_refresh_token_types_token_hash ON refresh_token_types (token_hash);
+
+CREATE TABLE IF NOT EXISTS session_types (
+    id uuid PRIMARY KEY,
+    super_user_id uuid NOT NULL,
+    user_agent text,
+    ip_address text,
+    created_at timestamptz NOT NULL,
+    last_seen_at timestamptz NOT NULL,
+    expires_at timestamptz NOT NULL,
+    revoked_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_session_types_super_user_id ON session_types (super_user_id);
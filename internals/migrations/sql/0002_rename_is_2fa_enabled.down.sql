@@ -0,0 +1 @@
+ALTER TABLE super_user_types RENAME COLUMN is_2fa_enabled TO is2_fa_enabled;
@@ -0,0 +1,2 @@
+-- Match the column the queries and the MongoDB documents use
+ALTER TABLE super_user_types RENAME COLUMN is2_fa_enabled TO is_2fa_enabled;
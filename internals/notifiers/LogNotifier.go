@@ -0,0 +1,29 @@
+package notifiers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/lordofthemind/EventifyGo/internals/types"
+)
+
+// logNotifier writes messages to the application log. It is meant for development, the
+// log then holds live reset tokens, so plug in a mail or SMS notifier in production.
+type logNotifier struct {
+	logger *log.Logger
+}
+
+// NewLogNotifier returns a notifier writing to logger, the standard logger when nil
+func NewLogNotifier(logger *log.Logger) NotifierInterface {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return &logNotifier{logger: logger}
+}
+
+// SendPasswordReset logs the reset token of a SuperUser
+func (n *logNotifier) SendPasswordReset(ctx context.Context, superUser *types.SuperUserType, token string, expiresAt time.Time) error {
+	n.logger.Printf("Password reset requested for %s, token %s valid until %s", superUser.Email, token, expiresAt.Format(time.RFC3339))
+	return nil
+}
@@ -0,0 +1,14 @@
+package notifiers
+
+import (
+	"context"
+	"time"
+
+	"github.com/lordofthemind/EventifyGo/internals/types"
+)
+
+// NotifierInterface delivers messages to a SuperUser out of band, outside of any API response
+type NotifierInterface interface {
+	// Send the password reset token of a SuperUser, valid until expiresAt
+	SendPasswordReset(ctx context.Context, superUser *types.SuperUserType, token string, expiresAt time.Time) error
+}
@@ -0,0 +1,60 @@
+package rbac
+
+// Permissions checked by the middlewares and the services
+const (
+	PermissionSuperUsersRead  = "superusers:read"
+	PermissionSuperUsersWrite = "superusers:write"
+	PermissionSuperUsersRoles = "superusers:roles"
+	PermissionEventsWrite     = "events:write"
+)
+
+// Roles a SuperUser can hold, a SuperUser without a role is a guest
+const (
+	RoleAdmin   = "admin"
+	RoleManager = "manager"
+	RoleViewer  = "viewer"
+	RoleGuest   = "guest"
+)
+
+// rolePermissions maps every role to its permission set
+var rolePermissions = map[string][]string{
+	RoleAdmin:   {PermissionSuperUsersRead, PermissionSuperUsersWrite, PermissionSuperUsersRoles, PermissionEventsWrite},
+	RoleManager: {PermissionSuperUsersRead, PermissionSuperUsersWrite, PermissionEventsWrite},
+	RoleViewer:  {PermissionSuperUsersRead},
+	RoleGuest:   {},
+}
+
+// IsRole reports whether role is a known role
+func IsRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+// IsPermission reports whether permission is a known permission
+func IsPermission(permission string) bool {
+	switch permission {
+	case PermissionSuperUsersRead, PermissionSuperUsersWrite, PermissionSuperUsersRoles, PermissionEventsWrite:
+		return true
+	}
+	return false
+}
+
+// Permissions returns the permission set of a role extended by the permission groups of a
+// SuperUser. Unknown roles and permissions grant nothing.
+func Permissions(role string, permissionGroups []string) map[string]bool {
+	permissions := make(map[string]bool)
+	for _, permission := range rolePermissions[role] {
+		permissions[permission] = true
+	}
+	for _, permission := range permissionGroups {
+		if IsPermission(permission) {
+			permissions[permission] = true
+		}
+	}
+	return permissions
+}
+
+// Has reports whether a role and permission groups grant permission
+func Has(role string, permissionGroups []string, permission string) bool {
+	return Permissions(role, permissionGroups)[permission]
+}
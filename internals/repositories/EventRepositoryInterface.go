@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/lordofthemind/EventifyGo/internals/types"
@@ -20,12 +21,38 @@ type EventRepositoryInterface interface {
 	// DeleteEvent deletes an event by its ID.
 	DeleteEvent(ctx context.Context, eventID uuid.UUID) error
 
+	// The listing methods below only return the events visible to viewerID: published ones
+	// and the ones it organizes. uuid.Nil stands for an anonymous viewer.
+
 	// SearchEvents searches for events based on the search query, pagination, and sorting.
-	SearchEvents(ctx context.Context, searchQuery string, page, limit int, sortBy string) ([]*types.EventType, error)
+	SearchEvents(ctx context.Context, viewerID uuid.UUID, searchQuery string, page, limit int, sortBy string) ([]*types.EventType, error)
 
 	// ListEvents retrieves a list of events with pagination and sorting.
-	ListEvents(ctx context.Context, page, limit int, sortBy string) ([]*types.EventType, error)
+	ListEvents(ctx context.Context, viewerID uuid.UUID, page, limit int, sortBy string) ([]*types.EventType, error)
 
 	// CountEvents returns the count of events based on the search query.
-	CountEvents(ctx context.Context, searchQuery string) (int64, error)
+	CountEvents(ctx context.Context, viewerID uuid.UUID, searchQuery string) (int64, error)
+
+	// FindEventsInRange returns the events matching the search query that can produce an
+	// occurrence in [from, to): one-off events and overrides overlapping the window,
+	// overrides whose original occurrence falls inside it, and series masters starting before to.
+	FindEventsInRange(ctx context.Context, viewerID uuid.UUID, searchQuery string, from, to time.Time) ([]*types.EventType, error)
+
+	// ListSeriesOverrides returns the stored overrides of a recurring series.
+	ListSeriesOverrides(ctx context.Context, seriesID uuid.UUID) ([]*types.EventType, error)
+
+	// FindEventByExternalID returns the event an organizer imported under an external id.
+	// It returns ErrEventNotFound when there is none.
+	FindEventByExternalID(ctx context.Context, organizerID uuid.UUID, externalID string) (*types.EventType, error)
+
+	// ListEventsByParticipant returns every event a user organizes or attends.
+	ListEventsByParticipant(ctx context.Context, userID uuid.UUID) ([]*types.EventType, error)
+
+	// RegisterAttendee atomically adds an attendee as long as the event has a free seat.
+	// It returns ErrEventFull, ErrAlreadyRegistered or ErrEventNotFound when it cannot.
+	RegisterAttendee(ctx context.Context, eventID, attendeeID uuid.UUID) error
+
+	// UnregisterAttendee atomically removes an attendee from an event.
+	// It returns ErrNotRegistered or ErrEventNotFound when it cannot.
+	UnregisterAttendee(ctx context.Context, eventID, attendeeID uuid.UUID) error
 }
@@ -0,0 +1,23 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/lordofthemind/EventifyGo/internals/types"
+)
+
+type LoginAttemptRepositoryInterface interface {
+	// GetAttempts returns the failed logins recorded for a key, nil when there are none.
+	GetAttempts(ctx context.Context, key string) (*types.LoginAttemptType, error)
+
+	// RecordFailure counts a failed login at a time and returns the updated record.
+	// Failures older than window are forgotten first.
+	RecordFailure(ctx context.Context, key string, at time.Time, window time.Duration) (*types.LoginAttemptType, error)
+
+	// BlockUntil rejects further logins for a key until a time.
+	BlockUntil(ctx context.Context, key string, until time.Time) error
+
+	// ResetAttempts forgets every failed login recorded for a key.
+	ResetAttempts(ctx context.Context, key string) error
+}
@@ -0,0 +1,29 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lordofthemind/EventifyGo/internals/types"
+)
+
+type RefreshTokenRepositoryInterface interface {
+	// Create stores a new refresh token.
+	Create(ctx context.Context, token *types.RefreshTokenType) error
+
+	// FindByHash returns the refresh token with the given hash, rotated and revoked ones
+	// included. It returns ErrRefreshTokenNotFound if there is none.
+	FindByHash(ctx context.Context, tokenHash string) (*types.RefreshTokenType, error)
+
+	// MarkRotated records that a refresh token was traded for a new one. It returns
+	// ErrRefreshTokenUsed if the token was already rotated or revoked, so a token is
+	// rotated once even under concurrent refreshes.
+	MarkRotated(ctx context.Context, id uuid.UUID, at time.Time) error
+
+	// RevokeFamily revokes every token of a family that is not revoked yet.
+	RevokeFamily(ctx context.Context, familyID uuid.UUID, at time.Time) error
+
+	// RevokeAll revokes every token of a SuperUser that is not revoked yet.
+	RevokeAll(ctx context.Context, superUserID uuid.UUID, at time.Time) error
+}
@@ -0,0 +1,37 @@
+package repositories
+
+import "github.com/lordofthemind/EventifyGo/internals/apperrors"
+
+// Errors shared by every event repository backend
+var (
+	ErrEventNotFound     = apperrors.NotFound("event not found")
+	ErrDuplicateEvent    = apperrors.Conflict("an event with this id already exists")
+	ErrEventFull         = apperrors.Conflict("event is at full capacity")
+	ErrAlreadyRegistered = apperrors.Conflict("attendee is already registered for this event")
+	ErrNotRegistered     = apperrors.NotFound("attendee is not registered for this event")
+)
+
+// Errors shared by every waitlist repository backend
+var (
+	ErrAlreadyWaitlisted = apperrors.Conflict("user is already on the waitlist for this event")
+	ErrNotWaitlisted     = apperrors.NotFound("user is not on the waitlist for this event")
+	ErrWaitlistEmpty     = apperrors.NotFound("waitlist is empty")
+)
+
+// Errors shared by every superuser repository backend
+var (
+	ErrSuperUserNotFound    = apperrors.NotFound("superuser not found")
+	ErrDuplicateSuperUser   = apperrors.Conflict("a superuser with this email or username already exists")
+	ErrTOTPStepUsed         = apperrors.Conflict("one-time code was already used")
+	ErrRecoveryCodeNotFound = apperrors.NotFound("recovery code is unknown or already used")
+	ErrResetTokenNotFound   = apperrors.NotFound("reset token is unknown, expired or already used")
+)
+
+// Errors shared by every refresh token repository backend
+var (
+	ErrRefreshTokenNotFound = apperrors.NotFound("refresh token not found")
+	ErrRefreshTokenUsed     = apperrors.Conflict("refresh token was already rotated or revoked")
+)
+
+// Errors shared by every session repository backend
+var ErrSessionNotFound = apperrors.NotFound("session not found")
@@ -0,0 +1,35 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lordofthemind/EventifyGo/internals/types"
+)
+
+type SessionRepositoryInterface interface {
+	// Create stores a new session.
+	Create(ctx context.Context, session *types.SessionType) error
+
+	// FindByID returns a session, revoked and expired ones included. It returns
+	// ErrSessionNotFound if there is none.
+	FindByID(ctx context.Context, id uuid.UUID) (*types.SessionType, error)
+
+	// ListActive returns the sessions of a SuperUser that are neither revoked nor expired at
+	// now, most recently seen first.
+	ListActive(ctx context.Context, superUserID uuid.UUID, now time.Time) ([]*types.SessionType, error)
+
+	// Touch records the time and IP address a session was last used from.
+	Touch(ctx context.Context, id uuid.UUID, at time.Time, ipAddress string) error
+
+	// Extend moves the expiry of a session, when its refresh token is rotated.
+	Extend(ctx context.Context, id uuid.UUID, expiresAt time.Time) error
+
+	// Revoke revokes a session, revoking it again keeps the first time. It returns
+	// ErrSessionNotFound if there is none.
+	Revoke(ctx context.Context, id uuid.UUID, at time.Time) error
+
+	// RevokeAll revokes every session of a SuperUser that is not revoked yet.
+	RevokeAll(ctx context.Context, superUserID uuid.UUID, at time.Time) error
+}
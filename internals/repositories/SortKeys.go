@@ -0,0 +1,48 @@
+package repositories
+
+import (
+	"fmt"
+
+	"github.com/lordofthemind/EventifyGo/internals/apperrors"
+)
+
+// ErrInvalidSortKey is returned for a sort key outside the allowlists below
+var ErrInvalidSortKey = apperrors.Validation("unknown sort key")
+
+// Sort keys clients may ask for, mapped to the column or field they sort by. The names are the
+// same in Postgres and MongoDB. Repositories only ever sort by a value taken from these maps.
+var (
+	eventSortKeys = map[string]string{
+		"date":       "date",
+		"name":       "name",
+		"location":   "location",
+		"capacity":   "capacity",
+		"created_at": "created_at",
+		"updated_at": "updated_at",
+	}
+	superUserSortKeys = map[string]string{
+		"created_at": "created_at",
+		"updated_at": "updated_at",
+		"username":   "username",
+		"email":      "email",
+		"full_name":  "full_name",
+	}
+)
+
+// EventSortKey returns the column an Event sort key stands for
+func EventSortKey(sortBy string) (string, error) {
+	return lookupSortKey(eventSortKeys, sortBy)
+}
+
+// SuperUserSortKey returns the column a SuperUser sort key stands for
+func SuperUserSortKey(sortBy string) (string, error) {
+	return lookupSortKey(superUserSortKeys, sortBy)
+}
+
+func lookupSortKey(keys map[string]string, sortBy string) (string, error) {
+	column, ok := keys[sortBy]
+	if !ok {
+		return "", fmt.Errorf("%w %q", ErrInvalidSortKey, sortBy)
+	}
+	return column, nil
+}
@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/lordofthemind/EventifyGo/internals/types"
@@ -13,7 +14,6 @@ type SuperUserRepositoryInterface interface {
 	FindByID(ctx context.Context, id uuid.UUID) (*types.SuperUserType, error)
 	FindByEmail(ctx context.Context, email string) (*types.SuperUserType, error)
 	FindByUsername(ctx context.Context, username string) (*types.SuperUserType, error)
-	FindByResetToken(ctx context.Context, token string) (*types.SuperUserType, error)
 	DeleteByID(ctx context.Context, id uuid.UUID) error
 
 	// Search methods
@@ -22,15 +22,28 @@ type SuperUserRepositoryInterface interface {
 	// Field updates
 	Update(ctx context.Context, superUser *types.SuperUserType) error
 	UpdateField(ctx context.Context, id uuid.UUID, field string, value interface{}) error
+	UpdateFields(ctx context.Context, id uuid.UUID, fields map[string]interface{}) error
 
 	// Specialized queries
 	GetRoleByID(ctx context.Context, id uuid.UUID) (string, error)
 	FindAll2FAEnabledSuperusers(ctx context.Context) ([]*types.SuperUserType, error)
 
 	// Specific field updates
-	UpdateResetToken(ctx context.Context, id uuid.UUID, token string) error
 	UpdateSuperuserRole(ctx context.Context, id uuid.UUID, role string) error
 
+	// Store the hash of a password reset token, replacing any pending one
+	SetResetToken(ctx context.Context, id uuid.UUID, tokenHash string, expiresAt time.Time) error
+
+	// Set a new password hash and clear the reset token in one step and return the ID of the
+	// SuperUser, ErrResetTokenNotFound unless the token hash is stored and has not expired
+	ResetPassword(ctx context.Context, tokenHash, hashedPassword string) (uuid.UUID, error)
+
+	// Record the time step of an accepted one-time code, ErrTOTPStepUsed unless it is later than the last one
+	AdvanceTOTPStep(ctx context.Context, id uuid.UUID, step int64) error
+
+	// Remove a recovery code hash once used, ErrRecoveryCodeNotFound when it is not stored
+	ConsumeRecoveryCode(ctx context.Context, id uuid.UUID, codeHash string) error
+
 	// Get all SuperUsers
 	GetAllSuperUsers(ctx context.Context) ([]*types.SuperUserType, error)
 }
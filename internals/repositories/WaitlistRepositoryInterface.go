@@ -0,0 +1,30 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/lordofthemind/EventifyGo/internals/types"
+)
+
+type WaitlistRepositoryInterface interface {
+	// AddToWaitlist appends an entry to the end of an event's waitlist.
+	// It returns ErrAlreadyWaitlisted if the user is already waiting for the event.
+	AddToWaitlist(ctx context.Context, entry *types.WaitlistEntryType) error
+
+	// RemoveFromWaitlist removes a user from an event's waitlist.
+	// It returns ErrNotWaitlisted if the user is not waiting for the event.
+	RemoveFromWaitlist(ctx context.Context, eventID, userID uuid.UUID) error
+
+	// GetWaitlistEntry returns a user's entry with its 1-based Position filled in.
+	GetWaitlistEntry(ctx context.Context, eventID, userID uuid.UUID) (*types.WaitlistEntryType, error)
+
+	// FirstInWaitlist returns the entry at the head of the waitlist, or ErrWaitlistEmpty.
+	FirstInWaitlist(ctx context.Context, eventID uuid.UUID) (*types.WaitlistEntryType, error)
+
+	// ListWaitlist returns an event's waitlist in order with positions filled in.
+	ListWaitlist(ctx context.Context, eventID uuid.UUID) ([]*types.WaitlistEntryType, error)
+
+	// ClearWaitlist removes every entry for an event.
+	ClearWaitlist(ctx context.Context, eventID uuid.UUID) error
+}
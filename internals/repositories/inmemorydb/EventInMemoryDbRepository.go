@@ -2,7 +2,7 @@ package inmemorydb
 
 import (
 	"context"
-	"errors"
+	"sort"
 	"sync"
 	"time"
 
@@ -40,7 +40,7 @@ func (r *inMemoryEventRepository) GetEventByID(ctx context.Context, eventID uuid
 
 	event, exists := r.events[eventID]
 	if !exists {
-		return nil, errors.New("event not found")
+		return nil, repositories.ErrEventNotFound
 	}
 	return event, nil
 }
@@ -49,10 +49,13 @@ func (r *inMemoryEventRepository) UpdateEvent(ctx context.Context, event *types.
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	if _, exists := r.events[event.EventID]; !exists {
-		return errors.New("event not found")
+	existing, exists := r.events[event.EventID]
+	if !exists {
+		return repositories.ErrEventNotFound
 	}
 
+	// Attendees are only changed through RegisterAttendee and UnregisterAttendee
+	event.Attendees = existing.Attendees
 	event.UpdatedAt = time.Now()
 	r.events[event.EventID] = event
 	return nil
@@ -63,24 +66,30 @@ func (r *inMemoryEventRepository) DeleteEvent(ctx context.Context, eventID uuid.
 	defer r.mu.Unlock()
 
 	if _, exists := r.events[eventID]; !exists {
-		return errors.New("event not found")
+		return repositories.ErrEventNotFound
 	}
 
 	delete(r.events, eventID)
 	return nil
 }
 
-func (r *inMemoryEventRepository) SearchEvents(ctx context.Context, searchQuery string, page, limit int, sortBy string) ([]*types.EventType, error) {
+func (r *inMemoryEventRepository) SearchEvents(ctx context.Context, viewerID uuid.UUID, searchQuery string, page, limit int, sortBy string) ([]*types.EventType, error) {
+	column, err := repositories.EventSortKey(sortBy)
+	if err != nil {
+		return nil, err
+	}
+
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
 	// Basic search by name or description (case insensitive)
 	var result []*types.EventType
 	for _, event := range r.events {
-		if matchEvent(event, searchQuery) {
+		if visibleTo(event, viewerID) && matchEvent(event, searchQuery) {
 			result = append(result, event)
 		}
 	}
+	sortEvents(result, column)
 
 	// Pagination
 	start := (page - 1) * limit
@@ -95,14 +104,22 @@ func (r *inMemoryEventRepository) SearchEvents(ctx context.Context, searchQuery
 	return result[start:end], nil
 }
 
-func (r *inMemoryEventRepository) ListEvents(ctx context.Context, page, limit int, sortBy string) ([]*types.EventType, error) {
+func (r *inMemoryEventRepository) ListEvents(ctx context.Context, viewerID uuid.UUID, page, limit int, sortBy string) ([]*types.EventType, error) {
+	column, err := repositories.EventSortKey(sortBy)
+	if err != nil {
+		return nil, err
+	}
+
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
 	var result []*types.EventType
 	for _, event := range r.events {
-		result = append(result, event)
+		if visibleTo(event, viewerID) {
+			result = append(result, event)
+		}
 	}
+	sortEvents(result, column)
 
 	// Pagination
 	start := (page - 1) * limit
@@ -117,19 +134,145 @@ func (r *inMemoryEventRepository) ListEvents(ctx context.Context, page, limit in
 	return result[start:end], nil
 }
 
-func (r *inMemoryEventRepository) CountEvents(ctx context.Context, searchQuery string) (int64, error) {
+func (r *inMemoryEventRepository) CountEvents(ctx context.Context, viewerID uuid.UUID, searchQuery string) (int64, error) {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
 	var count int64
 	for _, event := range r.events {
-		if matchEvent(event, searchQuery) {
+		if visibleTo(event, viewerID) && matchEvent(event, searchQuery) {
 			count++
 		}
 	}
 	return count, nil
 }
 
+func (r *inMemoryEventRepository) FindEventsInRange(ctx context.Context, viewerID uuid.UUID, searchQuery string, from, to time.Time) ([]*types.EventType, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := []*types.EventType{}
+	for _, event := range r.events {
+		if visibleTo(event, viewerID) && matchEvent(event, searchQuery) && inRange(event, from, to) {
+			result = append(result, event)
+		}
+	}
+	return result, nil
+}
+
+func (r *inMemoryEventRepository) ListSeriesOverrides(ctx context.Context, seriesID uuid.UUID) ([]*types.EventType, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := []*types.EventType{}
+	for _, event := range r.events {
+		if event.SeriesID != nil && *event.SeriesID == seriesID {
+			result = append(result, event)
+		}
+	}
+	return result, nil
+}
+
+func (r *inMemoryEventRepository) FindEventByExternalID(ctx context.Context, organizerID uuid.UUID, externalID string) (*types.EventType, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, event := range r.events {
+		if event.OrganizerID == organizerID && event.ExternalID == externalID {
+			return event, nil
+		}
+	}
+	return nil, repositories.ErrEventNotFound
+}
+
+func (r *inMemoryEventRepository) ListEventsByParticipant(ctx context.Context, userID uuid.UUID) ([]*types.EventType, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := []*types.EventType{}
+	for _, event := range r.events {
+		if event.OrganizerID == userID || containsAttendee(event.Attendees, userID) {
+			result = append(result, event)
+		}
+	}
+	return result, nil
+}
+
+func (r *inMemoryEventRepository) RegisterAttendee(ctx context.Context, eventID, attendeeID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	event, exists := r.events[eventID]
+	if !exists {
+		return repositories.ErrEventNotFound
+	}
+	if containsAttendee(event.Attendees, attendeeID) {
+		return repositories.ErrAlreadyRegistered
+	}
+	if len(event.Attendees) >= event.Capacity {
+		return repositories.ErrEventFull
+	}
+
+	// Store a copy so callers holding the previous pointer never see a partial write
+	updated := *event
+	updated.Attendees = append(append([]uuid.UUID{}, event.Attendees...), attendeeID)
+	updated.UpdatedAt = time.Now()
+	r.events[eventID] = &updated
+	return nil
+}
+
+func (r *inMemoryEventRepository) UnregisterAttendee(ctx context.Context, eventID, attendeeID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	event, exists := r.events[eventID]
+	if !exists {
+		return repositories.ErrEventNotFound
+	}
+	if !containsAttendee(event.Attendees, attendeeID) {
+		return repositories.ErrNotRegistered
+	}
+
+	updated := *event
+	updated.Attendees = make([]uuid.UUID, 0, len(event.Attendees)-1)
+	for _, id := range event.Attendees {
+		if id != attendeeID {
+			updated.Attendees = append(updated.Attendees, id)
+		}
+	}
+	updated.UpdatedAt = time.Now()
+	r.events[eventID] = &updated
+	return nil
+}
+
+// Helper function to check whether an attendee is in the list
+func containsAttendee(attendees []uuid.UUID, attendeeID uuid.UUID) bool {
+	for _, id := range attendees {
+		if id == attendeeID {
+			return true
+		}
+	}
+	return false
+}
+
+// Helper function to check whether an event can produce an occurrence in [from, to)
+func inRange(event *types.EventType, from, to time.Time) bool {
+	overlapping := event.Date.Before(to) && (!event.Date.Before(from) || event.End().After(from))
+	switch {
+	case event.IsRecurring():
+		return event.Date.Before(to)
+	case event.IsOccurrenceOverride():
+		return overlapping || (!event.RecurrenceID.Before(from) && event.RecurrenceID.Before(to))
+	default:
+		return overlapping
+	}
+}
+
+// Helper function to tell whether a viewer may see an event in listings
+func visibleTo(event *types.EventType, viewerID uuid.UUID) bool {
+	return event.IsVisibleTo(viewerID)
+}
+
 // Helper function to match an event with a search query
 func matchEvent(event *types.EventType, query string) bool {
 	return eventContainsIgnoreCase(event.Name, query) ||
@@ -140,3 +283,38 @@ func matchEvent(event *types.EventType, query string) bool {
 func eventContainsIgnoreCase(s, substr string) bool {
 	return len(s) >= len(substr) && s[:len(substr)] == substr
 }
+
+// Helper function to order events by a sort key column like the databases do, ties are broken
+// by ID so a page always holds the same events
+func sortEvents(events []*types.EventType, column string) {
+	sort.Slice(events, func(i, j int) bool {
+		a, b := events[i], events[j]
+		switch column {
+		case "date":
+			if !a.Date.Equal(b.Date) {
+				return a.Date.Before(b.Date)
+			}
+		case "name":
+			if a.Name != b.Name {
+				return a.Name < b.Name
+			}
+		case "location":
+			if a.Location != b.Location {
+				return a.Location < b.Location
+			}
+		case "capacity":
+			if a.Capacity != b.Capacity {
+				return a.Capacity < b.Capacity
+			}
+		case "created_at":
+			if !a.CreatedAt.Equal(b.CreatedAt) {
+				return a.CreatedAt.Before(b.CreatedAt)
+			}
+		case "updated_at":
+			if !a.UpdatedAt.Equal(b.UpdatedAt) {
+				return a.UpdatedAt.Before(b.UpdatedAt)
+			}
+		}
+		return a.EventID.String() < b.EventID.String()
+	})
+}
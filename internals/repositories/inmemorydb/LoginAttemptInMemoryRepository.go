@@ -0,0 +1,117 @@
+package inmemorydb
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/lordofthemind/EventifyGo/internals/repositories"
+	"github.com/lordofthemind/EventifyGo/internals/types"
+)
+
+// loginAttemptSweepEvery is the number of recorded failures between sweeps of stale records
+const loginAttemptSweepEvery = 1024
+
+type inMemoryLoginAttemptRepository struct {
+	mu       sync.Mutex
+	attempts map[string]*loginAttemptRecord
+	writes   int
+}
+
+// loginAttemptRecord is a stored record with the time it can be forgotten
+type loginAttemptRecord struct {
+	attempts  types.LoginAttemptType
+	expiresAt time.Time
+}
+
+// NewInMemoryLoginAttemptRepository initializes an in-memory login attempt store. Its state
+// is per process, run a shared store when several instances serve logins.
+func NewInMemoryLoginAttemptRepository() repositories.LoginAttemptRepositoryInterface {
+	return &inMemoryLoginAttemptRepository{
+		attempts: make(map[string]*loginAttemptRecord),
+	}
+}
+
+// GetAttempts returns a copy of the record of a key
+func (r *inMemoryLoginAttemptRepository) GetAttempts(ctx context.Context, key string) (*types.LoginAttemptType, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	record, exists := r.attempts[key]
+	if !exists {
+		return nil, nil
+	}
+	if time.Now().After(record.expiresAt) {
+		delete(r.attempts, key)
+		return nil, nil
+	}
+	attempts := record.attempts
+	return &attempts, nil
+}
+
+// RecordFailure counts a failed login for a key
+func (r *inMemoryLoginAttemptRepository) RecordFailure(ctx context.Context, key string, at time.Time, window time.Duration) (*types.LoginAttemptType, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.writes++
+	if r.writes%loginAttemptSweepEvery == 0 {
+		r.sweep(at)
+	}
+
+	record, exists := r.attempts[key]
+	if !exists {
+		record = &loginAttemptRecord{attempts: types.LoginAttemptType{Key: key}}
+		r.attempts[key] = record
+	}
+	if at.Sub(record.attempts.LastFailure) > window {
+		record.attempts.Failures = 0
+	}
+	record.attempts.Failures++
+	record.attempts.LastFailure = at
+	record.expiresAt = latest(at.Add(window), record.attempts.BlockedUntil)
+
+	attempts := record.attempts
+	return &attempts, nil
+}
+
+// BlockUntil blocks a key until a time
+func (r *inMemoryLoginAttemptRepository) BlockUntil(ctx context.Context, key string, until time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	record, exists := r.attempts[key]
+	if !exists {
+		record = &loginAttemptRecord{attempts: types.LoginAttemptType{Key: key}}
+		r.attempts[key] = record
+	}
+	record.attempts.BlockedUntil = until
+	record.expiresAt = latest(record.expiresAt, until)
+	return nil
+}
+
+// ResetAttempts forgets a key
+func (r *inMemoryLoginAttemptRepository) ResetAttempts(ctx context.Context, key string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.attempts, key)
+	return nil
+}
+
+// Helper function to drop every record that can be forgotten, called with the lock held
+func (r *inMemoryLoginAttemptRepository) sweep(now time.Time) {
+	for key, record := range r.attempts {
+		if now.After(record.expiresAt) {
+			delete(r.attempts, key)
+		}
+	}
+}
+
+// Helper function to return the later of two times
+func latest(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,92 @@
+package inmemorydb
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lordofthemind/EventifyGo/internals/repositories"
+	"github.com/lordofthemind/EventifyGo/internals/types"
+)
+
+type inMemoryRefreshTokenRepository struct {
+	mu     sync.RWMutex
+	tokens map[uuid.UUID]*types.RefreshTokenType
+	hashes map[string]uuid.UUID
+}
+
+// NewInMemoryRefreshTokenRepository initializes an in-memory refresh token store.
+func NewInMemoryRefreshTokenRepository() repositories.RefreshTokenRepositoryInterface {
+	return &inMemoryRefreshTokenRepository{
+		tokens: make(map[uuid.UUID]*types.RefreshTokenType),
+		hashes: make(map[string]uuid.UUID),
+	}
+}
+
+func (r *inMemoryRefreshTokenRepository) Create(ctx context.Context, token *types.RefreshTokenType) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if token.ID == uuid.Nil {
+		token.ID = uuid.New()
+	}
+	if token.CreatedAt.IsZero() {
+		token.CreatedAt = time.Now()
+	}
+	stored := *token
+	r.tokens[stored.ID] = &stored
+	r.hashes[stored.TokenHash] = stored.ID
+	return nil
+}
+
+func (r *inMemoryRefreshTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*types.RefreshTokenType, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	id, exists := r.hashes[tokenHash]
+	if !exists {
+		return nil, repositories.ErrRefreshTokenNotFound
+	}
+	token := *r.tokens[id]
+	return &token, nil
+}
+
+func (r *inMemoryRefreshTokenRepository) MarkRotated(ctx context.Context, id uuid.UUID, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	token, exists := r.tokens[id]
+	if !exists {
+		return repositories.ErrRefreshTokenNotFound
+	}
+	if token.RotatedAt != nil || token.RevokedAt != nil {
+		return repositories.ErrRefreshTokenUsed
+	}
+	token.RotatedAt = &at
+	return nil
+}
+
+func (r *inMemoryRefreshTokenRepository) RevokeFamily(ctx context.Context, familyID uuid.UUID, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, token := range r.tokens {
+		if token.FamilyID == familyID && token.RevokedAt == nil {
+			token.RevokedAt = &at
+		}
+	}
+	return nil
+}
+
+func (r *inMemoryRefreshTokenRepository) RevokeAll(ctx context.Context, superUserID uuid.UUID, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, token := range r.tokens {
+		if token.SuperUserID == superUserID && token.RevokedAt == nil {
+			token.RevokedAt = &at
+		}
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package inmemorydb
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lordofthemind/EventifyGo/internals/repositories"
+	"github.com/lordofthemind/EventifyGo/internals/types"
+)
+
+type inMemorySessionRepository struct {
+	mu       sync.RWMutex
+	sessions map[uuid.UUID]*types.SessionType
+}
+
+// NewInMemorySessionRepository initializes an in-memory session store.
+func NewInMemorySessionRepository() repositories.SessionRepositoryInterface {
+	return &inMemorySessionRepository{
+		sessions: make(map[uuid.UUID]*types.SessionType),
+	}
+}
+
+func (r *inMemorySessionRepository) Create(ctx context.Context, session *types.SessionType) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if session.ID == uuid.Nil {
+		session.ID = uuid.New()
+	}
+	if session.CreatedAt.IsZero() {
+		session.CreatedAt = time.Now()
+	}
+	stored := *session
+	r.sessions[stored.ID] = &stored
+	return nil
+}
+
+func (r *inMemorySessionRepository) FindByID(ctx context.Context, id uuid.UUID) (*types.SessionType, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	session, exists := r.sessions[id]
+	if !exists {
+		return nil, repositories.ErrSessionNotFound
+	}
+	found := *session
+	return &found, nil
+}
+
+func (r *inMemorySessionRepository) ListActive(ctx context.Context, superUserID uuid.UUID, now time.Time) ([]*types.SessionType, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	sessions := []*types.SessionType{}
+	for _, session := range r.sessions {
+		if session.SuperUserID == superUserID && session.RevokedAt == nil && session.ExpiresAt.After(now) {
+			found := *session
+			sessions = append(sessions, &found)
+		}
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastSeenAt.After(sessions[j].LastSeenAt)
+	})
+	return sessions, nil
+}
+
+func (r *inMemorySessionRepository) Touch(ctx context.Context, id uuid.UUID, at time.Time, ipAddress string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	session, exists := r.sessions[id]
+	if !exists {
+		return repositories.ErrSessionNotFound
+	}
+	session.LastSeenAt = at
+	session.IPAddress = ipAddress
+	return nil
+}
+
+func (r *inMemorySessionRepository) Extend(ctx context.Context, id uuid.UUID, expiresAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	session, exists := r.sessions[id]
+	if !exists {
+		return repositories.ErrSessionNotFound
+	}
+	session.ExpiresAt = expiresAt
+	return nil
+}
+
+func (r *inMemorySessionRepository) Revoke(ctx context.Context, id uuid.UUID, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	session, exists := r.sessions[id]
+	if !exists {
+		return repositories.ErrSessionNotFound
+	}
+	if session.RevokedAt == nil {
+		session.RevokedAt = &at
+	}
+	return nil
+}
+
+func (r *inMemorySessionRepository) RevokeAll(ctx context.Context, superUserID uuid.UUID, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, session := range r.sessions {
+		if session.SuperUserID == superUserID && session.RevokedAt == nil {
+			session.RevokedAt = &at
+		}
+	}
+	return nil
+}
@@ -2,7 +2,7 @@ package inmemorydb
 
 import (
 	"context"
-	"errors"
+	"sort"
 	"sync"
 	"time"
 
@@ -28,6 +28,13 @@ func (r *inMemorySuperUserRepository) Create(ctx context.Context, superUser *typ
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	// Email and username are unique like in the database backends
+	for _, existing := range r.superUsers {
+		if existing.Email == superUser.Email || existing.Username == superUser.Username {
+			return repositories.ErrDuplicateSuperUser
+		}
+	}
+
 	superUser.ID = uuid.New()
 	superUser.CreatedAt = time.Now()
 	superUser.UpdatedAt = time.Now()
@@ -44,7 +51,7 @@ func (r *inMemorySuperUserRepository) FindByID(ctx context.Context, id uuid.UUID
 	if superUser, exists := r.superUsers[id]; exists {
 		return superUser, nil
 	}
-	return nil, errors.New("superuser not found")
+	return nil, repositories.ErrSuperUserNotFound
 }
 
 // FindByEmail finds a super user by their email address
@@ -57,7 +64,7 @@ func (r *inMemorySuperUserRepository) FindByEmail(ctx context.Context, email str
 			return superUser, nil
 		}
 	}
-	return nil, errors.New("superuser not found")
+	return nil, repositories.ErrSuperUserNotFound
 }
 
 // FindByUsername finds a super user by their username
@@ -70,20 +77,7 @@ func (r *inMemorySuperUserRepository) FindByUsername(ctx context.Context, userna
 			return superUser, nil
 		}
 	}
-	return nil, errors.New("superuser not found")
-}
-
-// FindByResetToken finds a super user by their reset token
-func (r *inMemorySuperUserRepository) FindByResetToken(ctx context.Context, token string) (*types.SuperUserType, error) {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
-
-	for _, superUser := range r.superUsers {
-		if superUser.ResetToken != nil && *superUser.ResetToken == token {
-			return superUser, nil
-		}
-	}
-	return nil, errors.New("superuser not found")
+	return nil, repositories.ErrSuperUserNotFound
 }
 
 // DeleteByID deletes a super user by their ID
@@ -95,11 +89,16 @@ func (r *inMemorySuperUserRepository) DeleteByID(ctx context.Context, id uuid.UU
 		delete(r.superUsers, id)
 		return nil
 	}
-	return errors.New("superuser not found")
+	return repositories.ErrSuperUserNotFound
 }
 
 // SearchSuperusers searches for super users based on a search query
 func (r *inMemorySuperUserRepository) SearchSuperusers(ctx context.Context, searchQuery string, page, limit int, sortBy string) ([]*types.SuperUserType, error) {
+	field, err := repositories.SuperUserSortKey(sortBy)
+	if err != nil {
+		return nil, err
+	}
+
 	r.mu.RLock()
 	defer r.mu.RUnlock()
 
@@ -109,11 +108,12 @@ func (r *inMemorySuperUserRepository) SearchSuperusers(ctx context.Context, sear
 			results = append(results, superUser)
 		}
 	}
+	sortSuperUsers(results, field)
 
 	// Apply pagination
 	start := (page - 1) * limit
 	if start >= len(results) {
-		return nil, nil
+		return []*types.SuperUserType{}, nil
 	}
 
 	end := start + limit
@@ -135,7 +135,7 @@ func (r *inMemorySuperUserRepository) Update(ctx context.Context, superUser *typ
 		r.superUsers[superUser.ID] = superUser
 		return nil
 	}
-	return errors.New("superuser not found")
+	return repositories.ErrSuperUserNotFound
 }
 
 // UpdateField updates a specific field for a super user
@@ -145,19 +145,37 @@ func (r *inMemorySuperUserRepository) UpdateField(ctx context.Context, id uuid.U
 
 	if superUser, exists := r.superUsers[id]; exists {
 		superUser.UpdatedAt = time.Now()
-
-		switch field {
-		case "role":
-			superUser.Role = value.(string)
-		case "reset_token":
-			token := value.(string)
-			superUser.ResetToken = &token
-			// Add more cases as needed
-		}
+		setField(superUser, field, value)
 		r.superUsers[id] = superUser
 		return nil
 	}
-	return errors.New("superuser not found")
+	return repositories.ErrSuperUserNotFound
+}
+
+// UpdateFields updates several fields of a super user at once
+func (r *inMemorySuperUserRepository) UpdateFields(ctx context.Context, id uuid.UUID, fields map[string]interface{}) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	superUser, exists := r.superUsers[id]
+	if !exists {
+		return repositories.ErrSuperUserNotFound
+	}
+
+	// Email and username stay unique like in the database backends
+	email, _ := fields["email"].(string)
+	username, _ := fields["username"].(string)
+	for otherID, other := range r.superUsers {
+		if otherID != id && ((email != "" && other.Email == email) || (username != "" && other.Username == username)) {
+			return repositories.ErrDuplicateSuperUser
+		}
+	}
+
+	for field, value := range fields {
+		setField(superUser, field, value)
+	}
+	superUser.UpdatedAt = time.Now()
+	return nil
 }
 
 // GetRoleByID returns the role of a super user by their ID
@@ -168,7 +186,7 @@ func (r *inMemorySuperUserRepository) GetRoleByID(ctx context.Context, id uuid.U
 	if superUser, exists := r.superUsers[id]; exists {
 		return superUser.Role, nil
 	}
-	return "", errors.New("role not found")
+	return "", repositories.ErrSuperUserNotFound
 }
 
 // FindAll2FAEnabledSuperusers finds all super users with 2FA enabled
@@ -185,34 +203,117 @@ func (r *inMemorySuperUserRepository) FindAll2FAEnabledSuperusers(ctx context.Co
 	return superUsers, nil
 }
 
-// UpdateResetToken updates the reset token for a super user
-func (r *inMemorySuperUserRepository) UpdateResetToken(ctx context.Context, id uuid.UUID, token string) error {
-	return r.UpdateField(ctx, id, "reset_token", token)
-}
-
 // UpdateSuperuserRole updates the role of a super user
 func (r *inMemorySuperUserRepository) UpdateSuperuserRole(ctx context.Context, id uuid.UUID, role string) error {
 	return r.UpdateField(ctx, id, "role", role)
 }
 
+// SetResetToken stores the hash of a password reset token
+func (r *inMemorySuperUserRepository) SetResetToken(ctx context.Context, id uuid.UUID, tokenHash string, expiresAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	superUser, exists := r.superUsers[id]
+	if !exists {
+		return repositories.ErrSuperUserNotFound
+	}
+	superUser.ResetTokenHash = &tokenHash
+	superUser.ResetTokenExpires = &expiresAt
+	superUser.UpdatedAt = time.Now()
+	return nil
+}
+
+// ResetPassword sets a new password for the holder of a valid reset token
+func (r *inMemorySuperUserRepository) ResetPassword(ctx context.Context, tokenHash, hashedPassword string) (uuid.UUID, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for _, superUser := range r.superUsers {
+		if superUser.ResetTokenHash == nil || *superUser.ResetTokenHash != tokenHash {
+			continue
+		}
+		if superUser.ResetTokenExpires == nil || !superUser.ResetTokenExpires.After(now) {
+			break
+		}
+		superUser.HashedPassword = hashedPassword
+		superUser.ResetTokenHash = nil
+		superUser.ResetTokenExpires = nil
+		superUser.UpdatedAt = now
+		return superUser.ID, nil
+	}
+	return uuid.Nil, repositories.ErrResetTokenNotFound
+}
+
+// AdvanceTOTPStep records the time step of an accepted one-time code
+func (r *inMemorySuperUserRepository) AdvanceTOTPStep(ctx context.Context, id uuid.UUID, step int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	superUser, exists := r.superUsers[id]
+	if !exists {
+		return repositories.ErrSuperUserNotFound
+	}
+	if superUser.LastTOTPStep >= step {
+		return repositories.ErrTOTPStepUsed
+	}
+	superUser.LastTOTPStep = step
+	superUser.UpdatedAt = time.Now()
+	return nil
+}
+
+// ConsumeRecoveryCode removes a used recovery code hash
+func (r *inMemorySuperUserRepository) ConsumeRecoveryCode(ctx context.Context, id uuid.UUID, codeHash string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	superUser, exists := r.superUsers[id]
+	if !exists {
+		return repositories.ErrSuperUserNotFound
+	}
+	for i, hash := range superUser.RecoveryCodeHashes {
+		if hash == codeHash {
+			remaining := make([]string, 0, len(superUser.RecoveryCodeHashes)-1)
+			remaining = append(remaining, superUser.RecoveryCodeHashes[:i]...)
+			superUser.RecoveryCodeHashes = append(remaining, superUser.RecoveryCodeHashes[i+1:]...)
+			superUser.UpdatedAt = time.Now()
+			return nil
+		}
+	}
+	return repositories.ErrRecoveryCodeNotFound
+}
+
 // GetAllSuperUsers retrieves all super users from the in-memory store
 func (r *inMemorySuperUserRepository) GetAllSuperUsers(ctx context.Context) ([]*types.SuperUserType, error) {
 	r.mu.RLock() // Use read lock for concurrent access
 	defer r.mu.RUnlock()
 
 	// Create a slice to store all super users
-	var allSuperUsers []*types.SuperUserType
+	allSuperUsers := []*types.SuperUserType{}
 	for _, superUser := range r.superUsers {
 		allSuperUsers = append(allSuperUsers, superUser)
 	}
 
-	if len(allSuperUsers) == 0 {
-		return nil, errors.New("no super users found")
-	}
-
 	return allSuperUsers, nil
 }
 
+// Helper function to set a field by its column name
+func setField(superUser *types.SuperUserType, field string, value interface{}) {
+	switch field {
+	case "role":
+		superUser.Role = value.(string)
+	case "permission_groups":
+		superUser.PermissionGroups = value.([]string)
+	case "email":
+		superUser.Email = value.(string)
+	case "full_name":
+		superUser.FullName = value.(string)
+	case "username":
+		superUser.Username = value.(string)
+		// Add more cases as needed
+	}
+}
+
 // Helper function to match the search query
 func matchesQuery(superUser *types.SuperUserType, query string) bool {
 	return superUserContainsIgnoreCase(superUser.FullName, query) ||
@@ -224,3 +325,34 @@ func matchesQuery(superUser *types.SuperUserType, query string) bool {
 func superUserContainsIgnoreCase(s, substr string) bool {
 	return len(s) >= len(substr) && (s[:len(substr)] == substr || s[len(s)-len(substr):] == substr)
 }
+
+// Helper function to order super users by a sort key field like the databases do, ties are
+// broken by ID so a page always holds the same super users
+func sortSuperUsers(superUsers []*types.SuperUserType, field string) {
+	sort.Slice(superUsers, func(i, j int) bool {
+		a, b := superUsers[i], superUsers[j]
+		switch field {
+		case "created_at":
+			if !a.CreatedAt.Equal(b.CreatedAt) {
+				return a.CreatedAt.Before(b.CreatedAt)
+			}
+		case "updated_at":
+			if !a.UpdatedAt.Equal(b.UpdatedAt) {
+				return a.UpdatedAt.Before(b.UpdatedAt)
+			}
+		case "username":
+			if a.Username != b.Username {
+				return a.Username < b.Username
+			}
+		case "email":
+			if a.Email != b.Email {
+				return a.Email < b.Email
+			}
+		case "full_name":
+			if a.FullName != b.FullName {
+				return a.FullName < b.FullName
+			}
+		}
+		return a.ID.String() < b.ID.String()
+	})
+}
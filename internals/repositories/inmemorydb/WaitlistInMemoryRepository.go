@@ -0,0 +1,121 @@
+package inmemorydb
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lordofthemind/EventifyGo/internals/repositories"
+	"github.com/lordofthemind/EventifyGo/internals/types"
+)
+
+type inMemoryWaitlistRepository struct {
+	mu        sync.RWMutex
+	waitlists map[uuid.UUID][]*types.WaitlistEntryType
+}
+
+// NewInMemoryWaitlistRepository creates a new instance of inMemoryWaitlistRepository.
+func NewInMemoryWaitlistRepository() repositories.WaitlistRepositoryInterface {
+	return &inMemoryWaitlistRepository{
+		waitlists: make(map[uuid.UUID][]*types.WaitlistEntryType),
+	}
+}
+
+func (r *inMemoryWaitlistRepository) AddToWaitlist(ctx context.Context, entry *types.WaitlistEntryType) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if waitlistIndex(r.waitlists[entry.EventID], entry.UserID) >= 0 {
+		return repositories.ErrAlreadyWaitlisted
+	}
+
+	if entry.ID == uuid.Nil {
+		entry.ID = uuid.New()
+	}
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+	stored := *entry
+	r.waitlists[entry.EventID] = append(r.waitlists[entry.EventID], &stored)
+	return nil
+}
+
+func (r *inMemoryWaitlistRepository) RemoveFromWaitlist(ctx context.Context, eventID, userID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	entries := r.waitlists[eventID]
+	index := waitlistIndex(entries, userID)
+	if index < 0 {
+		return repositories.ErrNotWaitlisted
+	}
+
+	remaining := append(append([]*types.WaitlistEntryType{}, entries[:index]...), entries[index+1:]...)
+	if len(remaining) == 0 {
+		delete(r.waitlists, eventID)
+	} else {
+		r.waitlists[eventID] = remaining
+	}
+	return nil
+}
+
+func (r *inMemoryWaitlistRepository) GetWaitlistEntry(ctx context.Context, eventID, userID uuid.UUID) (*types.WaitlistEntryType, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entries := r.waitlists[eventID]
+	index := waitlistIndex(entries, userID)
+	if index < 0 {
+		return nil, repositories.ErrNotWaitlisted
+	}
+
+	entry := *entries[index]
+	entry.Position = index + 1
+	return &entry, nil
+}
+
+func (r *inMemoryWaitlistRepository) FirstInWaitlist(ctx context.Context, eventID uuid.UUID) (*types.WaitlistEntryType, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entries := r.waitlists[eventID]
+	if len(entries) == 0 {
+		return nil, repositories.ErrWaitlistEmpty
+	}
+
+	entry := *entries[0]
+	entry.Position = 1
+	return &entry, nil
+}
+
+func (r *inMemoryWaitlistRepository) ListWaitlist(ctx context.Context, eventID uuid.UUID) ([]*types.WaitlistEntryType, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make([]*types.WaitlistEntryType, 0, len(r.waitlists[eventID]))
+	for i, stored := range r.waitlists[eventID] {
+		entry := *stored
+		entry.Position = i + 1
+		result = append(result, &entry)
+	}
+	return result, nil
+}
+
+func (r *inMemoryWaitlistRepository) ClearWaitlist(ctx context.Context, eventID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.waitlists, eventID)
+	return nil
+}
+
+// Helper function to find a user's index in a waitlist
+func waitlistIndex(entries []*types.WaitlistEntryType, userID uuid.UUID) int {
+	for i, entry := range entries {
+		if entry.UserID == userID {
+			return i
+		}
+	}
+	return -1
+}
@@ -2,6 +2,7 @@ package mongodb
 
 import (
 	"context"
+	"regexp"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -26,17 +27,19 @@ func NewMongoEventRepository(db *mongo.Database) repositories.EventRepositoryInt
 
 func (r *mongoEventRepository) CreateEvent(ctx context.Context, event *types.EventType) error {
 	_, err := r.collection.InsertOne(ctx, event)
-	return err
+	return writeError(err, repositories.ErrDuplicateEvent)
 }
 
 func (r *mongoEventRepository) GetEventByID(ctx context.Context, eventID uuid.UUID) (*types.EventType, error) {
 	var event types.EventType
 	filter := bson.M{"_id": eventID}
-	err := r.collection.FindOne(ctx, filter).Decode(&event)
-	if err == mongo.ErrNoDocuments {
-		return nil, nil
+	if err := r.collection.FindOne(ctx, filter).Decode(&event); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, repositories.ErrEventNotFound
+		}
+		return nil, err
 	}
-	return &event, err
+	return &event, nil
 }
 
 func (r *mongoEventRepository) UpdateEvent(ctx context.Context, event *types.EventType) error {
@@ -50,35 +53,53 @@ func (r *mongoEventRepository) UpdateEvent(ctx context.Context, event *types.Eve
 			"capacity":     event.Capacity,
 			"updated_at":   time.Now(),
 			"organizer_id": event.OrganizerID,
-			"attendees":    event.Attendees,
+			"status":       event.Status,
+			"end_date":     event.EndDate,
+			"time_zone":    event.TimeZone,
+			"all_day":      event.AllDay,
+
+			"recurrence_rule":    event.RecurrenceRule,
+			"recurrence_exdates": event.RecurrenceExDates,
+			"series_id":          event.SeriesID,
+			"recurrence_id":      event.RecurrenceID,
 		},
 	}
-	_, err := r.collection.UpdateOne(ctx, filter, update)
-	return err
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return writeError(err, repositories.ErrDuplicateEvent)
+	}
+	if result.MatchedCount == 0 {
+		return repositories.ErrEventNotFound
+	}
+	return nil
 }
 
 func (r *mongoEventRepository) DeleteEvent(ctx context.Context, eventID uuid.UUID) error {
 	filter := bson.M{"_id": eventID}
-	_, err := r.collection.DeleteOne(ctx, filter)
-	return err
+	result, err := r.collection.DeleteOne(ctx, filter)
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return repositories.ErrEventNotFound
+	}
+	return nil
 }
 
-func (r *mongoEventRepository) SearchEvents(ctx context.Context, searchQuery string, page, limit int, sortBy string) ([]*types.EventType, error) {
+func (r *mongoEventRepository) SearchEvents(ctx context.Context, viewerID uuid.UUID, searchQuery string, page, limit int, sortBy string) ([]*types.EventType, error) {
+	key, err := repositories.EventSortKey(sortBy)
+	if err != nil {
+		return nil, err
+	}
 	var events []*types.EventType
 	skip := (page - 1) * limit
 
-	filter := bson.M{
-		"$or": []bson.M{
-			{"name": bson.M{"$regex": searchQuery, "$options": "i"}},
-			{"description": bson.M{"$regex": searchQuery, "$options": "i"}},
-			{"location": bson.M{"$regex": searchQuery, "$options": "i"}},
-		},
-	}
+	filter := bson.M{"$and": []bson.M{visibilityFilter(viewerID), searchFilter(searchQuery)}}
 
 	opts := options.Find().
 		SetSkip(int64(skip)).
 		SetLimit(int64(limit)).
-		SetSort(bson.D{{Key: sortBy, Value: 1}})
+		SetSort(bson.D{{Key: key, Value: 1}})
 
 	cursor, err := r.collection.Find(ctx, filter, opts)
 	if err != nil {
@@ -92,16 +113,115 @@ func (r *mongoEventRepository) SearchEvents(ctx context.Context, searchQuery str
 	return events, nil
 }
 
-func (r *mongoEventRepository) ListEvents(ctx context.Context, page, limit int, sortBy string) ([]*types.EventType, error) {
+func (r *mongoEventRepository) ListEvents(ctx context.Context, viewerID uuid.UUID, page, limit int, sortBy string) ([]*types.EventType, error) {
+	key, err := repositories.EventSortKey(sortBy)
+	if err != nil {
+		return nil, err
+	}
 	var events []*types.EventType
 	skip := (page - 1) * limit
 
 	opts := options.Find().
 		SetSkip(int64(skip)).
 		SetLimit(int64(limit)).
-		SetSort(bson.D{{Key: sortBy, Value: 1}})
+		SetSort(bson.D{{Key: key, Value: 1}})
+
+	cursor, err := r.collection.Find(ctx, visibilityFilter(viewerID), opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cursor.All(ctx, &events); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func (r *mongoEventRepository) CountEvents(ctx context.Context, viewerID uuid.UUID, searchQuery string) (int64, error) {
+	filter := bson.M{"$and": []bson.M{visibilityFilter(viewerID), searchFilter(searchQuery)}}
+
+	count, err := r.collection.CountDocuments(ctx, filter)
+	return count, err
+}
+
+func (r *mongoEventRepository) FindEventsInRange(ctx context.Context, viewerID uuid.UUID, searchQuery string, from, to time.Time) ([]*types.EventType, error) {
+	events := []*types.EventType{}
+	// An event overlaps the window when it starts inside it or starts before and ends after from
+	overlapping := bson.M{"date": bson.M{"$lt": to}, "$or": []bson.M{
+		{"date": bson.M{"$gte": from}},
+		{"end_date": bson.M{"$gt": from}},
+	}}
+	notRecurring := bson.M{"$in": bson.A{nil, ""}}
+
+	filter := bson.M{
+		"$and": []bson.M{
+			visibilityFilter(viewerID),
+			searchFilter(searchQuery),
+			{"$or": []bson.M{
+				// Series masters starting before the end of the window
+				{"recurrence_rule": bson.M{"$nin": bson.A{nil, ""}}, "date": bson.M{"$lt": to}},
+				// One-off events overlapping the window
+				{"recurrence_rule": notRecurring, "series_id": nil, "$and": []bson.M{overlapping}},
+				// Overrides that moved into the window or replace an occurrence inside it
+				{"series_id": bson.M{"$ne": nil}, "$or": []bson.M{
+					overlapping,
+					{"recurrence_id": bson.M{"$gte": from, "$lt": to}},
+				}},
+			}},
+		},
+	}
+
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cursor.All(ctx, &events); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func (r *mongoEventRepository) ListSeriesOverrides(ctx context.Context, seriesID uuid.UUID) ([]*types.EventType, error) {
+	events := []*types.EventType{}
+
+	cursor, err := r.collection.Find(ctx, bson.M{"series_id": seriesID})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cursor.All(ctx, &events); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func (r *mongoEventRepository) FindEventByExternalID(ctx context.Context, organizerID uuid.UUID, externalID string) (*types.EventType, error) {
+	var event types.EventType
+	filter := bson.M{"organizer_id": organizerID, "external_id": externalID}
+	err := r.collection.FindOne(ctx, filter).Decode(&event)
+	if err == mongo.ErrNoDocuments {
+		return nil, repositories.ErrEventNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+func (r *mongoEventRepository) ListEventsByParticipant(ctx context.Context, userID uuid.UUID) ([]*types.EventType, error) {
+	events := []*types.EventType{}
+	filter := bson.M{
+		"$or": []bson.M{
+			{"organizer_id": userID},
+			{"attendees": userID},
+		},
+	}
 
-	cursor, err := r.collection.Find(ctx, bson.M{}, opts)
+	cursor, err := r.collection.Find(ctx, filter)
 	if err != nil {
 		return nil, err
 	}
@@ -113,15 +233,90 @@ func (r *mongoEventRepository) ListEvents(ctx context.Context, page, limit int,
 	return events, nil
 }
 
-func (r *mongoEventRepository) CountEvents(ctx context.Context, searchQuery string) (int64, error) {
+func (r *mongoEventRepository) RegisterAttendee(ctx context.Context, eventID, attendeeID uuid.UUID) error {
+	// The filter only matches while the attendee is missing and a seat is free,
+	// so concurrent registrations can never push the array past capacity
 	filter := bson.M{
+		"_id":       eventID,
+		"attendees": bson.M{"$ne": attendeeID},
+		"$expr": bson.M{
+			"$lt": bson.A{
+				bson.M{"$size": bson.M{"$ifNull": bson.A{"$attendees", bson.A{}}}},
+				"$capacity",
+			},
+		},
+	}
+	update := bson.M{
+		"$addToSet": bson.M{"attendees": attendeeID},
+		"$set":      bson.M{"updated_at": time.Now()},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 1 {
+		return nil
+	}
+
+	// Nothing matched, find out why
+	event, err := r.GetEventByID(ctx, eventID)
+	if err != nil {
+		return err
+	}
+	for _, id := range event.Attendees {
+		if id == attendeeID {
+			return repositories.ErrAlreadyRegistered
+		}
+	}
+	return repositories.ErrEventFull
+}
+
+func (r *mongoEventRepository) UnregisterAttendee(ctx context.Context, eventID, attendeeID uuid.UUID) error {
+	filter := bson.M{"_id": eventID, "attendees": attendeeID}
+	update := bson.M{
+		"$pull": bson.M{"attendees": attendeeID},
+		"$set":  bson.M{"updated_at": time.Now()},
+	}
+
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 1 {
+		return nil
+	}
+
+	count, err := r.collection.CountDocuments(ctx, bson.M{"_id": eventID})
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return repositories.ErrEventNotFound
+	}
+	return repositories.ErrNotRegistered
+}
+
+// searchFilter matches the search query as a substring of name, description and location, like
+// the ILIKE of the Postgres repository. The query is escaped so it is never read as a pattern.
+func searchFilter(searchQuery string) bson.M {
+	pattern := regexp.QuoteMeta(searchQuery)
+	return bson.M{
 		"$or": []bson.M{
-			{"name": bson.M{"$regex": searchQuery, "$options": "i"}},
-			{"description": bson.M{"$regex": searchQuery, "$options": "i"}},
-			{"location": bson.M{"$regex": searchQuery, "$options": "i"}},
+			{"name": bson.M{"$regex": pattern, "$options": "i"}},
+			{"description": bson.M{"$regex": pattern, "$options": "i"}},
+			{"location": bson.M{"$regex": pattern, "$options": "i"}},
 		},
 	}
+}
 
-	count, err := r.collection.CountDocuments(ctx, filter)
-	return count, err
+// Helper function to limit a query to published events, events without a status included,
+// and the events organized by the viewer
+func visibilityFilter(viewerID uuid.UUID) bson.M {
+	return bson.M{
+		"$or": []bson.M{
+			{"status": bson.M{"$in": bson.A{types.EventStatusPublished, "", nil}}},
+			{"organizer_id": viewerID},
+		},
+	}
 }
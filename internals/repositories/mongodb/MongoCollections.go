@@ -0,0 +1,207 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/lordofthemind/EventifyGo/internals/apperrors"
+	"github.com/lordofthemind/EventifyGo/internals/rbac"
+	"github.com/lordofthemind/EventifyGo/internals/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Server error codes handled while bootstrapping and writing
+const (
+	indexNotFound             = 27
+	namespaceExists           = 48
+	documentValidationFailure = 121
+)
+
+// collectionSpec declares the indexes of a collection and the schema its documents must match,
+// dropped names indexes earlier versions created that are no longer used
+type collectionSpec struct {
+	name      string
+	indexes   []mongo.IndexModel
+	dropped   []string
+	validator bson.M
+}
+
+// collectionSpecs mirror the indexes the Postgres schema declares
+var collectionSpecs = []collectionSpec{
+	{
+		name: "superusers",
+		indexes: []mongo.IndexModel{
+			{Keys: bson.D{{Key: "email", Value: 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{Key: "username", Value: 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{Key: "reset_token_hash", Value: 1}}},
+		},
+		dropped: []string{"full_name_text_username_text_email_text"},
+		validator: jsonSchema([]string{"email", "username", "hashed_password", "role"}, bson.M{
+			"email":                stringSchema(1),
+			"username":             stringSchema(1),
+			"full_name":            bson.M{"bsonType": "string"},
+			"hashed_password":      stringSchema(1),
+			"role":                 bson.M{"enum": bson.A{rbac.RoleAdmin, rbac.RoleManager, rbac.RoleViewer, rbac.RoleGuest}},
+			"is_2fa_enabled":       bson.M{"bsonType": "bool"},
+			"last_totp_step":       bson.M{"bsonType": bson.A{"int", "long"}},
+			"permission_groups":    stringArraySchema(),
+			"recovery_code_hashes": stringArraySchema(),
+			"created_at":           bson.M{"bsonType": "date"},
+			"updated_at":           bson.M{"bsonType": "date"},
+		}),
+	},
+	{
+		name: "events",
+		indexes: []mongo.IndexModel{
+			{Keys: bson.D{{Key: "date", Value: 1}}},
+			{Keys: bson.D{{Key: "organizer_id", Value: 1}, {Key: "date", Value: 1}}},
+			{Keys: bson.D{{Key: "organizer_id", Value: 1}, {Key: "external_id", Value: 1}}},
+			{Keys: bson.D{{Key: "attendees", Value: 1}}},
+			{Keys: bson.D{{Key: "series_id", Value: 1}}, Options: options.Index().SetSparse(true)},
+			{Keys: bson.D{{Key: "status", Value: 1}}},
+		},
+		dropped: []string{"name_text_description_text_location_text"},
+		validator: jsonSchema([]string{"name", "date", "capacity", "organizer_id"}, bson.M{
+			"name":         stringSchema(1),
+			"description":  bson.M{"bsonType": "string"},
+			"date":         bson.M{"bsonType": "date"},
+			"end_date":     bson.M{"bsonType": bson.A{"date", "null"}},
+			"location":     bson.M{"bsonType": "string"},
+			"capacity":     bson.M{"bsonType": bson.A{"int", "long"}, "minimum": 1},
+			"organizer_id": bson.M{"bsonType": "binData"},
+			"attendees":    bson.M{"bsonType": bson.A{"array", "null"}, "items": bson.M{"bsonType": "binData"}},
+			// Events stored before statuses existed have none and count as published
+			"status": bson.M{"enum": bson.A{
+				"", types.EventStatusDraft, types.EventStatusPublished, types.EventStatusCancelled, types.EventStatusCompleted,
+			}},
+			"all_day": bson.M{"bsonType": "bool"},
+		}),
+	},
+	{
+		name: "waitlist",
+		indexes: []mongo.IndexModel{
+			{Keys: bson.D{{Key: "event_id", Value: 1}, {Key: "user_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{Key: "event_id", Value: 1}, {Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}},
+		},
+	},
+	{
+		name: "refresh_tokens",
+		indexes: []mongo.IndexModel{
+			{Keys: bson.D{{Key: "token_hash", Value: 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{Key: "family_id", Value: 1}}},
+			{Keys: bson.D{{Key: "superuser_id", Value: 1}}},
+		},
+	},
+	{
+		name: "sessions",
+		indexes: []mongo.IndexModel{
+			{Keys: bson.D{{Key: "superuser_id", Value: 1}}},
+		},
+	},
+}
+
+// EnsureCollections creates the indexes of every collection and, when validate is set, installs
+// their $jsonSchema validators. It is safe to run on every start. Documents stored before a
+// validator existed are not checked again until they are rewritten whole.
+func EnsureCollections(ctx context.Context, db *mongo.Database, validate bool) error {
+	existing, err := db.ListCollectionNames(ctx, bson.M{})
+	if err != nil {
+		return fmt.Errorf("failed to list collections: %w", err)
+	}
+	exists := map[string]bool{}
+	for _, name := range existing {
+		exists[name] = true
+	}
+
+	for _, spec := range collectionSpecs {
+		if validate && spec.validator != nil {
+			if err := applyValidator(ctx, db, spec, exists[spec.name]); err != nil {
+				return fmt.Errorf("failed to set the schema of %s: %w", spec.name, err)
+			}
+		}
+		if _, err := db.Collection(spec.name).Indexes().CreateMany(ctx, spec.indexes); err != nil {
+			// A unique index cannot be built over duplicates already stored
+			return fmt.Errorf("failed to create the indexes of %s: %w", spec.name, err)
+		}
+		for _, index := range spec.dropped {
+			if err := dropIndex(ctx, db.Collection(spec.name), index); err != nil {
+				return fmt.Errorf("failed to drop the index %s of %s: %w", index, spec.name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Helper function to drop an index, an index that does not exist is already dropped
+func dropIndex(ctx context.Context, collection *mongo.Collection, name string) error {
+	_, err := collection.Indexes().DropOne(ctx, name)
+	var serverErr mongo.ServerError
+	if errors.As(err, &serverErr) && serverErr.HasErrorCode(indexNotFound) {
+		return nil
+	}
+	return err
+}
+
+// Helper function to create a collection with its validator, or replace the validator of an
+// existing one. Moderate validation leaves updates of already invalid documents alone.
+func applyValidator(ctx context.Context, db *mongo.Database, spec collectionSpec, exists bool) error {
+	if !exists {
+		opts := options.CreateCollection().
+			SetValidator(spec.validator).
+			SetValidationLevel("moderate").
+			SetValidationAction("error")
+		err := db.CreateCollection(ctx, spec.name, opts)
+		var serverErr mongo.ServerError
+		if !errors.As(err, &serverErr) || !serverErr.HasErrorCode(namespaceExists) {
+			return err
+		}
+		// Another instance created it first, its validator is replaced below
+	}
+
+	command := bson.D{
+		{Key: "collMod", Value: spec.name},
+		{Key: "validator", Value: spec.validator},
+		{Key: "validationLevel", Value: "moderate"},
+		{Key: "validationAction", Value: "error"},
+	}
+	return db.RunCommand(ctx, command).Err()
+}
+
+// Helper function to wrap the properties of a document into a $jsonSchema validator, fields
+// not listed are allowed
+func jsonSchema(required []string, properties bson.M) bson.M {
+	return bson.M{"$jsonSchema": bson.M{
+		"bsonType":   "object",
+		"required":   required,
+		"properties": properties,
+	}}
+}
+
+// Helper function to declare a string of a minimum length
+func stringSchema(minLength int) bson.M {
+	return bson.M{"bsonType": "string", "minLength": minLength}
+}
+
+// Helper function to declare a list of strings, nil slices are stored as null
+func stringArraySchema() bson.M {
+	return bson.M{"bsonType": bson.A{"array", "null"}, "items": bson.M{"bsonType": "string"}}
+}
+
+// Helper function to translate a write error. Duplicate keys become duplicate and documents
+// the collection validator rejects become validation errors.
+func writeError(err error, duplicate error) error {
+	if err == nil {
+		return nil
+	}
+	if mongo.IsDuplicateKeyError(err) {
+		return duplicate
+	}
+	var serverErr mongo.ServerError
+	if errors.As(err, &serverErr) && serverErr.HasErrorCode(documentValidationFailure) {
+		return fmt.Errorf("%w: document does not match the collection schema: %w", apperrors.ErrValidation, err)
+	}
+	return err
+}
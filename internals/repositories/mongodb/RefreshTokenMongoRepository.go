@@ -0,0 +1,71 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lordofthemind/EventifyGo/internals/repositories"
+	"github.com/lordofthemind/EventifyGo/internals/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type mongoRefreshTokenRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoRefreshTokenRepository creates a new instance of mongoRefreshTokenRepository.
+func NewMongoRefreshTokenRepository(db *mongo.Database) repositories.RefreshTokenRepositoryInterface {
+	return &mongoRefreshTokenRepository{
+		collection: db.Collection("refresh_tokens"),
+	}
+}
+
+func (r *mongoRefreshTokenRepository) Create(ctx context.Context, token *types.RefreshTokenType) error {
+	if token.ID == uuid.Nil {
+		token.ID = uuid.New()
+	}
+	if token.CreatedAt.IsZero() {
+		token.CreatedAt = time.Now()
+	}
+	_, err := r.collection.InsertOne(ctx, token)
+	return err
+}
+
+func (r *mongoRefreshTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*types.RefreshTokenType, error) {
+	var token types.RefreshTokenType
+	err := r.collection.FindOne(ctx, bson.M{"token_hash": tokenHash}).Decode(&token)
+	if err == mongo.ErrNoDocuments {
+		return nil, repositories.ErrRefreshTokenNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// MarkRotated only matches a live token, so concurrent refreshes cannot both rotate it
+func (r *mongoRefreshTokenRepository) MarkRotated(ctx context.Context, id uuid.UUID, at time.Time) error {
+	filter := bson.M{"_id": id, "rotated_at": nil, "revoked_at": nil}
+	result, err := r.collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"rotated_at": at}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return repositories.ErrRefreshTokenUsed
+	}
+	return nil
+}
+
+func (r *mongoRefreshTokenRepository) RevokeFamily(ctx context.Context, familyID uuid.UUID, at time.Time) error {
+	filter := bson.M{"family_id": familyID, "revoked_at": nil}
+	_, err := r.collection.UpdateMany(ctx, filter, bson.M{"$set": bson.M{"revoked_at": at}})
+	return err
+}
+
+func (r *mongoRefreshTokenRepository) RevokeAll(ctx context.Context, superUserID uuid.UUID, at time.Time) error {
+	filter := bson.M{"superuser_id": superUserID, "revoked_at": nil}
+	_, err := r.collection.UpdateMany(ctx, filter, bson.M{"$set": bson.M{"revoked_at": at}})
+	return err
+}
@@ -0,0 +1,97 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lordofthemind/EventifyGo/internals/repositories"
+	"github.com/lordofthemind/EventifyGo/internals/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoSessionRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoSessionRepository creates a new instance of mongoSessionRepository.
+func NewMongoSessionRepository(db *mongo.Database) repositories.SessionRepositoryInterface {
+	return &mongoSessionRepository{
+		collection: db.Collection("sessions"),
+	}
+}
+
+func (r *mongoSessionRepository) Create(ctx context.Context, session *types.SessionType) error {
+	if session.ID == uuid.Nil {
+		session.ID = uuid.New()
+	}
+	if session.CreatedAt.IsZero() {
+		session.CreatedAt = time.Now()
+	}
+	_, err := r.collection.InsertOne(ctx, session)
+	return err
+}
+
+func (r *mongoSessionRepository) FindByID(ctx context.Context, id uuid.UUID) (*types.SessionType, error) {
+	var session types.SessionType
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&session)
+	if err == mongo.ErrNoDocuments {
+		return nil, repositories.ErrSessionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *mongoSessionRepository) ListActive(ctx context.Context, superUserID uuid.UUID, now time.Time) ([]*types.SessionType, error) {
+	filter := bson.M{"superuser_id": superUserID, "revoked_at": nil, "expires_at": bson.M{"$gt": now}}
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "last_seen_at", Value: -1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	sessions := []*types.SessionType{}
+	if err := cursor.All(ctx, &sessions); err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+func (r *mongoSessionRepository) Touch(ctx context.Context, id uuid.UUID, at time.Time, ipAddress string) error {
+	return r.updateByID(ctx, id, bson.M{"$set": bson.M{"last_seen_at": at, "ip_address": ipAddress}})
+}
+
+func (r *mongoSessionRepository) Extend(ctx context.Context, id uuid.UUID, expiresAt time.Time) error {
+	return r.updateByID(ctx, id, bson.M{"$set": bson.M{"expires_at": expiresAt}})
+}
+
+func (r *mongoSessionRepository) Revoke(ctx context.Context, id uuid.UUID, at time.Time) error {
+	if _, err := r.collection.UpdateOne(ctx, bson.M{"_id": id, "revoked_at": nil}, bson.M{"$set": bson.M{"revoked_at": at}}); err != nil {
+		return err
+	}
+	// Nothing matched either because the session is unknown or because it was already revoked
+	_, err := r.FindByID(ctx, id)
+	return err
+}
+
+func (r *mongoSessionRepository) RevokeAll(ctx context.Context, superUserID uuid.UUID, at time.Time) error {
+	filter := bson.M{"superuser_id": superUserID, "revoked_at": nil}
+	_, err := r.collection.UpdateMany(ctx, filter, bson.M{"$set": bson.M{"revoked_at": at}})
+	return err
+}
+
+// Helper function to update a session, reporting unknown ones
+func (r *mongoSessionRepository) updateByID(ctx context.Context, id uuid.UUID, update bson.M) error {
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return repositories.ErrSessionNotFound
+	}
+	return nil
+}
@@ -2,7 +2,7 @@ package mongodb
 
 import (
 	"context"
-	"errors"
+	"regexp"
 	"time"
 
 	"github.com/google/uuid"
@@ -30,75 +30,61 @@ func (r *mongoSuperUserRepository) Create(ctx context.Context, superUser *types.
 	superUser.UpdatedAt = time.Now()
 
 	_, err := r.collection.InsertOne(ctx, superUser)
-	return err
+	return writeError(err, repositories.ErrDuplicateSuperUser)
 }
 
 // FindByID finds a super user by UUID
 func (r *mongoSuperUserRepository) FindByID(ctx context.Context, id uuid.UUID) (*types.SuperUserType, error) {
-	var superUser types.SuperUserType
 	filter := bson.M{"_id": id}
-	err := r.collection.FindOne(ctx, filter).Decode(&superUser)
-	if err == mongo.ErrNoDocuments {
-		return nil, errors.New("superuser not found")
-	}
-	return &superUser, err
+	return r.findOne(ctx, filter)
 }
 
 // FindByEmail finds a super user by email
 func (r *mongoSuperUserRepository) FindByEmail(ctx context.Context, email string) (*types.SuperUserType, error) {
-	var superUser types.SuperUserType
 	filter := bson.M{"email": email}
-	err := r.collection.FindOne(ctx, filter).Decode(&superUser)
-	if err == mongo.ErrNoDocuments {
-		return nil, errors.New("superuser not found")
-	}
-	return &superUser, err
+	return r.findOne(ctx, filter)
 }
 
 // FindByUsername finds a super user by username
 func (r *mongoSuperUserRepository) FindByUsername(ctx context.Context, username string) (*types.SuperUserType, error) {
-	var superUser types.SuperUserType
 	filter := bson.M{"username": username}
-	err := r.collection.FindOne(ctx, filter).Decode(&superUser)
-	if err == mongo.ErrNoDocuments {
-		return nil, errors.New("superuser not found")
-	}
-	return &superUser, err
-}
-
-// FindByResetToken finds a super user by reset token
-func (r *mongoSuperUserRepository) FindByResetToken(ctx context.Context, token string) (*types.SuperUserType, error) {
-	var superUser types.SuperUserType
-	filter := bson.M{"reset_token": token}
-	err := r.collection.FindOne(ctx, filter).Decode(&superUser)
-	if err == mongo.ErrNoDocuments {
-		return nil, errors.New("superuser not found")
-	}
-	return &superUser, err
+	return r.findOne(ctx, filter)
 }
 
 // DeleteByID deletes a super user by UUID
 func (r *mongoSuperUserRepository) DeleteByID(ctx context.Context, id uuid.UUID) error {
 	filter := bson.M{"_id": id}
-	_, err := r.collection.DeleteOne(ctx, filter)
-	return err
+	result, err := r.collection.DeleteOne(ctx, filter)
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return repositories.ErrSuperUserNotFound
+	}
+	return nil
 }
 
 // SearchSuperusers searches for super users based on a query string, with pagination and sorting
 func (r *mongoSuperUserRepository) SearchSuperusers(ctx context.Context, searchQuery string, page, limit int, sortBy string) ([]*types.SuperUserType, error) {
+	key, err := repositories.SuperUserSortKey(sortBy)
+	if err != nil {
+		return nil, err
+	}
 	var superUsers []*types.SuperUserType
+	// The query is escaped so it matches as a plain substring
+	pattern := regexp.QuoteMeta(searchQuery)
 	filter := bson.M{
 		"$or": []bson.M{
-			{"full_name": bson.M{"$regex": searchQuery, "$options": "i"}},
-			{"username": bson.M{"$regex": searchQuery, "$options": "i"}},
-			{"email": bson.M{"$regex": searchQuery, "$options": "i"}},
+			{"full_name": bson.M{"$regex": pattern, "$options": "i"}},
+			{"username": bson.M{"$regex": pattern, "$options": "i"}},
+			{"email": bson.M{"$regex": pattern, "$options": "i"}},
 		},
 	}
 
 	findOptions := options.Find().
 		SetSkip(int64((page - 1) * limit)).
 		SetLimit(int64(limit)).
-		SetSort(bson.M{sortBy: 1}) // 1 for ascending, -1 for descending
+		SetSort(bson.D{{Key: key, Value: 1}}) // 1 for ascending, -1 for descending
 
 	cursor, err := r.collection.Find(ctx, filter, findOptions)
 	if err != nil {
@@ -125,18 +111,21 @@ func (r *mongoSuperUserRepository) SearchSuperusers(ctx context.Context, searchQ
 func (r *mongoSuperUserRepository) Update(ctx context.Context, superUser *types.SuperUserType) error {
 	superUser.UpdatedAt = time.Now()
 
-	filter := bson.M{"_id": superUser.ID}
-	update := bson.M{"$set": superUser}
-	_, err := r.collection.UpdateOne(ctx, filter, update)
-	return err
+	return r.updateByID(ctx, superUser.ID, bson.M{"$set": superUser})
 }
 
 // UpdateField allows updating a single field of a super user document
 func (r *mongoSuperUserRepository) UpdateField(ctx context.Context, id uuid.UUID, field string, value interface{}) error {
-	filter := bson.M{"_id": id}
-	update := bson.M{"$set": bson.M{field: value}}
-	_, err := r.collection.UpdateOne(ctx, filter, update)
-	return err
+	return r.updateByID(ctx, id, bson.M{"$set": bson.M{field: value}})
+}
+
+// UpdateFields updates several fields of a super user document at once
+func (r *mongoSuperUserRepository) UpdateFields(ctx context.Context, id uuid.UUID, fields map[string]interface{}) error {
+	set := bson.M{"updated_at": time.Now()}
+	for field, value := range fields {
+		set[field] = value
+	}
+	return r.updateByID(ctx, id, bson.M{"$set": set})
 }
 
 // GetRoleByID retrieves the role of a super user by their UUID
@@ -145,11 +134,13 @@ func (r *mongoSuperUserRepository) GetRoleByID(ctx context.Context, id uuid.UUID
 		Role string `bson:"role"`
 	}
 	filter := bson.M{"_id": id}
-	err := r.collection.FindOne(ctx, filter).Decode(&result)
-	if err == mongo.ErrNoDocuments {
-		return "", errors.New("superuser not found")
+	if err := r.collection.FindOne(ctx, filter).Decode(&result); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return "", repositories.ErrSuperUserNotFound
+		}
+		return "", err
 	}
-	return result.Role, err
+	return result.Role, nil
 }
 
 // FindAll2FAEnabledSuperusers retrieves all super users with 2FA enabled
@@ -186,30 +177,107 @@ func (r *mongoSuperUserRepository) GetAllSuperUsers(ctx context.Context) ([]*typ
 	}
 	defer cursor.Close(ctx)
 
-	var allSuperUsers []*types.SuperUserType
+	allSuperUsers := []*types.SuperUserType{}
 	if err = cursor.All(ctx, &allSuperUsers); err != nil {
 		return nil, err
 	}
 
-	if len(allSuperUsers) == 0 {
-		return nil, errors.New("no super users found")
-	}
-
 	return allSuperUsers, nil
 }
 
-// UpdateResetToken updates the reset token for a super user
-func (r *mongoSuperUserRepository) UpdateResetToken(ctx context.Context, id uuid.UUID, token string) error {
-	filter := bson.M{"_id": id}
-	update := bson.M{"$set": bson.M{"reset_token": token, "updated_at": time.Now()}}
-	_, err := r.collection.UpdateOne(ctx, filter, update)
-	return err
-}
-
 // UpdateSuperuserRole updates the role of a super user
 func (r *mongoSuperUserRepository) UpdateSuperuserRole(ctx context.Context, id uuid.UUID, role string) error {
-	filter := bson.M{"_id": id}
 	update := bson.M{"$set": bson.M{"role": role, "updated_at": time.Now()}}
-	_, err := r.collection.UpdateOne(ctx, filter, update)
-	return err
+	return r.updateByID(ctx, id, update)
+}
+
+// SetResetToken stores the hash of a password reset token
+func (r *mongoSuperUserRepository) SetResetToken(ctx context.Context, id uuid.UUID, tokenHash string, expiresAt time.Time) error {
+	update := bson.M{"$set": bson.M{"reset_token_hash": tokenHash, "reset_token_expires": expiresAt, "updated_at": time.Now()}}
+	return r.updateByID(ctx, id, update)
+}
+
+// ResetPassword sets a new password for the holder of a valid reset token, the filter makes the token single use
+func (r *mongoSuperUserRepository) ResetPassword(ctx context.Context, tokenHash, hashedPassword string) (uuid.UUID, error) {
+	filter := bson.M{"reset_token_hash": tokenHash, "reset_token_expires": bson.M{"$gt": time.Now()}}
+	update := bson.M{"$set": bson.M{
+		"hashed_password":     hashedPassword,
+		"reset_token_hash":    nil,
+		"reset_token_expires": nil,
+		"updated_at":          time.Now(),
+	}}
+	var superUser types.SuperUserType
+	opts := options.FindOneAndUpdate().SetProjection(bson.M{"_id": 1})
+	if err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&superUser); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return uuid.Nil, repositories.ErrResetTokenNotFound
+		}
+		return uuid.Nil, err
+	}
+	return superUser.ID, nil
+}
+
+// AdvanceTOTPStep records the time step of an accepted one-time code, the filter makes the check atomic
+func (r *mongoSuperUserRepository) AdvanceTOTPStep(ctx context.Context, id uuid.UUID, step int64) error {
+	filter := bson.M{"_id": id, "last_totp_step": bson.M{"$not": bson.M{"$gte": step}}}
+	update := bson.M{"$set": bson.M{"last_totp_step": step, "updated_at": time.Now()}}
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 1 {
+		return nil
+	}
+
+	// Nothing matched, find out why
+	if _, err := r.FindByID(ctx, id); err != nil {
+		return err
+	}
+	return repositories.ErrTOTPStepUsed
+}
+
+// ConsumeRecoveryCode removes a used recovery code hash, the filter makes the check atomic
+func (r *mongoSuperUserRepository) ConsumeRecoveryCode(ctx context.Context, id uuid.UUID, codeHash string) error {
+	filter := bson.M{"_id": id, "recovery_code_hashes": codeHash}
+	update := bson.M{
+		"$pull": bson.M{"recovery_code_hashes": codeHash},
+		"$set":  bson.M{"updated_at": time.Now()},
+	}
+	result, err := r.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 1 {
+		return nil
+	}
+
+	// Nothing matched, find out why
+	if _, err := r.FindByID(ctx, id); err != nil {
+		return err
+	}
+	return repositories.ErrRecoveryCodeNotFound
+}
+
+// Helper function to find a single super user, ErrSuperUserNotFound when nothing matches
+func (r *mongoSuperUserRepository) findOne(ctx context.Context, filter bson.M) (*types.SuperUserType, error) {
+	var superUser types.SuperUserType
+	if err := r.collection.FindOne(ctx, filter).Decode(&superUser); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, repositories.ErrSuperUserNotFound
+		}
+		return nil, err
+	}
+	return &superUser, nil
+}
+
+// Helper function to update a single super user, ErrSuperUserNotFound when the id does not exist
+func (r *mongoSuperUserRepository) updateByID(ctx context.Context, id uuid.UUID, update bson.M) error {
+	result, err := r.collection.UpdateOne(ctx, bson.M{"_id": id}, update)
+	if err != nil {
+		return writeError(err, repositories.ErrDuplicateSuperUser)
+	}
+	if result.MatchedCount == 0 {
+		return repositories.ErrSuperUserNotFound
+	}
+	return nil
 }
@@ -0,0 +1,123 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lordofthemind/EventifyGo/internals/repositories"
+	"github.com/lordofthemind/EventifyGo/internals/types"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type mongoWaitlistRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoWaitlistRepository creates a new instance of mongoWaitlistRepository.
+func NewMongoWaitlistRepository(db *mongo.Database) repositories.WaitlistRepositoryInterface {
+	return &mongoWaitlistRepository{
+		collection: db.Collection("waitlist"),
+	}
+}
+
+// waitlistOrder sorts entries by join time, using the id to break ties
+var waitlistOrder = bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}
+
+func (r *mongoWaitlistRepository) AddToWaitlist(ctx context.Context, entry *types.WaitlistEntryType) error {
+	if entry.ID == uuid.Nil {
+		entry.ID = uuid.New()
+	}
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+
+	// Upserting on (event_id, user_id) keeps a user from joining the same waitlist twice
+	filter := bson.M{"event_id": entry.EventID, "user_id": entry.UserID}
+	update := bson.M{"$setOnInsert": bson.M{"_id": entry.ID, "created_at": entry.CreatedAt}}
+	result, err := r.collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if err != nil {
+		// Two concurrent upserts both insert, the unique index rejects the second
+		return writeError(err, repositories.ErrAlreadyWaitlisted)
+	}
+	if result.UpsertedCount == 0 {
+		return repositories.ErrAlreadyWaitlisted
+	}
+	return nil
+}
+
+func (r *mongoWaitlistRepository) RemoveFromWaitlist(ctx context.Context, eventID, userID uuid.UUID) error {
+	result, err := r.collection.DeleteOne(ctx, bson.M{"event_id": eventID, "user_id": userID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return repositories.ErrNotWaitlisted
+	}
+	return nil
+}
+
+func (r *mongoWaitlistRepository) GetWaitlistEntry(ctx context.Context, eventID, userID uuid.UUID) (*types.WaitlistEntryType, error) {
+	var entry types.WaitlistEntryType
+	err := r.collection.FindOne(ctx, bson.M{"event_id": eventID, "user_id": userID}).Decode(&entry)
+	if err == mongo.ErrNoDocuments {
+		return nil, repositories.ErrNotWaitlisted
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Position is the number of entries that joined before this one, plus one
+	ahead, err := r.collection.CountDocuments(ctx, bson.M{
+		"event_id": eventID,
+		"$or": []bson.M{
+			{"created_at": bson.M{"$lt": entry.CreatedAt}},
+			{"created_at": entry.CreatedAt, "_id": bson.M{"$lt": entry.ID}},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	entry.Position = int(ahead) + 1
+	return &entry, nil
+}
+
+func (r *mongoWaitlistRepository) FirstInWaitlist(ctx context.Context, eventID uuid.UUID) (*types.WaitlistEntryType, error) {
+	var entry types.WaitlistEntryType
+	opts := options.FindOne().SetSort(waitlistOrder)
+	err := r.collection.FindOne(ctx, bson.M{"event_id": eventID}, opts).Decode(&entry)
+	if err == mongo.ErrNoDocuments {
+		return nil, repositories.ErrWaitlistEmpty
+	}
+	if err != nil {
+		return nil, err
+	}
+	entry.Position = 1
+	return &entry, nil
+}
+
+func (r *mongoWaitlistRepository) ListWaitlist(ctx context.Context, eventID uuid.UUID) ([]*types.WaitlistEntryType, error) {
+	entries := []*types.WaitlistEntryType{}
+	opts := options.Find().SetSort(waitlistOrder)
+
+	cursor, err := r.collection.Find(ctx, bson.M{"event_id": eventID}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+
+	for i, entry := range entries {
+		entry.Position = i + 1
+	}
+	return entries, nil
+}
+
+func (r *mongoWaitlistRepository) ClearWaitlist(ctx context.Context, eventID uuid.UUID) error {
+	_, err := r.collection.DeleteMany(ctx, bson.M{"event_id": eventID})
+	return err
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/lordofthemind/EventifyGo/internals/repositories"
 	"github.com/lordofthemind/EventifyGo/internals/types"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type postgresEventRepository struct {
@@ -30,7 +31,7 @@ func (r *postgresEventRepository) GetEventByID(ctx context.Context, eventID uuid
 	var event types.EventType
 	if err := r.db.WithContext(ctx).First(&event, "event_id = ?", eventID).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, nil
+			return nil, repositories.ErrEventNotFound
 		}
 		return nil, err
 	}
@@ -39,34 +40,185 @@ func (r *postgresEventRepository) GetEventByID(ctx context.Context, eventID uuid
 
 func (r *postgresEventRepository) UpdateEvent(ctx context.Context, event *types.EventType) error {
 	event.UpdatedAt = time.Now()
-	return r.db.WithContext(ctx).Model(event).Where("event_id = ?", event.EventID).Updates(event).Error
+	// Attendees are only changed through RegisterAttendee and UnregisterAttendee
+	// Select("*") makes cleared fields such as a removed recurrence rule persist too
+	result := r.db.WithContext(ctx).Model(event).Where("event_id = ?", event.EventID).Select("*").Omit("attendees", "created_at").Updates(event)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return repositories.ErrEventNotFound
+	}
+	return nil
 }
 
 func (r *postgresEventRepository) DeleteEvent(ctx context.Context, eventID uuid.UUID) error {
-	return r.db.WithContext(ctx).Where("event_id = ?", eventID).Delete(&types.EventType{}).Error
+	result := r.db.WithContext(ctx).Where("event_id = ?", eventID).Delete(&types.EventType{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return repositories.ErrEventNotFound
+	}
+	return nil
 }
 
-func (r *postgresEventRepository) SearchEvents(ctx context.Context, searchQuery string, page, limit int, sortBy string) ([]*types.EventType, error) {
+func (r *postgresEventRepository) SearchEvents(ctx context.Context, viewerID uuid.UUID, searchQuery string, page, limit int, sortBy string) ([]*types.EventType, error) {
+	column, err := repositories.EventSortKey(sortBy)
+	if err != nil {
+		return nil, err
+	}
 	var events []*types.EventType
 	offset := (page - 1) * limit
 
-	err := r.db.WithContext(ctx).Where("name ILIKE ? OR description ILIKE ? OR location ILIKE ?", "%"+searchQuery+"%", "%"+searchQuery+"%", "%"+searchQuery+"%").
-		Order(sortBy).Offset(offset).Limit(limit).Find(&events).Error
+	err = r.db.WithContext(ctx).Scopes(visibleTo(viewerID)).Where("name ILIKE ? OR description ILIKE ? OR location ILIKE ?", "%"+searchQuery+"%", "%"+searchQuery+"%", "%"+searchQuery+"%").
+		Order(clause.OrderByColumn{Column: clause.Column{Name: column}}).Offset(offset).Limit(limit).Find(&events).Error
 
 	return events, err
 }
 
-func (r *postgresEventRepository) ListEvents(ctx context.Context, page, limit int, sortBy string) ([]*types.EventType, error) {
+func (r *postgresEventRepository) ListEvents(ctx context.Context, viewerID uuid.UUID, page, limit int, sortBy string) ([]*types.EventType, error) {
+	column, err := repositories.EventSortKey(sortBy)
+	if err != nil {
+		return nil, err
+	}
 	var events []*types.EventType
 	offset := (page - 1) * limit
 
-	err := r.db.WithContext(ctx).Order(sortBy).Offset(offset).Limit(limit).Find(&events).Error
+	err = r.db.WithContext(ctx).Scopes(visibleTo(viewerID)).Order(clause.OrderByColumn{Column: clause.Column{Name: column}}).Offset(offset).Limit(limit).Find(&events).Error
 
 	return events, err
 }
 
-func (r *postgresEventRepository) CountEvents(ctx context.Context, searchQuery string) (int64, error) {
+func (r *postgresEventRepository) CountEvents(ctx context.Context, viewerID uuid.UUID, searchQuery string) (int64, error) {
 	var count int64
-	err := r.db.WithContext(ctx).Model(&types.EventType{}).Where("name ILIKE ? OR description ILIKE ? OR location ILIKE ?", "%"+searchQuery+"%", "%"+searchQuery+"%", "%"+searchQuery+"%").Count(&count).Error
+	err := r.db.WithContext(ctx).Model(&types.EventType{}).Scopes(visibleTo(viewerID)).Where("name ILIKE ? OR description ILIKE ? OR location ILIKE ?", "%"+searchQuery+"%", "%"+searchQuery+"%", "%"+searchQuery+"%").Count(&count).Error
 	return count, err
 }
+
+func (r *postgresEventRepository) FindEventsInRange(ctx context.Context, viewerID uuid.UUID, searchQuery string, from, to time.Time) ([]*types.EventType, error) {
+	var events []*types.EventType
+	pattern := "%" + searchQuery + "%"
+
+	err := r.db.WithContext(ctx).Scopes(visibleTo(viewerID)).
+		Where("name ILIKE ? OR description ILIKE ? OR location ILIKE ?", pattern, pattern, pattern).
+		Where(r.db.
+			// Series masters starting before the end of the window
+			Where("COALESCE(recurrence_rule, '') <> '' AND date < ?", to).
+			// One-off events overlapping the window
+			Or("COALESCE(recurrence_rule, '') = '' AND series_id IS NULL AND date < ? AND (date >= ? OR end_date > ?)", to, from, from).
+			// Overrides that moved into the window or replace an occurrence inside it
+			Or("series_id IS NOT NULL AND ((date < ? AND (date >= ? OR end_date > ?)) OR (recurrence_id >= ? AND recurrence_id < ?))", to, from, from, from, to)).
+		Find(&events).Error
+
+	return events, err
+}
+
+func (r *postgresEventRepository) ListSeriesOverrides(ctx context.Context, seriesID uuid.UUID) ([]*types.EventType, error) {
+	var events []*types.EventType
+	err := r.db.WithContext(ctx).Where("series_id = ?", seriesID).Find(&events).Error
+	return events, err
+}
+
+func (r *postgresEventRepository) FindEventByExternalID(ctx context.Context, organizerID uuid.UUID, externalID string) (*types.EventType, error) {
+	var event types.EventType
+	err := r.db.WithContext(ctx).First(&event, "organizer_id = ? AND external_id = ?", organizerID, externalID).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, repositories.ErrEventNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+func (r *postgresEventRepository) ListEventsByParticipant(ctx context.Context, userID uuid.UUID) ([]*types.EventType, error) {
+	var events []*types.EventType
+	err := r.db.WithContext(ctx).Where("organizer_id = ? OR ?::uuid = ANY(attendees)", userID, userID).Find(&events).Error
+	return events, err
+}
+
+func (r *postgresEventRepository) RegisterAttendee(ctx context.Context, eventID, attendeeID uuid.UUID) error {
+	// A single conditional UPDATE keeps the capacity check and the append atomic
+	result := r.db.WithContext(ctx).Model(&types.EventType{}).
+		Where("event_id = ?", eventID).
+		Where("NOT (?::uuid = ANY(COALESCE(attendees, '{}')))", attendeeID).
+		Where("COALESCE(array_length(attendees, 1), 0) < capacity").
+		Updates(map[string]interface{}{
+			"attendees":  gorm.Expr("array_append(COALESCE(attendees, '{}'), ?::uuid)", attendeeID),
+			"updated_at": time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 1 {
+		return nil
+	}
+
+	// Nothing was updated, find out why
+	state, err := r.attendanceState(ctx, eventID, attendeeID)
+	if err != nil {
+		return err
+	}
+	if state == nil {
+		return repositories.ErrEventNotFound
+	}
+	if state.Registered {
+		return repositories.ErrAlreadyRegistered
+	}
+	return repositories.ErrEventFull
+}
+
+func (r *postgresEventRepository) UnregisterAttendee(ctx context.Context, eventID, attendeeID uuid.UUID) error {
+	result := r.db.WithContext(ctx).Model(&types.EventType{}).
+		Where("event_id = ?", eventID).
+		Where("?::uuid = ANY(attendees)", attendeeID).
+		Updates(map[string]interface{}{
+			"attendees":  gorm.Expr("array_remove(attendees, ?::uuid)", attendeeID),
+			"updated_at": time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 1 {
+		return nil
+	}
+
+	state, err := r.attendanceState(ctx, eventID, attendeeID)
+	if err != nil {
+		return err
+	}
+	if state == nil {
+		return repositories.ErrEventNotFound
+	}
+	return repositories.ErrNotRegistered
+}
+
+type attendanceState struct {
+	Registered bool
+}
+
+// attendanceState reports whether the attendee is registered, or nil if the event does not exist
+func (r *postgresEventRepository) attendanceState(ctx context.Context, eventID, attendeeID uuid.UUID) (*attendanceState, error) {
+	var states []attendanceState
+	err := r.db.WithContext(ctx).Model(&types.EventType{}).
+		Select("?::uuid = ANY(COALESCE(attendees, '{}')) AS registered", attendeeID).
+		Where("event_id = ?", eventID).
+		Limit(1).
+		Scan(&states).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(states) == 0 {
+		return nil, nil
+	}
+	return &states[0], nil
+}
+
+// visibleTo limits a query to published events, events without a status included,
+// and the events organized by the viewer
+func visibleTo(viewerID uuid.UUID) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("(COALESCE(status, '') IN ? OR organizer_id = ?)", []string{types.EventStatusPublished, ""}, viewerID)
+	}
+}
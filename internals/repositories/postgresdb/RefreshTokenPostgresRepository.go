@@ -0,0 +1,68 @@
+package postgresdb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lordofthemind/EventifyGo/internals/repositories"
+	"github.com/lordofthemind/EventifyGo/internals/types"
+	"gorm.io/gorm"
+)
+
+type postgresRefreshTokenRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresRefreshTokenRepository creates a new instance of postgresRefreshTokenRepository.
+func NewPostgresRefreshTokenRepository(db *gorm.DB) repositories.RefreshTokenRepositoryInterface {
+	return &postgresRefreshTokenRepository{db: db}
+}
+
+func (r *postgresRefreshTokenRepository) Create(ctx context.Context, token *types.RefreshTokenType) error {
+	if token.ID == uuid.Nil {
+		token.ID = uuid.New()
+	}
+	if token.CreatedAt.IsZero() {
+		token.CreatedAt = time.Now()
+	}
+	return r.db.WithContext(ctx).Create(token).Error
+}
+
+func (r *postgresRefreshTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*types.RefreshTokenType, error) {
+	var token types.RefreshTokenType
+	if err := r.db.WithContext(ctx).First(&token, "token_hash = ?", tokenHash).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repositories.ErrRefreshTokenNotFound
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+// MarkRotated only matches a live token, so concurrent refreshes cannot both rotate it
+func (r *postgresRefreshTokenRepository) MarkRotated(ctx context.Context, id uuid.UUID, at time.Time) error {
+	result := r.db.WithContext(ctx).Model(&types.RefreshTokenType{}).
+		Where("id = ? AND rotated_at IS NULL AND revoked_at IS NULL", id).
+		Update("rotated_at", at)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return repositories.ErrRefreshTokenUsed
+	}
+	return nil
+}
+
+func (r *postgresRefreshTokenRepository) RevokeFamily(ctx context.Context, familyID uuid.UUID, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&types.RefreshTokenType{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", at).Error
+}
+
+func (r *postgresRefreshTokenRepository) RevokeAll(ctx context.Context, superUserID uuid.UUID, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&types.RefreshTokenType{}).
+		Where("super_user_id = ? AND revoked_at IS NULL", superUserID).
+		Update("revoked_at", at).Error
+}
@@ -0,0 +1,92 @@
+package postgresdb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lordofthemind/EventifyGo/internals/repositories"
+	"github.com/lordofthemind/EventifyGo/internals/types"
+	"gorm.io/gorm"
+)
+
+type postgresSessionRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresSessionRepository creates a new instance of postgresSessionRepository.
+func NewPostgresSessionRepository(db *gorm.DB) repositories.SessionRepositoryInterface {
+	return &postgresSessionRepository{db: db}
+}
+
+func (r *postgresSessionRepository) Create(ctx context.Context, session *types.SessionType) error {
+	if session.ID == uuid.Nil {
+		session.ID = uuid.New()
+	}
+	if session.CreatedAt.IsZero() {
+		session.CreatedAt = time.Now()
+	}
+	return r.db.WithContext(ctx).Create(session).Error
+}
+
+func (r *postgresSessionRepository) FindByID(ctx context.Context, id uuid.UUID) (*types.SessionType, error) {
+	var session types.SessionType
+	if err := r.db.WithContext(ctx).First(&session, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repositories.ErrSessionNotFound
+		}
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *postgresSessionRepository) ListActive(ctx context.Context, superUserID uuid.UUID, now time.Time) ([]*types.SessionType, error) {
+	sessions := []*types.SessionType{}
+	err := r.db.WithContext(ctx).
+		Where("super_user_id = ? AND revoked_at IS NULL AND expires_at > ?", superUserID, now).
+		Order("last_seen_at DESC").
+		Find(&sessions).Error
+	if err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+func (r *postgresSessionRepository) Touch(ctx context.Context, id uuid.UUID, at time.Time, ipAddress string) error {
+	return r.updateByID(ctx, id, map[string]interface{}{"last_seen_at": at, "ip_address": ipAddress})
+}
+
+func (r *postgresSessionRepository) Extend(ctx context.Context, id uuid.UUID, expiresAt time.Time) error {
+	return r.updateByID(ctx, id, map[string]interface{}{"expires_at": expiresAt})
+}
+
+func (r *postgresSessionRepository) Revoke(ctx context.Context, id uuid.UUID, at time.Time) error {
+	err := r.db.WithContext(ctx).Model(&types.SessionType{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", at).Error
+	if err != nil {
+		return err
+	}
+	// Nothing matched either because the session is unknown or because it was already revoked
+	_, err = r.FindByID(ctx, id)
+	return err
+}
+
+func (r *postgresSessionRepository) RevokeAll(ctx context.Context, superUserID uuid.UUID, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&types.SessionType{}).
+		Where("super_user_id = ? AND revoked_at IS NULL", superUserID).
+		Update("revoked_at", at).Error
+}
+
+// Helper function to update a session, reporting unknown ones
+func (r *postgresSessionRepository) updateByID(ctx context.Context, id uuid.UUID, fields map[string]interface{}) error {
+	result := r.db.WithContext(ctx).Model(&types.SessionType{}).Where("id = ?", id).Updates(fields)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return repositories.ErrSessionNotFound
+	}
+	return nil
+}
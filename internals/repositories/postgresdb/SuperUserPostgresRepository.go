@@ -3,13 +3,14 @@ package postgresdb
 import (
 	"context"
 	"errors"
-	"fmt"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/lordofthemind/EventifyGo/internals/repositories"
 	"github.com/lordofthemind/EventifyGo/internals/types"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type postgresSuperUserRepository struct {
@@ -28,7 +29,13 @@ func (r *postgresSuperUserRepository) Create(ctx context.Context, superUser *typ
 	superUser.CreatedAt = time.Now()
 	superUser.UpdatedAt = time.Now()
 
-	return r.db.WithContext(ctx).Create(superUser).Error
+	if err := r.db.WithContext(ctx).Create(superUser).Error; err != nil {
+		if isUniqueViolation(err) {
+			return repositories.ErrDuplicateSuperUser
+		}
+		return err
+	}
+	return nil
 }
 
 // FindByID finds a super user by UUID
@@ -36,7 +43,7 @@ func (r *postgresSuperUserRepository) FindByID(ctx context.Context, id uuid.UUID
 	var superUser types.SuperUserType
 	if err := r.db.WithContext(ctx).First(&superUser, "id = ?", id).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("superuser not found")
+			return nil, repositories.ErrSuperUserNotFound
 		}
 		return nil, err
 	}
@@ -48,7 +55,7 @@ func (r *postgresSuperUserRepository) FindByEmail(ctx context.Context, email str
 	var superUser types.SuperUserType
 	if err := r.db.WithContext(ctx).First(&superUser, "email = ?", email).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("superuser not found")
+			return nil, repositories.ErrSuperUserNotFound
 		}
 		return nil, err
 	}
@@ -60,19 +67,7 @@ func (r *postgresSuperUserRepository) FindByUsername(ctx context.Context, userna
 	var superUser types.SuperUserType
 	if err := r.db.WithContext(ctx).First(&superUser, "username = ?", username).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("superuser not found")
-		}
-		return nil, err
-	}
-	return &superUser, nil
-}
-
-// FindByResetToken finds a super user by reset token
-func (r *postgresSuperUserRepository) FindByResetToken(ctx context.Context, token string) (*types.SuperUserType, error) {
-	var superUser types.SuperUserType
-	if err := r.db.WithContext(ctx).First(&superUser, "reset_token = ?", token).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return nil, fmt.Errorf("superuser not found")
+			return nil, repositories.ErrSuperUserNotFound
 		}
 		return nil, err
 	}
@@ -81,16 +76,27 @@ func (r *postgresSuperUserRepository) FindByResetToken(ctx context.Context, toke
 
 // DeleteByID deletes a super user by UUID
 func (r *postgresSuperUserRepository) DeleteByID(ctx context.Context, id uuid.UUID) error {
-	return r.db.WithContext(ctx).Delete(&types.SuperUserType{}, "id = ?", id).Error
+	result := r.db.WithContext(ctx).Delete(&types.SuperUserType{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return repositories.ErrSuperUserNotFound
+	}
+	return nil
 }
 
 // SearchSuperusers searches for super users based on a query string, with pagination and sorting
 func (r *postgresSuperUserRepository) SearchSuperusers(ctx context.Context, searchQuery string, page, limit int, sortBy string) ([]*types.SuperUserType, error) {
+	column, err := repositories.SuperUserSortKey(sortBy)
+	if err != nil {
+		return nil, err
+	}
 	var superUsers []*types.SuperUserType
 
 	query := r.db.WithContext(ctx).
 		Where("full_name ILIKE ? OR username ILIKE ? OR email ILIKE ?", "%"+searchQuery+"%", "%"+searchQuery+"%", "%"+searchQuery+"%").
-		Order(sortBy).
+		Order(clause.OrderByColumn{Column: clause.Column{Name: column}}).
 		Offset((page - 1) * limit).
 		Limit(limit).
 		Find(&superUsers)
@@ -105,30 +111,35 @@ func (r *postgresSuperUserRepository) SearchSuperusers(ctx context.Context, sear
 // Update updates an entire super user document
 func (r *postgresSuperUserRepository) Update(ctx context.Context, superUser *types.SuperUserType) error {
 	superUser.UpdatedAt = time.Now()
-	return r.db.WithContext(ctx).Save(superUser).Error
+	// Save would insert a missing super user, only update an existing row
+	return updateResult(r.db.WithContext(ctx).Model(superUser).Where("id = ?", superUser.ID).Select("*").Omit("created_at").Updates(superUser))
 }
 
 // UpdateField updates a single field of a super user document
 func (r *postgresSuperUserRepository) UpdateField(ctx context.Context, id uuid.UUID, field string, value interface{}) error {
-	return r.db.WithContext(ctx).Model(&types.SuperUserType{}).Where("id = ?", id).Update(field, value).Error
+	return updateResult(r.db.WithContext(ctx).Model(&types.SuperUserType{}).Where("id = ?", id).Update(field, value))
+}
+
+// UpdateFields updates several columns of a super user at once
+func (r *postgresSuperUserRepository) UpdateFields(ctx context.Context, id uuid.UUID, fields map[string]interface{}) error {
+	columns := map[string]interface{}{"updated_at": time.Now()}
+	for field, value := range fields {
+		columns[field] = value
+	}
+	return updateResult(r.db.WithContext(ctx).Model(&types.SuperUserType{}).Where("id = ?", id).Updates(columns))
 }
 
 // GetRoleByID retrieves the role of a super user by their UUID
 func (r *postgresSuperUserRepository) GetRoleByID(ctx context.Context, id uuid.UUID) (string, error) {
-	var role string
-	err := r.db.WithContext(ctx).Model(&types.SuperUserType{}).Where("id = ?", id).Pluck("role", &role).Error
+	var superUser types.SuperUserType
+	err := r.db.WithContext(ctx).Select("role").First(&superUser, "id = ?", id).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return "", fmt.Errorf("superuser not found")
+			return "", repositories.ErrSuperUserNotFound
 		}
 		return "", err
 	}
-	return role, nil
-}
-
-// UpdateResetToken updates the reset token of a super user
-func (r *postgresSuperUserRepository) UpdateResetToken(ctx context.Context, id uuid.UUID, token string) error {
-	return r.UpdateField(ctx, id, "reset_token", token)
+	return superUser.Role, nil
 }
 
 // UpdateSuperuserRole updates the role of a super user
@@ -136,6 +147,74 @@ func (r *postgresSuperUserRepository) UpdateSuperuserRole(ctx context.Context, i
 	return r.UpdateField(ctx, id, "role", role)
 }
 
+// SetResetToken stores the hash of a password reset token
+func (r *postgresSuperUserRepository) SetResetToken(ctx context.Context, id uuid.UUID, tokenHash string, expiresAt time.Time) error {
+	return updateResult(r.db.WithContext(ctx).Model(&types.SuperUserType{}).Where("id = ?", id).
+		Updates(map[string]interface{}{"reset_token_hash": tokenHash, "reset_token_expires": expiresAt, "updated_at": time.Now()}))
+}
+
+// ResetPassword sets a new password for the holder of a valid reset token, the condition makes the token single use
+func (r *postgresSuperUserRepository) ResetPassword(ctx context.Context, tokenHash, hashedPassword string) (uuid.UUID, error) {
+	var superUser types.SuperUserType
+	result := r.db.WithContext(ctx).Model(&superUser).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "id"}}}).
+		Where("reset_token_hash = ? AND reset_token_expires > ?", tokenHash, time.Now()).
+		Updates(map[string]interface{}{
+			"hashed_password":     hashedPassword,
+			"reset_token_hash":    nil,
+			"reset_token_expires": nil,
+			"updated_at":          time.Now(),
+		})
+	if result.Error != nil {
+		return uuid.Nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return uuid.Nil, repositories.ErrResetTokenNotFound
+	}
+	return superUser.ID, nil
+}
+
+// AdvanceTOTPStep records the time step of an accepted one-time code, the condition makes the check atomic
+func (r *postgresSuperUserRepository) AdvanceTOTPStep(ctx context.Context, id uuid.UUID, step int64) error {
+	result := r.db.WithContext(ctx).Model(&types.SuperUserType{}).
+		Where("id = ? AND last_totp_step < ?", id, step).
+		Updates(map[string]interface{}{"last_totp_step": step, "updated_at": time.Now()})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 1 {
+		return nil
+	}
+
+	// Nothing was updated, find out why
+	if _, err := r.FindByID(ctx, id); err != nil {
+		return err
+	}
+	return repositories.ErrTOTPStepUsed
+}
+
+// ConsumeRecoveryCode removes a used recovery code hash, the condition makes the check atomic
+func (r *postgresSuperUserRepository) ConsumeRecoveryCode(ctx context.Context, id uuid.UUID, codeHash string) error {
+	result := r.db.WithContext(ctx).Model(&types.SuperUserType{}).
+		Where("id = ? AND ? = ANY(recovery_code_hashes)", id, codeHash).
+		Updates(map[string]interface{}{
+			"recovery_code_hashes": gorm.Expr("array_remove(recovery_code_hashes, ?)", codeHash),
+			"updated_at":           time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 1 {
+		return nil
+	}
+
+	// Nothing was updated, find out why
+	if _, err := r.FindByID(ctx, id); err != nil {
+		return err
+	}
+	return repositories.ErrRecoveryCodeNotFound
+}
+
 // FindAll2FAEnabledSuperusers retrieves all super users with 2FA enabled
 func (r *postgresSuperUserRepository) FindAll2FAEnabledSuperusers(ctx context.Context) ([]*types.SuperUserType, error) {
 	var superUsers []*types.SuperUserType
@@ -148,15 +227,31 @@ func (r *postgresSuperUserRepository) FindAll2FAEnabledSuperusers(ctx context.Co
 
 // GetAllSuperUsers retrieves all super users from the PostgreSQL database
 func (r *postgresSuperUserRepository) GetAllSuperUsers(ctx context.Context) ([]*types.SuperUserType, error) {
-	var allSuperUsers []*types.SuperUserType
+	allSuperUsers := []*types.SuperUserType{}
 
 	if err := r.db.WithContext(ctx).Find(&allSuperUsers).Error; err != nil {
 		return nil, err
 	}
 
-	if len(allSuperUsers) == 0 {
-		return nil, errors.New("no super users found")
+	return allSuperUsers, nil
+}
+
+// Helper function to translate the result of a single row update
+func updateResult(result *gorm.DB) error {
+	if result.Error != nil {
+		if isUniqueViolation(result.Error) {
+			return repositories.ErrDuplicateSuperUser
+		}
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return repositories.ErrSuperUserNotFound
 	}
+	return nil
+}
 
-	return allSuperUsers, nil
+// Helper function to detect a violated unique constraint
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
 }
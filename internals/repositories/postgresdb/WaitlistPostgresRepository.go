@@ -0,0 +1,105 @@
+package postgresdb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lordofthemind/EventifyGo/internals/repositories"
+	"github.com/lordofthemind/EventifyGo/internals/types"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type postgresWaitlistRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresWaitlistRepository creates a new instance of postgresWaitlistRepository.
+func NewPostgresWaitlistRepository(db *gorm.DB) repositories.WaitlistRepositoryInterface {
+	return &postgresWaitlistRepository{db: db}
+}
+
+// waitlistOrder sorts entries by join time, using the id to break ties
+const waitlistOrder = "created_at ASC, id ASC"
+
+func (r *postgresWaitlistRepository) AddToWaitlist(ctx context.Context, entry *types.WaitlistEntryType) error {
+	if entry.ID == uuid.Nil {
+		entry.ID = uuid.New()
+	}
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+
+	// The unique (event_id, user_id) index turns a second join into a no-op
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(entry)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return repositories.ErrAlreadyWaitlisted
+	}
+	return nil
+}
+
+func (r *postgresWaitlistRepository) RemoveFromWaitlist(ctx context.Context, eventID, userID uuid.UUID) error {
+	result := r.db.WithContext(ctx).Where("event_id = ? AND user_id = ?", eventID, userID).Delete(&types.WaitlistEntryType{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return repositories.ErrNotWaitlisted
+	}
+	return nil
+}
+
+func (r *postgresWaitlistRepository) GetWaitlistEntry(ctx context.Context, eventID, userID uuid.UUID) (*types.WaitlistEntryType, error) {
+	var entry types.WaitlistEntryType
+	if err := r.db.WithContext(ctx).First(&entry, "event_id = ? AND user_id = ?", eventID, userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repositories.ErrNotWaitlisted
+		}
+		return nil, err
+	}
+
+	// Position is the number of entries that joined before this one, plus one
+	var ahead int64
+	err := r.db.WithContext(ctx).Model(&types.WaitlistEntryType{}).
+		Where("event_id = ? AND (created_at, id) < (?, ?)", eventID, entry.CreatedAt, entry.ID).
+		Count(&ahead).Error
+	if err != nil {
+		return nil, err
+	}
+	entry.Position = int(ahead) + 1
+	return &entry, nil
+}
+
+func (r *postgresWaitlistRepository) FirstInWaitlist(ctx context.Context, eventID uuid.UUID) (*types.WaitlistEntryType, error) {
+	var entry types.WaitlistEntryType
+	err := r.db.WithContext(ctx).Where("event_id = ?", eventID).Order(waitlistOrder).Take(&entry).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, repositories.ErrWaitlistEmpty
+		}
+		return nil, err
+	}
+	entry.Position = 1
+	return &entry, nil
+}
+
+func (r *postgresWaitlistRepository) ListWaitlist(ctx context.Context, eventID uuid.UUID) ([]*types.WaitlistEntryType, error) {
+	entries := []*types.WaitlistEntryType{}
+	if err := r.db.WithContext(ctx).Where("event_id = ?", eventID).Order(waitlistOrder).Find(&entries).Error; err != nil {
+		return nil, err
+	}
+
+	for i, entry := range entries {
+		entry.Position = i + 1
+	}
+	return entries, nil
+}
+
+func (r *postgresWaitlistRepository) ClearWaitlist(ctx context.Context, eventID uuid.UUID) error {
+	return r.db.WithContext(ctx).Where("event_id = ?", eventID).Delete(&types.WaitlistEntryType{}).Error
+}
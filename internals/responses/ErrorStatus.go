@@ -0,0 +1,29 @@
+package responses
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/lordofthemind/EventifyGo/internals/apperrors"
+)
+
+// ErrorStatus translates an error returned by a service into the HTTP status code of the
+// response, shared by the Gin and Fiber handlers. Errors of an unknown kind are server errors.
+func ErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, apperrors.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, apperrors.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, apperrors.ErrValidation):
+		return http.StatusBadRequest
+	case errors.Is(err, apperrors.ErrUnauthorized):
+		return http.StatusUnauthorized
+	case errors.Is(err, apperrors.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, apperrors.ErrTooManyRequests):
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusInternalServerError
+	}
+}
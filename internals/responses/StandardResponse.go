@@ -7,6 +7,9 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
+// RequestIDKey is the context key holding the request ID, set by the request ID middlewares
+const RequestIDKey = "RequestID"
+
 // StandardResponse defines the structure for API responses
 type StandardResponse struct {
 	Status    int         `json:"status"`
@@ -20,10 +23,8 @@ type StandardResponse struct {
 // NewResponse returns a standardized response and includes request ID from context
 func NewGinResponse(c *gin.Context, status int, message string, data interface{}, err interface{}) StandardResponse {
 	// Get the request ID from the context and ensure type safety
-	requestID, exists := c.Get("RequestID")
-	if !exists {
-		requestID = ""
-	}
+	requestID, _ := c.Get(RequestIDKey)
+	requestIDString, _ := requestID.(string)
 
 	return StandardResponse{
 		Status:    status,
@@ -31,14 +32,14 @@ func NewGinResponse(c *gin.Context, status int, message string, data interface{}
 		Data:      data,
 		Error:     err,
 		Timestamp: time.Now().Format(time.RFC3339),
-		RequestID: requestID.(string),
+		RequestID: requestIDString,
 	}
 }
 
 // NewResponse returns a standardized response and includes request ID from context
 func NewFiberResponse(c *fiber.Ctx, status int, message string, data interface{}, err interface{}) StandardResponse {
-	// Get the request ID from the context with a default value if it doesn't exist
-	requestID := c.Get("RequestID", "")
+	// Get the request ID from the locals set by the middleware, empty when it did not run
+	requestID, _ := c.Locals(RequestIDKey).(string)
 
 	return StandardResponse{
 		Status:    status,
@@ -46,6 +47,6 @@ func NewFiberResponse(c *fiber.Ctx, status int, message string, data interface{}
 		Data:      data,
 		Error:     err,
 		Timestamp: time.Now().Format(time.RFC3339),
-		RequestID: requestID, // no need to cast since it's already a string
+		RequestID: requestID,
 	}
 }
@@ -0,0 +1,12 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/lordofthemind/EventifyGo/internals/handlers"
+)
+
+func SetupAuthFiberRoutes(app *fiber.App, handler *handlers.AuthFiberHandler) {
+	app.Post("/auth/login", handler.LoginHandler)
+	app.Post("/auth/refresh", handler.RefreshHandler)
+	app.Post("/auth/logout", handler.LogoutHandler)
+}
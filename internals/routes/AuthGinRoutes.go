@@ -0,0 +1,12 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/lordofthemind/EventifyGo/internals/handlers"
+)
+
+func SetupAuthGinRoutes(r *gin.Engine, handler *handlers.AuthGinHandler) {
+	r.POST("/auth/login", handler.LoginHandler)
+	r.POST("/auth/refresh", handler.RefreshHandler)
+	r.POST("/auth/logout", handler.LogoutHandler)
+}
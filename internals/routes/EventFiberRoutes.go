@@ -0,0 +1,39 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/lordofthemind/EventifyGo/internals/handlers"
+	"github.com/lordofthemind/EventifyGo/internals/rbac"
+	"github.com/lordofthemind/EventifyGo/internals/services"
+	"github.com/lordofthemind/EventifyGo/pkgs/middlewares"
+	"github.com/lordofthemind/mygopher/gophertoken"
+)
+
+// Static paths are registered before /events/:id because Fiber matches routes in order.
+// Reading Events is public, a logged in SuperUser also sees their own unpublished events.
+// Changing an Event, its attendees or waitlist, or listing the waitlist, requires a logged in
+// SuperUser. The calendar feed of a SuperUser is for themselves or those allowed to read
+// SuperUsers.
+func SetupEventFiberRoutes(app *fiber.App, handler *handlers.EventFiberHandler, tokenManager gophertoken.TokenManager, sessions services.SessionServiceInterface, authorizer services.AuthorizationServiceInterface) {
+	auth := middlewares.AuthTokenFiberMiddleware(tokenManager, sessions)
+	identify := middlewares.OptionalAuthTokenFiberMiddleware(tokenManager, sessions)
+	selfOrRead := middlewares.RequireSelfOrPermissionFiber(authorizer, rbac.PermissionSuperUsersRead)
+	app.Post("/events", auth, handler.CreateEventHandler)
+	app.Get("/events", identify, handler.ListEventsHandler)
+	app.Get("/events/search", identify, handler.SearchEventsHandler)
+	app.Get("/events/count", identify, handler.CountEventsHandler)
+	app.Post("/events/import", auth, handler.ImportEventsHandler)
+	app.Get("/events/:id", identify, handler.GetEventByIDHandler)
+	app.Put("/events/:id", auth, handler.UpdateEventHandler)
+	app.Delete("/events/:id", auth, handler.DeleteEventHandler)
+	app.Put("/events/:id/status", auth, handler.ChangeEventStatusHandler)
+	app.Get("/events/:id/occurrences", identify, handler.ListOccurrencesHandler)
+	app.Put("/events/:id/occurrences/:occurrence", auth, handler.OverrideOccurrenceHandler)
+	app.Delete("/events/:id/occurrences/:occurrence", auth, handler.CancelOccurrenceHandler)
+	app.Post("/events/:id/attendees", auth, handler.RegisterAttendeeHandler)
+	app.Delete("/events/:id/attendees/:attendeeId", auth, handler.UnregisterAttendeeHandler)
+	app.Get("/events/:id/waitlist", auth, handler.ListWaitlistHandler)
+	app.Get("/events/:id/waitlist/:userId", auth, handler.GetWaitlistPositionHandler)
+	app.Delete("/events/:id/waitlist/:userId", auth, handler.LeaveWaitlistHandler)
+	app.Get("/superusers/:id/calendar.ics", auth, selfOrRead, handler.ExportSuperUserCalendarHandler)
+}
@@ -0,0 +1,38 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/lordofthemind/EventifyGo/internals/handlers"
+	"github.com/lordofthemind/EventifyGo/internals/rbac"
+	"github.com/lordofthemind/EventifyGo/internals/services"
+	"github.com/lordofthemind/EventifyGo/pkgs/middlewares"
+	"github.com/lordofthemind/mygopher/gophertoken"
+)
+
+// Reading Events is public, a logged in SuperUser also sees their own unpublished events.
+// Changing an Event, its attendees or waitlist, or listing the waitlist, requires a logged in
+// SuperUser. The calendar feed of a SuperUser is for themselves or those allowed to read
+// SuperUsers
+func SetupEventGinRoutes(r *gin.Engine, handler *handlers.EventGinHandler, tokenManager gophertoken.TokenManager, sessions services.SessionServiceInterface, authorizer services.AuthorizationServiceInterface) {
+	auth := middlewares.AuthTokenMiddleware(tokenManager, sessions)
+	identify := middlewares.OptionalAuthTokenMiddleware(tokenManager, sessions)
+	selfOrRead := middlewares.RequireSelfOrPermission(authorizer, rbac.PermissionSuperUsersRead)
+	r.POST("/events", auth, handler.CreateEventHandler)
+	r.GET("/events", identify, handler.ListEventsHandler)
+	r.GET("/events/search", identify, handler.SearchEventsHandler)
+	r.GET("/events/count", identify, handler.CountEventsHandler)
+	r.POST("/events/import", auth, handler.ImportEventsHandler)
+	r.GET("/events/:id", identify, handler.GetEventByIDHandler)
+	r.PUT("/events/:id", auth, handler.UpdateEventHandler)
+	r.DELETE("/events/:id", auth, handler.DeleteEventHandler)
+	r.PUT("/events/:id/status", auth, handler.ChangeEventStatusHandler)
+	r.GET("/events/:id/occurrences", identify, handler.ListOccurrencesHandler)
+	r.PUT("/events/:id/occurrences/:occurrence", auth, handler.OverrideOccurrenceHandler)
+	r.DELETE("/events/:id/occurrences/:occurrence", auth, handler.CancelOccurrenceHandler)
+	r.POST("/events/:id/attendees", auth, handler.RegisterAttendeeHandler)
+	r.DELETE("/events/:id/attendees/:attendeeId", auth, handler.UnregisterAttendeeHandler)
+	r.GET("/events/:id/waitlist", auth, handler.ListWaitlistHandler)
+	r.GET("/events/:id/waitlist/:userId", auth, handler.GetWaitlistPositionHandler)
+	r.DELETE("/events/:id/waitlist/:userId", auth, handler.LeaveWaitlistHandler)
+	r.GET("/superusers/:id/calendar.ics", auth, selfOrRead, handler.ExportSuperUserCalendarHandler)
+}
@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/lordofthemind/EventifyGo/internals/handlers"
+	"github.com/lordofthemind/EventifyGo/internals/rbac"
+	"github.com/lordofthemind/EventifyGo/internals/services"
+	"github.com/lordofthemind/EventifyGo/pkgs/middlewares"
+	"github.com/lordofthemind/mygopher/gophertoken"
+)
+
+// Lockouts are read and cleared by SuperUsers allowed to read and write SuperUsers
+func SetupLockoutFiberRoutes(app *fiber.App, handler *handlers.LockoutFiberHandler, tokenManager gophertoken.TokenManager, sessions services.SessionServiceInterface, authorizer services.AuthorizationServiceInterface) {
+	auth := middlewares.AuthTokenFiberMiddleware(tokenManager, sessions)
+	read := middlewares.RequirePermissionFiber(authorizer, rbac.PermissionSuperUsersRead)
+	write := middlewares.RequirePermissionFiber(authorizer, rbac.PermissionSuperUsersWrite)
+	app.Get("/superusers/:id/lockout", auth, read, handler.GetSuperUserLockoutHandler)
+	app.Post("/superusers/:id/unlock", auth, write, handler.UnlockSuperUserHandler)
+}
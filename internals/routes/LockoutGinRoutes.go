@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/lordofthemind/EventifyGo/internals/handlers"
+	"github.com/lordofthemind/EventifyGo/internals/rbac"
+	"github.com/lordofthemind/EventifyGo/internals/services"
+	"github.com/lordofthemind/EventifyGo/pkgs/middlewares"
+	"github.com/lordofthemind/mygopher/gophertoken"
+)
+
+// Lockouts are read and cleared by SuperUsers allowed to read and write SuperUsers
+func SetupLockoutGinRoutes(r *gin.Engine, handler *handlers.LockoutGinHandler, tokenManager gophertoken.TokenManager, sessions services.SessionServiceInterface, authorizer services.AuthorizationServiceInterface) {
+	auth := middlewares.AuthTokenMiddleware(tokenManager, sessions)
+	read := middlewares.RequirePermission(authorizer, rbac.PermissionSuperUsersRead)
+	write := middlewares.RequirePermission(authorizer, rbac.PermissionSuperUsersWrite)
+	r.GET("/superusers/:id/lockout", auth, read, handler.GetSuperUserLockoutHandler)
+	r.POST("/superusers/:id/unlock", auth, write, handler.UnlockSuperUserHandler)
+}
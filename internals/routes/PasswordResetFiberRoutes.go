@@ -0,0 +1,11 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/lordofthemind/EventifyGo/internals/handlers"
+)
+
+func SetupPasswordResetFiberRoutes(app *fiber.App, handler *handlers.PasswordResetFiberHandler) {
+	app.Post("/auth/password-reset", handler.RequestPasswordResetHandler)
+	app.Post("/auth/password-reset/confirm", handler.ConfirmPasswordResetHandler)
+}
@@ -0,0 +1,11 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/lordofthemind/EventifyGo/internals/handlers"
+)
+
+func SetupPasswordResetGinRoutes(r *gin.Engine, handler *handlers.PasswordResetGinHandler) {
+	r.POST("/auth/password-reset", handler.RequestPasswordResetHandler)
+	r.POST("/auth/password-reset/confirm", handler.ConfirmPasswordResetHandler)
+}
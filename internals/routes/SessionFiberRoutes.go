@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/lordofthemind/EventifyGo/internals/handlers"
+	"github.com/lordofthemind/EventifyGo/internals/rbac"
+	"github.com/lordofthemind/EventifyGo/internals/services"
+	"github.com/lordofthemind/EventifyGo/pkgs/middlewares"
+	"github.com/lordofthemind/mygopher/gophertoken"
+)
+
+// SuperUsers manage their own sessions, the sessions of others need the SuperUser permissions
+func SetupSessionFiberRoutes(app *fiber.App, handler *handlers.SessionFiberHandler, tokenManager gophertoken.TokenManager, sessions services.SessionServiceInterface, authorizer services.AuthorizationServiceInterface) {
+	auth := middlewares.AuthTokenFiberMiddleware(tokenManager, sessions)
+	selfOrRead := middlewares.RequireSelfOrPermissionFiber(authorizer, rbac.PermissionSuperUsersRead)
+	selfOrWrite := middlewares.RequireSelfOrPermissionFiber(authorizer, rbac.PermissionSuperUsersWrite)
+	app.Get("/superusers/:id/sessions", auth, selfOrRead, handler.ListSessionsHandler)
+	app.Delete("/superusers/:id/sessions", auth, selfOrWrite, handler.RevokeAllSessionsHandler)
+	app.Delete("/superusers/:id/sessions/:sessionID", auth, selfOrWrite, handler.RevokeSessionHandler)
+}
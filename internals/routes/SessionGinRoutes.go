@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/lordofthemind/EventifyGo/internals/handlers"
+	"github.com/lordofthemind/EventifyGo/internals/rbac"
+	"github.com/lordofthemind/EventifyGo/internals/services"
+	"github.com/lordofthemind/EventifyGo/pkgs/middlewares"
+	"github.com/lordofthemind/mygopher/gophertoken"
+)
+
+// SuperUsers manage their own sessions, the sessions of others need the SuperUser permissions
+func SetupSessionGinRoutes(r *gin.Engine, handler *handlers.SessionGinHandler, tokenManager gophertoken.TokenManager, sessions services.SessionServiceInterface, authorizer services.AuthorizationServiceInterface) {
+	auth := middlewares.AuthTokenMiddleware(tokenManager, sessions)
+	selfOrRead := middlewares.RequireSelfOrPermission(authorizer, rbac.PermissionSuperUsersRead)
+	selfOrWrite := middlewares.RequireSelfOrPermission(authorizer, rbac.PermissionSuperUsersWrite)
+	r.GET("/superusers/:id/sessions", auth, selfOrRead, handler.ListSessionsHandler)
+	r.DELETE("/superusers/:id/sessions", auth, selfOrWrite, handler.RevokeAllSessionsHandler)
+	r.DELETE("/superusers/:id/sessions/:sessionID", auth, selfOrWrite, handler.RevokeSessionHandler)
+}
@@ -3,22 +3,37 @@ package routes
 import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/lordofthemind/EventifyGo/internals/handlers"
+	"github.com/lordofthemind/EventifyGo/internals/rbac"
+	"github.com/lordofthemind/EventifyGo/internals/services"
+	"github.com/lordofthemind/EventifyGo/pkgs/middlewares"
+	"github.com/lordofthemind/mygopher/gophertoken"
 )
 
-func SetupSuperUserFiberRoutes(app *fiber.App, handler *handlers.SuperUserFiberHandler) {
-	app.Post("/superusers", handler.CreateSuperUserHandler)
-	app.Get("/superusers", handler.GetAllSuperUsersHandler)
-	app.Get("/superusers/:id", handler.GetSuperUserByIDHandler)
-	app.Get("/superusers/email/:email", handler.GetSuperUserByEmailHandler)
-	app.Get("/superusers/username/:username", handler.GetSuperUserByUsernameHandler)
-	app.Post("/superusers/:id/enable2fa", handler.Enable2FAForSuperUserHandler)
-	app.Post("/superusers/:id/disable2fa", handler.Disable2FAForSuperUserHandler)
-	app.Get("/superusers/2fa", handler.GetAll2FAEnabledSuperUsersHandler)
-	app.Put("/superusers/:id/role", handler.UpdateSuperUserRoleHandler)
-	app.Put("/superusers/:id/permissions", handler.UpdateSuperUserPermissionsHandler)
-	app.Put("/superusers/:id/field/:field", handler.UpdateSuperUserFieldHandler)
-	app.Post("/superusers/:id/generate-reset-token", handler.GenerateAndSetResetTokenHandler)
-	app.Post("/superusers/:id/clear-reset-token", handler.ClearResetTokenHandler)
-	app.Delete("/superusers/:id", handler.DeleteSuperUserByIDHandler)
-	app.Get("/superusers/search", handler.SearchSuperUsersHandler)
+// Static paths are registered before /superusers/:id because Fiber matches routes in order.
+// Every SuperUser route requires an authenticated SuperUser holding the permission of the
+// route, SuperUsers may read their own record and manage their own 2FA. Setting up 2FA and
+// recovery codes hands out secrets, so only the SuperUser themselves may do it.
+func SetupSuperUserFiberRoutes(app *fiber.App, handler *handlers.SuperUserFiberHandler, tokenManager gophertoken.TokenManager, sessions services.SessionServiceInterface, authorizer services.AuthorizationServiceInterface) {
+	auth := middlewares.AuthTokenFiberMiddleware(tokenManager, sessions)
+	read := middlewares.RequirePermissionFiber(authorizer, rbac.PermissionSuperUsersRead)
+	write := middlewares.RequirePermissionFiber(authorizer, rbac.PermissionSuperUsersWrite)
+	roles := middlewares.RequirePermissionFiber(authorizer, rbac.PermissionSuperUsersRoles)
+	selfOrRead := middlewares.RequireSelfOrPermissionFiber(authorizer, rbac.PermissionSuperUsersRead)
+	selfOrWrite := middlewares.RequireSelfOrPermissionFiber(authorizer, rbac.PermissionSuperUsersWrite)
+	app.Post("/superusers", auth, write, handler.CreateSuperUserHandler)
+	app.Get("/superusers", auth, read, handler.GetAllSuperUsersHandler)
+	app.Get("/superusers/2fa", auth, read, handler.GetAll2FAEnabledSuperUsersHandler)
+	app.Get("/superusers/search", auth, read, handler.SearchSuperUsersHandler)
+	app.Get("/superusers/email/:email", auth, read, handler.GetSuperUserByEmailHandler)
+	app.Get("/superusers/username/:username", auth, read, handler.GetSuperUserByUsernameHandler)
+	app.Get("/superusers/:id", auth, selfOrRead, handler.GetSuperUserByIDHandler)
+	app.Post("/superusers/:id/setup2fa", auth, handler.Setup2FAForSuperUserHandler)
+	app.Post("/superusers/:id/enable2fa", auth, handler.Enable2FAForSuperUserHandler)
+	app.Post("/superusers/:id/disable2fa", auth, selfOrWrite, handler.Disable2FAForSuperUserHandler)
+	app.Post("/superusers/:id/recovery-codes", auth, handler.RegenerateRecoveryCodesHandler)
+	app.Get("/superusers/:id/recovery-codes", auth, selfOrRead, handler.CountRecoveryCodesHandler)
+	app.Put("/superusers/:id/role", auth, roles, handler.UpdateSuperUserRoleHandler)
+	app.Put("/superusers/:id/permissions", auth, roles, handler.UpdateSuperUserPermissionsHandler)
+	app.Patch("/superusers/:id", auth, selfOrWrite, handler.PatchSuperUserHandler)
+	app.Delete("/superusers/:id", auth, write, handler.DeleteSuperUserByIDHandler)
 }
@@ -3,22 +3,36 @@ package routes
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/lordofthemind/EventifyGo/internals/handlers"
+	"github.com/lordofthemind/EventifyGo/internals/rbac"
+	"github.com/lordofthemind/EventifyGo/internals/services"
+	"github.com/lordofthemind/EventifyGo/pkgs/middlewares"
+	"github.com/lordofthemind/mygopher/gophertoken"
 )
 
-func SetupSuperUserGinRoutes(r *gin.Engine, handler *handlers.SuperUserGinHandler) {
-	r.POST("/superusers", handler.CreateSuperUserHandler)
-	r.GET("/superusers", handler.GetAllSuperUsersHandler)
-	r.GET("/superusers/:id", handler.GetSuperUserByIDHandler)
-	r.GET("/superusers/email/:email", handler.GetSuperUserByEmailHandler)
-	r.GET("/superusers/username/:username", handler.GetSuperUserByUsernameHandler)
-	r.POST("/superusers/:id/enable2fa", handler.Enable2FAForSuperUserHandler)
-	r.POST("/superusers/:id/disable2fa", handler.Disable2FAForSuperUserHandler)
-	r.GET("/superusers/2fa", handler.GetAll2FAEnabledSuperUsersHandler)
-	r.PUT("/superusers/:id/role", handler.UpdateSuperUserRoleHandler)
-	r.PUT("/superusers/:id/permissions", handler.UpdateSuperUserPermissionsHandler)
-	r.PUT("/superusers/:id/field/:field", handler.UpdateSuperUserFieldHandler)
-	r.POST("/superusers/:id/generate-reset-token", handler.GenerateAndSetResetTokenHandler)
-	r.POST("/superusers/:id/clear-reset-token", handler.ClearResetTokenHandler)
-	r.DELETE("/superusers/:id", handler.DeleteSuperUserByIDHandler)
-	r.GET("/superusers/search", handler.SearchSuperUsersHandler)
+// Every SuperUser route requires an authenticated SuperUser holding the permission of the
+// route, SuperUsers may read their own record and manage their own 2FA. Setting up 2FA and
+// recovery codes hands out secrets, so only the SuperUser themselves may do it.
+func SetupSuperUserGinRoutes(r *gin.Engine, handler *handlers.SuperUserGinHandler, tokenManager gophertoken.TokenManager, sessions services.SessionServiceInterface, authorizer services.AuthorizationServiceInterface) {
+	auth := middlewares.AuthTokenMiddleware(tokenManager, sessions)
+	read := middlewares.RequirePermission(authorizer, rbac.PermissionSuperUsersRead)
+	write := middlewares.RequirePermission(authorizer, rbac.PermissionSuperUsersWrite)
+	roles := middlewares.RequirePermission(authorizer, rbac.PermissionSuperUsersRoles)
+	selfOrRead := middlewares.RequireSelfOrPermission(authorizer, rbac.PermissionSuperUsersRead)
+	selfOrWrite := middlewares.RequireSelfOrPermission(authorizer, rbac.PermissionSuperUsersWrite)
+	r.POST("/superusers", auth, write, handler.CreateSuperUserHandler)
+	r.GET("/superusers", auth, read, handler.GetAllSuperUsersHandler)
+	r.GET("/superusers/2fa", auth, read, handler.GetAll2FAEnabledSuperUsersHandler)
+	r.GET("/superusers/search", auth, read, handler.SearchSuperUsersHandler)
+	r.GET("/superusers/email/:email", auth, read, handler.GetSuperUserByEmailHandler)
+	r.GET("/superusers/username/:username", auth, read, handler.GetSuperUserByUsernameHandler)
+	r.GET("/superusers/:id", auth, selfOrRead, handler.GetSuperUserByIDHandler)
+	r.POST("/superusers/:id/setup2fa", auth, handler.Setup2FAForSuperUserHandler)
+	r.POST("/superusers/:id/enable2fa", auth, handler.Enable2FAForSuperUserHandler)
+	r.POST("/superusers/:id/disable2fa", auth, selfOrWrite, handler.Disable2FAForSuperUserHandler)
+	r.POST("/superusers/:id/recovery-codes", auth, handler.RegenerateRecoveryCodesHandler)
+	r.GET("/superusers/:id/recovery-codes", auth, selfOrRead, handler.CountRecoveryCodesHandler)
+	r.PUT("/superusers/:id/role", auth, roles, handler.UpdateSuperUserRoleHandler)
+	r.PUT("/superusers/:id/permissions", auth, roles, handler.UpdateSuperUserPermissionsHandler)
+	r.PATCH("/superusers/:id", auth, selfOrWrite, handler.PatchSuperUserHandler)
+	r.DELETE("/superusers/:id", auth, write, handler.DeleteSuperUserByIDHandler)
 }
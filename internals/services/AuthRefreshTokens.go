@@ -0,0 +1,172 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lordofthemind/EventifyGo/internals/apperrors"
+	"github.com/lordofthemind/EventifyGo/internals/repositories"
+	"github.com/lordofthemind/EventifyGo/internals/types"
+)
+
+// refreshTokenBytes is the randomness of a refresh token
+const refreshTokenBytes = 32
+
+// Errors returned while refreshing tokens
+var (
+	ErrInvalidRefreshToken = apperrors.Unauthorized("refresh token is invalid or expired")
+	ErrRefreshTokenReused  = apperrors.Unauthorized("refresh token was already used, the session was revoked")
+)
+
+// Trade a refresh token for a new pair. A rotated token presented again means it leaked, so
+// its session is revoked and its holder, legitimate or not, has to log in again.
+func (s *AuthService) Refresh(ctx context.Context, refreshToken string, client ClientInfo) (*LoginResult, error) {
+	stored, err := s.findRefreshToken(ctx, refreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if stored.RevokedAt != nil || !now.Before(stored.ExpiresAt) {
+		return nil, ErrInvalidRefreshToken
+	}
+	if stored.RotatedAt != nil {
+		return nil, s.revokeReusedFamily(ctx, stored.FamilyID, now)
+	}
+
+	// The family of a refresh token is its session
+	session, err := s.sessions.FindByID(ctx, stored.FamilyID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrSessionNotFound) {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+	if session.RevokedAt != nil {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	if err := s.refreshTokens.MarkRotated(ctx, stored.ID, now); err != nil {
+		if errors.Is(err, repositories.ErrRefreshTokenUsed) {
+			// Another request rotated the token first
+			return nil, s.revokeReusedFamily(ctx, stored.FamilyID, now)
+		}
+		return nil, fmt.Errorf("failed to rotate refresh token: %w", err)
+	}
+
+	superUser, err := s.repo.FindByID(ctx, stored.SuperUserID)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, fmt.Errorf("failed to get superuser: %w", err)
+	}
+
+	result, err := s.issueTokens(ctx, superUser, session.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The session lives as long as its latest refresh token
+	if err := s.sessions.Extend(ctx, session.ID, result.RefreshExpiresAt); err != nil {
+		return nil, fmt.Errorf("failed to extend session: %w", err)
+	}
+	if err := s.sessions.Touch(ctx, session.ID, now, client.IP); err != nil {
+		return nil, fmt.Errorf("failed to update session: %w", err)
+	}
+	return result, nil
+}
+
+// Revoke the session of a refresh token
+func (s *AuthService) Logout(ctx context.Context, refreshToken string) error {
+	stored, err := s.findRefreshToken(ctx, refreshToken)
+	if err != nil {
+		if errors.Is(err, ErrInvalidRefreshToken) {
+			return nil
+		}
+		return err
+	}
+
+	return revokeSession(ctx, s.sessions, s.refreshTokens, stored.FamilyID, time.Now())
+}
+
+// Helper function to issue an access token and a refresh token of a session
+func (s *AuthService) issueTokens(ctx context.Context, superUser *types.SuperUserType, sessionID uuid.UUID) (*LoginResult, error) {
+	token, err := s.tokenManager.GenerateToken(accessTokenSubject(superUser.ID, sessionID), s.accessDuration)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	refreshToken, err := generateRefreshToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	stored := &types.RefreshTokenType{
+		FamilyID:    sessionID,
+		SuperUserID: superUser.ID,
+		TokenHash:   hashRefreshToken(refreshToken),
+		CreatedAt:   now,
+		ExpiresAt:   now.Add(s.refreshDuration),
+	}
+	if err := s.refreshTokens.Create(ctx, stored); err != nil {
+		return nil, fmt.Errorf("failed to store refresh token: %w", err)
+	}
+
+	return &LoginResult{
+		SuperUser:        superUser,
+		Token:            token,
+		ExpiresAt:        now.Add(s.accessDuration),
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: stored.ExpiresAt,
+	}, nil
+}
+
+// Helper function to look up a refresh token by its hash
+func (s *AuthService) findRefreshToken(ctx context.Context, refreshToken string) (*types.RefreshTokenType, error) {
+	refreshToken = strings.TrimSpace(refreshToken)
+	if refreshToken == "" {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	stored, err := s.refreshTokens.FindByHash(ctx, hashRefreshToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, repositories.ErrRefreshTokenNotFound) {
+			return nil, ErrInvalidRefreshToken
+		}
+		return nil, fmt.Errorf("failed to get refresh token: %w", err)
+	}
+	return stored, nil
+}
+
+// Helper function to revoke a session after one of its rotated tokens was presented again
+func (s *AuthService) revokeReusedFamily(ctx context.Context, familyID uuid.UUID, at time.Time) error {
+	if err := revokeSession(ctx, s.sessions, s.refreshTokens, familyID, at); err != nil {
+		return err
+	}
+	return ErrRefreshTokenReused
+}
+
+// Helper function to generate a URL safe refresh token
+func generateRefreshToken() (string, error) {
+	raw := make([]byte, refreshTokenBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// Helper function to hash a refresh token, it is random so a plain SHA-256 is enough
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
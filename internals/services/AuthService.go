@@ -0,0 +1,153 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lordofthemind/EventifyGo/internals/apperrors"
+	"github.com/lordofthemind/EventifyGo/internals/repositories"
+	"github.com/lordofthemind/EventifyGo/internals/types"
+	"github.com/lordofthemind/mygopher/gophertoken"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Errors returned by the auth service
+var (
+	ErrInvalidCredentials    = apperrors.Unauthorized("invalid credentials")
+	ErrTwoFactorRequired     = apperrors.Unauthorized("two-factor code required")
+	ErrTwoFactorCodeRejected = apperrors.Unauthorized("invalid or already used two-factor code")
+)
+
+// LoginResult is a successful login or refresh. The access token identifies the SuperUser by
+// ID, so it survives a change of username.
+type LoginResult struct {
+	SuperUser        *types.SuperUserType `json:"superuser"`
+	Token            string               `json:"-"`
+	ExpiresAt        time.Time            `json:"expires_at"`
+	RefreshToken     string               `json:"-"`
+	RefreshExpiresAt time.Time            `json:"refresh_expires_at"`
+}
+
+// ClientInfo describes the device a login or refresh comes from
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+}
+
+type AuthService struct {
+	repo            repositories.SuperUserRepositoryInterface
+	sessions        repositories.SessionRepositoryInterface
+	refreshTokens   repositories.RefreshTokenRepositoryInterface
+	tokenManager    gophertoken.TokenManager
+	accessDuration  time.Duration
+	refreshDuration time.Duration
+	lockout         LockoutServiceInterface
+}
+
+func NewAuthService(repo repositories.SuperUserRepositoryInterface, sessions repositories.SessionRepositoryInterface, refreshTokens repositories.RefreshTokenRepositoryInterface, tokenManager gophertoken.TokenManager, accessDuration, refreshDuration time.Duration, lockout LockoutServiceInterface) AuthServiceInterface {
+	return &AuthService{
+		repo:            repo,
+		sessions:        sessions,
+		refreshTokens:   refreshTokens,
+		tokenManager:    tokenManager,
+		accessDuration:  accessDuration,
+		refreshDuration: refreshDuration,
+		lockout:         lockout,
+	}
+}
+
+// Log a SuperUser in with an email or username and a password, plus a TOTP or recovery code when 2FA is enabled.
+// Failed attempts are counted per account and per client IP and slow down further attempts.
+func (s *AuthService) Login(ctx context.Context, identifier, password, code string, client ClientInfo) (*LoginResult, error) {
+	identifier = strings.TrimSpace(identifier)
+	if identifier == "" || password == "" {
+		return nil, fmt.Errorf("%w: identifier and password are required", apperrors.ErrValidation)
+	}
+
+	superUser, err := s.findByIdentifier(ctx, identifier)
+	if err != nil && !errors.Is(err, apperrors.ErrNotFound) {
+		return nil, fmt.Errorf("failed to get superuser: %w", err)
+	}
+
+	// Unknown accounts are throttled by identifier, so they behave like existing ones
+	account := strings.ToLower(identifier)
+	if superUser != nil {
+		account = superUser.ID.String()
+	}
+	endLogin, err := s.lockout.BeginLogin(ctx, account, client.IP)
+	if err != nil {
+		return nil, err
+	}
+	defer endLogin()
+
+	if superUser == nil {
+		// Compare against a dummy hash so unknown accounts take as long as wrong passwords
+		bcrypt.CompareHashAndPassword(dummyPasswordHash(), []byte(password))
+		return nil, s.loginFailed(ctx, account, client.IP, ErrInvalidCredentials)
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(superUser.HashedPassword), []byte(password)); err != nil {
+		return nil, s.loginFailed(ctx, account, client.IP, ErrInvalidCredentials)
+	}
+
+	if superUser.Is2FAEnabled {
+		if code == "" {
+			return nil, ErrTwoFactorRequired
+		}
+		valid, err := consumeSecondFactor(ctx, s.repo, superUser, code)
+		if err != nil {
+			return nil, err
+		}
+		if !valid {
+			return nil, s.loginFailed(ctx, account, client.IP, ErrTwoFactorCodeRejected)
+		}
+	}
+
+	if err := s.lockout.RecordSuccess(ctx, account); err != nil {
+		return nil, err
+	}
+
+	// Every login starts a new session, which is also the family of its refresh tokens
+	now := time.Now()
+	session := &types.SessionType{
+		ID:          uuid.New(),
+		SuperUserID: superUser.ID,
+		UserAgent:   client.UserAgent,
+		IPAddress:   client.IP,
+		CreatedAt:   now,
+		LastSeenAt:  now,
+		ExpiresAt:   now.Add(s.refreshDuration),
+	}
+	if err := s.sessions.Create(ctx, session); err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+
+	return s.issueTokens(ctx, superUser, session.ID)
+}
+
+// Helper function to count a failed login before returning its error
+func (s *AuthService) loginFailed(ctx context.Context, account, clientIP string, loginErr error) error {
+	if err := s.lockout.RecordFailure(ctx, account, clientIP); err != nil {
+		return err
+	}
+	return loginErr
+}
+
+// Helper function to find a SuperUser by email when the identifier looks like one, by username otherwise
+func (s *AuthService) findByIdentifier(ctx context.Context, identifier string) (*types.SuperUserType, error) {
+	if strings.Contains(identifier, "@") {
+		return s.repo.FindByEmail(ctx, identifier)
+	}
+	return s.repo.FindByUsername(ctx, identifier)
+}
+
+// dummyPasswordHash is computed on first use to keep package initialization cheap
+var dummyPasswordHash = sync.OnceValue(func() []byte {
+	hash, _ := bcrypt.GenerateFromPassword([]byte("eventify-dummy-password"), bcrypt.DefaultCost)
+	return hash
+})
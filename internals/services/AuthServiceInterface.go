@@ -0,0 +1,19 @@
+package services
+
+import (
+	"context"
+)
+
+type AuthServiceInterface interface {
+	// Check the credentials of a SuperUser, identified by email or username, start a session and issue its tokens.
+	// The code is a TOTP code or an unused recovery code, required when the SuperUser has 2FA enabled.
+	// Failed logins from the client IP or for the account are throttled with a LoginThrottledError.
+	Login(ctx context.Context, identifier, password, code string, client ClientInfo) (*LoginResult, error)
+
+	// Trade a refresh token for a new access and refresh token. The refresh token is single use,
+	// presenting a rotated one again revokes every token issued from the same login.
+	Refresh(ctx context.Context, refreshToken string, client ClientInfo) (*LoginResult, error)
+
+	// End the session of a refresh token, revoking its tokens. Unknown tokens are ignored.
+	Logout(ctx context.Context, refreshToken string) error
+}
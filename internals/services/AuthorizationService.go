@@ -0,0 +1,32 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/lordofthemind/EventifyGo/internals/apperrors"
+	"github.com/lordofthemind/EventifyGo/internals/rbac"
+	"github.com/lordofthemind/EventifyGo/internals/repositories"
+)
+
+type AuthorizationService struct {
+	repo repositories.SuperUserRepositoryInterface
+}
+
+func NewAuthorizationService(repo repositories.SuperUserRepositoryInterface) AuthorizationServiceInterface {
+	return &AuthorizationService{repo: repo}
+}
+
+// Check a permission of a SuperUser, a deleted SuperUser holds none
+func (s *AuthorizationService) HasPermission(ctx context.Context, id uuid.UUID, permission string) (bool, error) {
+	superUser, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get superuser: %w", err)
+	}
+	return rbac.Has(superUser.Role, superUser.PermissionGroups, permission), nil
+}
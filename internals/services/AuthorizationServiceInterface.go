@@ -0,0 +1,12 @@
+package services
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type AuthorizationServiceInterface interface {
+	// Report whether a SuperUser holds a permission through their role or permission groups
+	HasPermission(ctx context.Context, id uuid.UUID, permission string) (bool, error)
+}
@@ -0,0 +1,155 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lordofthemind/EventifyGo/internals/types"
+	"github.com/lordofthemind/EventifyGo/pkgs/icalendar"
+)
+
+const (
+	calendarProdID    = "-//EventifyGo//Events//EN"
+	calendarUIDDomain = "eventifygo"
+)
+
+// Export a single Event the viewer may see as an iCalendar document. A recurring Event is
+// exported together with the overrides of its occurrences.
+func (s *EventService) ExportEventCalendar(ctx context.Context, viewerID, eventID uuid.UUID) ([]byte, error) {
+	event, err := s.GetEventByID(ctx, viewerID, eventID)
+	if err != nil {
+		return nil, err
+	}
+
+	events := []*types.EventType{event}
+	if event.IsRecurring() {
+		overrides, err := s.repo.ListSeriesOverrides(ctx, eventID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list occurrence overrides: %w", err)
+		}
+		events = append(events, overrides...)
+	}
+
+	return s.buildCalendar(ctx, event.Name, events)
+}
+
+// Export the calendar feed of a SuperUser covering the Events they organize or attend. Only
+// the Events the viewer may see are included, so others get the published ones.
+func (s *EventService) ExportSuperUserCalendar(ctx context.Context, viewerID, superUserID uuid.UUID) ([]byte, error) {
+	superUser, err := s.superUserRepo.FindByID(ctx, superUserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get superuser: %w", err)
+	}
+
+	participating, err := s.repo.ListEventsByParticipant(ctx, superUserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list events: %w", err)
+	}
+	var events []*types.EventType
+	for _, event := range participating {
+		if event.IsVisibleTo(viewerID) {
+			events = append(events, event)
+		}
+	}
+
+	// Overrides belong to the series they modify, so pull in those of every series in the feed
+	seen := make(map[uuid.UUID]bool, len(events))
+	for _, event := range events {
+		seen[event.EventID] = true
+	}
+	for _, event := range events {
+		if !event.IsRecurring() {
+			continue
+		}
+		overrides, err := s.repo.ListSeriesOverrides(ctx, event.EventID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list occurrence overrides: %w", err)
+		}
+		for _, override := range overrides {
+			if !seen[override.EventID] && override.IsVisibleTo(viewerID) {
+				seen[override.EventID] = true
+				events = append(events, override)
+			}
+		}
+	}
+
+	name := superUser.FullName
+	if name == "" {
+		name = superUser.Username
+	}
+	return s.buildCalendar(ctx, name+" - EventifyGo", events)
+}
+
+// Helper function to turn Events into an iCalendar document
+func (s *EventService) buildCalendar(ctx context.Context, name string, events []*types.EventType) ([]byte, error) {
+	organizers := make(map[uuid.UUID]*icalendar.Organizer)
+	calendar := icalendar.Calendar{ProdID: calendarProdID, Name: name}
+
+	for _, event := range events {
+		organizer, cached := organizers[event.OrganizerID]
+		if !cached {
+			// A missing organizer only drops the ORGANIZER property
+			if superUser, err := s.superUserRepo.FindByID(ctx, event.OrganizerID); err == nil {
+				organizer = &icalendar.Organizer{Name: superUser.FullName, Email: superUser.Email}
+			}
+			organizers[event.OrganizerID] = organizer
+		}
+		calendar.Events = append(calendar.Events, toICalEvent(event, organizer))
+	}
+
+	data, err := calendar.Marshal()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode calendar: %w", err)
+	}
+	return data, nil
+}
+
+// Helper function to map an Event onto a VEVENT
+func toICalEvent(event *types.EventType, organizer *icalendar.Organizer) icalendar.Event {
+	// An event without an end is written without DTEND
+	end := time.Time{}
+	if event.EndDate != nil {
+		end = *event.EndDate
+	}
+	return icalendar.Event{
+		UID:          eventUID(event),
+		Summary:      event.Name,
+		Description:  event.Description,
+		Location:     event.Location,
+		Start:        event.Date,
+		End:          end,
+		AllDay:       event.AllDay,
+		TimeZone:     event.Zone(),
+		Organizer:    organizer,
+		RRule:        event.RecurrenceRule,
+		ExDates:      event.RecurrenceExDates,
+		RecurrenceID: event.RecurrenceID,
+		Status:       icalStatus(event),
+		Created:      event.CreatedAt,
+		LastModified: event.UpdatedAt,
+	}
+}
+
+// eventUID is the stable iCalendar UID of an Event. Overrides share the UID
+// of their series and are told apart by RECURRENCE-ID.
+func eventUID(event *types.EventType) string {
+	id := event.EventID
+	if event.SeriesID != nil {
+		id = *event.SeriesID
+	}
+	return fmt.Sprintf("%s@%s", id, calendarUIDDomain)
+}
+
+// icalStatus maps the lifecycle status of an Event onto the VEVENT STATUS
+func icalStatus(event *types.EventType) string {
+	switch event.LifecycleStatus() {
+	case types.EventStatusDraft:
+		return "TENTATIVE"
+	case types.EventStatusCancelled:
+		return "CANCELLED"
+	default:
+		return "CONFIRMED"
+	}
+}
@@ -0,0 +1,457 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lordofthemind/EventifyGo/internals/apperrors"
+	"github.com/lordofthemind/EventifyGo/internals/types"
+	"github.com/lordofthemind/EventifyGo/pkgs/icalendar"
+)
+
+// Formats accepted by ImportEvents
+const (
+	ImportFormatICS = "ics"
+	ImportFormatCSV = "csv"
+)
+
+// Outcome of a single imported row. In a dry run "created" means the row would be created.
+const (
+	ImportActionCreated = "created"
+	ImportActionSkipped = "skipped"
+	ImportActionFailed  = "failed"
+)
+
+// maxImportRows bounds the size of a single import
+const maxImportRows = 5000
+
+// Errors that reject an import as a whole
+var (
+	ErrUnsupportedImportFormat = apperrors.Validation("unsupported import format, expected ics or csv")
+	ErrTooManyImportRows       = apperrors.Validation(fmt.Sprintf("an import is limited to %d rows", maxImportRows))
+)
+
+// csvColumns are the columns understood in a CSV import, name and date are required
+var csvColumns = map[string]bool{
+	"external_id":     false,
+	"name":            true,
+	"description":     false,
+	"date":            true,
+	"end_date":        false,
+	"time_zone":       false,
+	"all_day":         false,
+	"status":          false,
+	"location":        false,
+	"capacity":        false,
+	"recurrence_rule": false,
+}
+
+// ImportOptions controls an import. DefaultCapacity applies to rows without a capacity,
+// which includes every iCalendar event.
+type ImportOptions struct {
+	Format          string
+	OrganizerID     uuid.UUID
+	DefaultCapacity int
+	DryRun          bool
+}
+
+// ImportRowResult reports what happened to a single row or VEVENT
+type ImportRowResult struct {
+	Row        int        `json:"row"`
+	Line       int        `json:"line"`
+	ExternalID string     `json:"external_id,omitempty"`
+	Name       string     `json:"name,omitempty"`
+	Action     string     `json:"action"`
+	EventID    *uuid.UUID `json:"event_id,omitempty"`
+	Error      string     `json:"error,omitempty"`
+}
+
+// ImportResult summarizes an import
+type ImportResult struct {
+	DryRun  bool              `json:"dry_run"`
+	Total   int               `json:"total"`
+	Created int               `json:"created"`
+	Skipped int               `json:"skipped"`
+	Failed  int               `json:"failed"`
+	Rows    []ImportRowResult `json:"rows"`
+}
+
+// importRow is a decoded row waiting to be imported
+type importRow struct {
+	row, line  int
+	externalID string
+	event      *types.EventType
+	err        error
+}
+
+// Import Events from an iCalendar or CSV file. Every row is validated and reported on its
+// own. Rows are keyed on their iCal UID or external_id, so importing the same file again
+// skips what already exists. A dry run validates without writing anything.
+func (s *EventService) ImportEvents(ctx context.Context, r io.Reader, opts ImportOptions) (*ImportResult, error) {
+	if err := s.checkOrganizer(ctx, opts.OrganizerID); err != nil {
+		return nil, err
+	}
+
+	var (
+		rows []*importRow
+		err  error
+	)
+	switch strings.ToLower(opts.Format) {
+	case ImportFormatICS:
+		rows, err = decodeICSRows(r, opts)
+	case ImportFormatCSV:
+		rows, err = decodeCSVRows(r, opts)
+	default:
+		return nil, ErrUnsupportedImportFormat
+	}
+	// A file that cannot be decoded is rejected as invalid input
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", apperrors.ErrValidation, err)
+	}
+	if len(rows) > maxImportRows {
+		return nil, ErrTooManyImportRows
+	}
+
+	importer := &eventImporter{
+		service: s,
+		opts:    opts,
+		seen:    make(map[string]int),
+		masters: make(map[string]*types.EventType),
+	}
+
+	// Series masters go first so the overrides in the same file can find them
+	results := make([]ImportRowResult, 0, len(rows))
+	for _, row := range rows {
+		if row.err != nil || row.event.RecurrenceID == nil {
+			results = append(results, importer.importEvent(ctx, row))
+		}
+	}
+	for _, row := range rows {
+		if row.err == nil && row.event.RecurrenceID != nil {
+			results = append(results, importer.importOverride(ctx, row))
+		}
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Row < results[j].Row })
+
+	result := &ImportResult{DryRun: opts.DryRun, Total: len(results), Rows: results}
+	for _, row := range results {
+		switch row.Action {
+		case ImportActionCreated:
+			result.Created++
+		case ImportActionSkipped:
+			result.Skipped++
+		default:
+			result.Failed++
+		}
+	}
+	return result, nil
+}
+
+// eventImporter carries the state of a single import
+type eventImporter struct {
+	service *EventService
+	opts    ImportOptions
+	// seen maps the import keys of this file to the row that used them first
+	seen map[string]int
+	// masters maps external ids to stored or, in a dry run, prepared series masters
+	masters map[string]*types.EventType
+}
+
+func (im *eventImporter) importEvent(ctx context.Context, row *importRow) ImportRowResult {
+	result := newImportRowResult(row)
+	if row.err != nil {
+		return failImportRow(result, row.err)
+	}
+	if row.externalID == "" {
+		return failImportRow(result, errors.New("external id is required"))
+	}
+	if first, dup := im.seen[row.externalID]; dup {
+		return failImportRow(result, fmt.Errorf("external id already used in row %d", first))
+	}
+	im.seen[row.externalID] = row.row
+
+	existing, err := im.service.repo.FindEventByExternalID(ctx, im.opts.OrganizerID, row.externalID)
+	if err == nil {
+		im.masters[row.externalID] = existing
+		result.Action = ImportActionSkipped
+		result.EventID = &existing.EventID
+		return result
+	}
+	if !errors.Is(err, ErrEventNotFound) {
+		return failImportRow(result, err)
+	}
+
+	event := row.event
+	event.OrganizerID = im.opts.OrganizerID
+	event.ExternalID = row.externalID
+	if err := prepareNewEvent(event); err != nil {
+		return failImportRow(result, err)
+	}
+
+	if !im.opts.DryRun {
+		if err := im.service.insertEvent(ctx, event); err != nil {
+			return failImportRow(result, err)
+		}
+		result.EventID = &event.EventID
+	}
+	im.masters[row.externalID] = event
+	result.Action = ImportActionCreated
+	return result
+}
+
+func (im *eventImporter) importOverride(ctx context.Context, row *importRow) ImportRowResult {
+	result := newImportRowResult(row)
+	recurrenceID := *row.event.RecurrenceID
+
+	key := row.externalID + "@" + recurrenceID.UTC().Format(time.RFC3339)
+	if first, dup := im.seen[key]; dup {
+		return failImportRow(result, fmt.Errorf("occurrence already overridden in row %d", first))
+	}
+	im.seen[key] = row.row
+
+	master, ok := im.masters[row.externalID]
+	if !ok {
+		existing, err := im.service.repo.FindEventByExternalID(ctx, im.opts.OrganizerID, row.externalID)
+		if err != nil {
+			return failImportRow(result, fmt.Errorf("series %q not found: %w", row.externalID, err))
+		}
+		master = existing
+	}
+	if err := im.service.checkOccurrence(master, recurrenceID); err != nil {
+		return failImportRow(result, err)
+	}
+
+	override := row.event
+	if err := validateEvent(override); err != nil {
+		return failImportRow(result, fmt.Errorf("%w: %w", apperrors.ErrValidation, err))
+	}
+
+	// A master prepared in a dry run has no stored overrides yet
+	if master.EventID != uuid.Nil {
+		existing, err := im.service.findOverride(ctx, master.EventID, recurrenceID)
+		if err != nil {
+			return failImportRow(result, err)
+		}
+		if existing != nil {
+			result.Action = ImportActionSkipped
+			result.EventID = &existing.EventID
+			return result
+		}
+	}
+
+	if !im.opts.DryRun {
+		created, err := im.service.OverrideOccurrence(ctx, im.opts.OrganizerID, master.EventID, recurrenceID, override)
+		if err != nil {
+			return failImportRow(result, err)
+		}
+		result.EventID = &created.EventID
+	}
+	result.Action = ImportActionCreated
+	return result
+}
+
+func newImportRowResult(row *importRow) ImportRowResult {
+	result := ImportRowResult{Row: row.row, Line: row.line, ExternalID: row.externalID}
+	if row.event != nil {
+		result.Name = row.event.Name
+	}
+	return result
+}
+
+func failImportRow(result ImportRowResult, err error) ImportRowResult {
+	result.Action = ImportActionFailed
+	result.Error = err.Error()
+	return result
+}
+
+// Helper function to turn the VEVENTs of an iCalendar file into import rows
+func decodeICSRows(r io.Reader, opts ImportOptions) ([]*importRow, error) {
+	calendar, err := icalendar.Decode(r)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]*importRow, 0, len(calendar.Events))
+	for i, vevent := range calendar.Events {
+		row := &importRow{row: i + 1, line: vevent.Line, externalID: strings.TrimSpace(vevent.UID), err: vevent.Err}
+		row.event = &types.EventType{
+			Name:              vevent.Summary,
+			Description:       vevent.Description,
+			Location:          vevent.Location,
+			Date:              vevent.Start,
+			Capacity:          opts.DefaultCapacity,
+			RecurrenceRule:    vevent.RRule,
+			RecurrenceExDates: vevent.ExDates,
+			AllDay:            vevent.AllDay,
+		}
+		switch vevent.Status {
+		case "CONFIRMED":
+			row.event.Status = types.EventStatusPublished
+		case "CANCELLED":
+			if row.err == nil {
+				row.err = errors.New("cancelled events are not imported")
+			}
+		}
+		if vevent.TimeZone != nil {
+			row.event.TimeZone = vevent.TimeZone.String()
+		}
+		if !vevent.End.IsZero() {
+			end := vevent.End
+			row.event.EndDate = &end
+		}
+		if vevent.RecurrenceID != nil {
+			if vevent.RRule != "" {
+				row.err = errors.New("an occurrence override cannot recur")
+			}
+			// The series is resolved from the UID once its master is known
+			row.event.RecurrenceID = vevent.RecurrenceID
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// Helper function to turn the records of a CSV file into import rows. Rows without
+// an external_id are keyed on a hash of their content.
+func decodeCSVRows(r io.Reader, opts ImportOptions) ([]*importRow, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil, errors.New("csv file is empty")
+		}
+		return nil, fmt.Errorf("failed to read csv header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if _, known := csvColumns[name]; !known {
+			return nil, fmt.Errorf("unknown csv column %q", name)
+		}
+		if _, dup := columns[name]; dup {
+			return nil, fmt.Errorf("duplicate csv column %q", name)
+		}
+		columns[name] = i
+	}
+	for name, required := range csvColumns {
+		if _, present := columns[name]; required && !present {
+			return nil, fmt.Errorf("missing csv column %q", name)
+		}
+	}
+
+	var rows []*importRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		line, _ := reader.FieldPos(0)
+		row := &importRow{row: len(rows) + 1, line: line}
+		rows = append(rows, row)
+		if len(rows) > maxImportRows {
+			return nil, ErrTooManyImportRows
+		}
+
+		if err != nil {
+			// A wrong number of fields only spoils this record, anything else the whole file
+			if !errors.Is(err, csv.ErrFieldCount) {
+				return nil, fmt.Errorf("failed to read csv: %w", err)
+			}
+			row.err = fmt.Errorf("expected %d fields, got %d", len(header), len(record))
+			continue
+		}
+
+		field := func(name string) string {
+			if i, ok := columns[name]; ok {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		row.event = &types.EventType{
+			Name:           field("name"),
+			Description:    field("description"),
+			Location:       field("location"),
+			Capacity:       opts.DefaultCapacity,
+			RecurrenceRule: field("recurrence_rule"),
+			Status:         strings.ToLower(field("status")),
+		}
+		row.externalID = field("external_id")
+		if row.externalID == "" {
+			row.externalID = csvRowKey(record)
+		}
+
+		row.event.TimeZone = field("time_zone")
+		if allDay := field("all_day"); allDay != "" {
+			if row.event.AllDay, err = strconv.ParseBool(allDay); err != nil {
+				row.err = fmt.Errorf("invalid all_day %q", allDay)
+				continue
+			}
+		}
+		loc := time.UTC
+		if row.event.TimeZone != "" {
+			if loc, err = loadZone(row.event.TimeZone); err != nil {
+				row.err = err
+				continue
+			}
+		}
+		if row.event.Date, err = parseCSVTime(field("date"), loc); err != nil {
+			row.err = fmt.Errorf("invalid date: %w", err)
+			continue
+		}
+		if endDate := field("end_date"); endDate != "" {
+			end, err := parseCSVTime(endDate, loc)
+			if err != nil {
+				row.err = fmt.Errorf("invalid end_date: %w", err)
+				continue
+			}
+			row.event.EndDate = &end
+		}
+		if capacity := field("capacity"); capacity != "" {
+			if row.event.Capacity, err = strconv.Atoi(capacity); err != nil {
+				row.err = fmt.Errorf("invalid capacity %q", capacity)
+				continue
+			}
+		}
+	}
+	return rows, nil
+}
+
+// csvTimeLayouts are the accepted CSV times besides RFC 3339, read as wall-clock time
+var csvTimeLayouts = []string{"2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02 15:04", time.DateOnly}
+
+// parseCSVTime reads an RFC 3339 time or a local time or date in loc
+func parseCSVTime(value string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	for _, layout := range csvTimeLayouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%q is not an RFC 3339 time, a local time or a date", value)
+}
+
+// csvRowKey derives a stable import key from the content of a CSV record
+func csvRowKey(record []string) string {
+	hash := sha256.New()
+	for _, field := range record {
+		hash.Write([]byte(strings.TrimSpace(field)))
+		hash.Write([]byte{0})
+	}
+	return "csv:" + hex.EncodeToString(hash.Sum(nil))[:32]
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lordofthemind/EventifyGo/internals/apperrors"
+	"github.com/lordofthemind/EventifyGo/internals/types"
+)
+
+// eventStatusTransitions lists the statuses each status can move to. Cancelled and
+// completed events are final.
+var eventStatusTransitions = map[string][]string{
+	types.EventStatusDraft:     {types.EventStatusPublished, types.EventStatusCancelled},
+	types.EventStatusPublished: {types.EventStatusCancelled, types.EventStatusCompleted},
+}
+
+// Move an Event to another lifecycle status on behalf of its organizer or a holder of
+// events:write. Cancelling keeps the record, its attendees and its waitlist. A series carries
+// its occurrence overrides along.
+func (s *EventService) ChangeEventStatus(ctx context.Context, actorID, eventID uuid.UUID, status string) (*types.EventType, error) {
+	existing, err := s.getEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeEventChange(ctx, actorID, existing); err != nil {
+		return nil, err
+	}
+	if existing.IsOccurrenceOverride() {
+		return nil, fmt.Errorf("%w: an occurrence follows the status of its series", apperrors.ErrValidation)
+	}
+
+	switch status {
+	case types.EventStatusDraft, types.EventStatusPublished, types.EventStatusCancelled, types.EventStatusCompleted:
+	default:
+		return nil, fmt.Errorf("%w: unknown status %q", apperrors.ErrValidation, status)
+	}
+
+	current := existing.LifecycleStatus()
+	if status == current {
+		return existing, nil
+	}
+	if !canTransition(current, status) {
+		return nil, fmt.Errorf("%w: %s to %s", ErrInvalidStatusTransition, current, status)
+	}
+	if status == types.EventStatusCompleted {
+		if err := checkEventOver(existing); err != nil {
+			return nil, err
+		}
+	}
+
+	// Work on a copy, the in-memory repository hands out its own records
+	updated := *existing
+	updated.Status = status
+	updated.UpdatedAt = time.Now()
+	if err := s.repo.UpdateEvent(ctx, &updated); err != nil {
+		return nil, fmt.Errorf("failed to change event status: %w", err)
+	}
+
+	if updated.IsRecurring() {
+		overrides, err := s.repo.ListSeriesOverrides(ctx, eventID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list occurrence overrides: %w", err)
+		}
+		for _, override := range overrides {
+			changed := *override
+			changed.Status = status
+			changed.UpdatedAt = updated.UpdatedAt
+			if err := s.repo.UpdateEvent(ctx, &changed); err != nil {
+				return nil, fmt.Errorf("failed to change occurrence status: %w", err)
+			}
+		}
+	}
+
+	return &updated, nil
+}
+
+// Helper function to check a status transition against eventStatusTransitions
+func canTransition(from, to string) bool {
+	for _, allowed := range eventStatusTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Helper function to make sure an Event, or every occurrence of a series, is over
+func checkEventOver(event *types.EventType) error {
+	now := time.Now()
+	if event.IsRecurring() {
+		set, err := recurrenceSetFrom(event, now)
+		if err != nil {
+			return err
+		}
+		if next := set.After(now, true); !next.IsZero() {
+			return fmt.Errorf("%w: the series still has upcoming occurrences", apperrors.ErrValidation)
+		}
+		return nil
+	}
+	if event.End().After(now) {
+		return fmt.Errorf("%w: an event can only be completed once it is over", apperrors.ErrValidation)
+	}
+	return nil
+}
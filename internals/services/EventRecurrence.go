@@ -0,0 +1,166 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lordofthemind/EventifyGo/internals/apperrors"
+	"github.com/lordofthemind/EventifyGo/internals/types"
+	"github.com/teambition/rrule-go"
+)
+
+// maxOccurrenceSteps bounds how many occurrences of a single series are walked while
+// expanding a window, a window needing more is rejected instead of being cut short
+const maxOccurrenceSteps = 100000
+
+// ErrTooManyOccurrences is returned when a window holds more occurrences than can be expanded
+var ErrTooManyOccurrences = fmt.Errorf("%w: the time window holds too many occurrences, narrow it down", apperrors.ErrValidation)
+
+// normalizeRecurrenceRule strips an optional "RRULE:" prefix and checks that the rule parses
+func normalizeRecurrenceRule(rule string) (string, error) {
+	rule = strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(rule)), "RRULE:")
+	if rule == "" {
+		return "", nil
+	}
+	if strings.Contains(rule, "\n") || strings.Contains(rule, "DTSTART") {
+		return "", errors.New("recurrence_rule must not contain DTSTART, the event date is used instead")
+	}
+
+	option, err := rrule.StrToROption(rule)
+	if err != nil {
+		return "", fmt.Errorf("invalid recurrence_rule: %w", err)
+	}
+	if option.Freq == rrule.SECONDLY || option.Freq == rrule.MINUTELY {
+		return "", errors.New("recurrence_rule frequency must be HOURLY or longer")
+	}
+	return rule, nil
+}
+
+// recurrenceSetFrom builds the RFC 5545 recurrence set of a series master for occurrences
+// starting at notBefore or later. Walking a set always starts at its DTSTART, so a long running
+// series is restarted close to notBefore when its rule allows it. Earlier occurrences may
+// be missing from the returned set.
+func recurrenceSetFrom(master *types.EventType, notBefore time.Time) (*rrule.Set, error) {
+	option, err := rrule.StrToROption(master.RecurrenceRule)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recurrence_rule: %w", err)
+	}
+	// rrule-go works with second precision and expands in the zone of DTSTART,
+	// which keeps the wall-clock time of occurrences across DST changes
+	option.Dtstart = master.Date.In(master.Zone()).Truncate(time.Second)
+	option.Dtstart = rebaseStart(option, notBefore)
+
+	rule, err := rrule.NewRRule(*option)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recurrence_rule: %w", err)
+	}
+
+	set := &rrule.Set{}
+	set.RRule(rule)
+	for _, exdate := range master.RecurrenceExDates {
+		set.ExDate(exdate.Truncate(time.Second))
+	}
+	return set, nil
+}
+
+// rebaseStart moves the DTSTART of an HOURLY or DAILY rule forward by whole intervals to
+// just before notBefore. These rules repeat every interval on the wall clock, so the moved
+// rule yields the same occurrences from there on. A COUNT numbers occurrences from the
+// original DTSTART and other frequencies do not repeat evenly, those rules are left alone.
+func rebaseStart(option *rrule.ROption, notBefore time.Time) time.Time {
+	start := option.Dtstart
+	if option.Count > 0 || !notBefore.After(start) {
+		return start
+	}
+	interval := option.Interval
+	if interval < 1 {
+		interval = 1
+	}
+	var period time.Duration
+	switch option.Freq {
+	case rrule.HOURLY:
+		period = time.Duration(interval) * time.Hour
+	case rrule.DAILY:
+		period = time.Duration(interval) * 24 * time.Hour
+	default:
+		return start
+	}
+
+	// Count in wall-clock time like rrule-go, one interval is kept as slack for DST changes
+	loc := start.Location()
+	wall := wallClock(start)
+	periods := int64(wallClock(notBefore.In(loc)).Sub(wall)/period) - 1
+	for ; periods > 0; periods-- {
+		moved := wall.Add(time.Duration(periods) * period)
+		rebased := time.Date(moved.Year(), moved.Month(), moved.Day(), moved.Hour(), moved.Minute(), moved.Second(), 0, loc)
+		// A start skipped by a DST change would shift the hour the rule defaults to
+		if wallClock(rebased).Equal(moved) {
+			return rebased
+		}
+	}
+	return start
+}
+
+// wallClock returns the wall-clock reading of t as a UTC time
+func wallClock(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.UTC)
+}
+
+// occurrenceKey identifies an occurrence independently of storage precision and location
+func occurrenceKey(t time.Time) int64 {
+	return t.Truncate(time.Second).Unix()
+}
+
+// isOccurrence reports whether start is one of the scheduled occurrences of a series
+func isOccurrence(master *types.EventType, start time.Time) (bool, error) {
+	set, err := recurrenceSetFrom(master, start)
+	if err != nil {
+		return false, err
+	}
+	start = start.Truncate(time.Second)
+	for _, occurrence := range set.Between(start, start, true) {
+		if occurrenceKey(occurrence) == occurrenceKey(start) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// expandOccurrences returns the occurrences of a series master that overlap [from, to),
+// skipping the ones listed in overridden
+func expandOccurrences(master *types.EventType, from, to time.Time, overridden map[int64]bool) ([]*types.EventType, error) {
+	// Occurrences starting up to one event length before the window still overlap it
+	set, err := recurrenceSetFrom(master, from.Add(-master.End().Sub(master.Date)))
+	if err != nil {
+		return nil, err
+	}
+
+	var occurrences []*types.EventType
+	next := set.Iterator()
+	for step := 0; ; step++ {
+		start, ok := next()
+		if !ok || !start.Before(to) {
+			break
+		}
+		if step == maxOccurrenceSteps {
+			return nil, ErrTooManyOccurrences
+		}
+		if overridden[occurrenceKey(start)] {
+			continue
+		}
+
+		occurrence := *master
+		occurrence.Date = start
+		occurrence.EndDate = occurrenceEnd(master, start)
+		if !overlaps(occurrence.Date, occurrence.End(), from, to) {
+			continue
+		}
+		occurrence.SeriesID = &master.EventID
+		recurrenceID := start
+		occurrence.RecurrenceID = &recurrenceID
+		occurrences = append(occurrences, &occurrence)
+	}
+	return occurrences, nil
+}
@@ -0,0 +1,217 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lordofthemind/EventifyGo/internals/types"
+)
+
+func TestNormalizeRecurrenceRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    string
+		want    string
+		wantErr bool
+	}{
+		{"empty", "  ", "", false},
+		{"prefix and case", "rrule:freq=daily;count=3", "FREQ=DAILY;COUNT=3", false},
+		{"weekly", "FREQ=WEEKLY;BYDAY=MO,WE", "FREQ=WEEKLY;BYDAY=MO,WE", false},
+		{"dtstart", "DTSTART:20240101T090000Z\nRRULE:FREQ=DAILY", "", true},
+		{"minutely", "FREQ=MINUTELY", "", true},
+		{"invalid", "FREQ=SOMETIMES", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeRecurrenceRule(tt.rule)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("normalizeRecurrenceRule(%q) error = %v, wantErr %v", tt.rule, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("normalizeRecurrenceRule(%q) = %q, want %q", tt.rule, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpandOccurrencesAcrossDST(t *testing.T) {
+	berlin := loadLocation(t, "Europe/Berlin")
+	newYork := loadLocation(t, "America/New_York")
+
+	tests := []struct {
+		name       string
+		loc        *time.Location
+		start      time.Time
+		rule       string
+		exdates    []time.Time
+		from, to   time.Time
+		overridden []time.Time
+		want       []time.Time
+	}{
+		{
+			// Berlin moves from UTC+1 to UTC+2 on 2024-03-31
+			name:    "daily into summer time with EXDATE",
+			loc:     berlin,
+			start:   time.Date(2024, 3, 29, 9, 0, 0, 0, berlin),
+			rule:    "FREQ=DAILY",
+			exdates: []time.Time{time.Date(2024, 3, 31, 9, 0, 0, 0, berlin)},
+			from:    time.Date(2024, 3, 29, 0, 0, 0, 0, berlin),
+			to:      time.Date(2024, 4, 2, 0, 0, 0, 0, berlin),
+			want: []time.Time{
+				time.Date(2024, 3, 29, 8, 0, 0, 0, time.UTC),
+				time.Date(2024, 3, 30, 8, 0, 0, 0, time.UTC),
+				time.Date(2024, 4, 1, 7, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			// New York moves from UTC-4 to UTC-5 on 2024-11-03
+			name:  "weekly out of summer time",
+			loc:   newYork,
+			start: time.Date(2024, 10, 20, 18, 30, 0, 0, newYork),
+			rule:  "FREQ=WEEKLY;COUNT=4",
+			from:  time.Date(2024, 10, 1, 0, 0, 0, 0, newYork),
+			to:    time.Date(2024, 12, 1, 0, 0, 0, 0, newYork),
+			want: []time.Time{
+				time.Date(2024, 10, 20, 22, 30, 0, 0, time.UTC),
+				time.Date(2024, 10, 27, 22, 30, 0, 0, time.UTC),
+				time.Date(2024, 11, 3, 23, 30, 0, 0, time.UTC),
+				time.Date(2024, 11, 10, 23, 30, 0, 0, time.UTC),
+			},
+		},
+		{
+			// The window is years after DTSTART, so the start is rebased across many DST changes
+			name:    "rebased daily series with EXDATE",
+			loc:     berlin,
+			start:   time.Date(2020, 1, 6, 9, 0, 0, 0, berlin),
+			rule:    "FREQ=DAILY",
+			exdates: []time.Time{time.Date(2024, 10, 27, 9, 0, 0, 0, berlin)},
+			from:    time.Date(2024, 10, 26, 0, 0, 0, 0, berlin),
+			to:      time.Date(2024, 10, 29, 0, 0, 0, 0, berlin),
+			want: []time.Time{
+				time.Date(2024, 10, 26, 7, 0, 0, 0, time.UTC),
+				time.Date(2024, 10, 28, 8, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:       "overridden occurrence is skipped",
+			loc:        berlin,
+			start:      time.Date(2024, 3, 29, 9, 0, 0, 0, berlin),
+			rule:       "FREQ=DAILY;COUNT=3",
+			from:       time.Date(2024, 3, 29, 0, 0, 0, 0, berlin),
+			to:         time.Date(2024, 4, 5, 0, 0, 0, 0, berlin),
+			overridden: []time.Time{time.Date(2024, 3, 30, 9, 0, 0, 0, berlin)},
+			want: []time.Time{
+				time.Date(2024, 3, 29, 8, 0, 0, 0, time.UTC),
+				time.Date(2024, 3, 31, 7, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			end := tt.start.Add(time.Hour)
+			master := &types.EventType{
+				EventID:           uuid.New(),
+				Date:              tt.start.UTC(),
+				EndDate:           &end,
+				TimeZone:          tt.loc.String(),
+				RecurrenceRule:    tt.rule,
+				RecurrenceExDates: tt.exdates,
+			}
+			overridden := make(map[int64]bool)
+			for _, start := range tt.overridden {
+				overridden[occurrenceKey(start)] = true
+			}
+
+			occurrences, err := expandOccurrences(master, tt.from, tt.to, overridden)
+			if err != nil {
+				t.Fatalf("expandOccurrences returned error: %v", err)
+			}
+			if len(occurrences) != len(tt.want) {
+				t.Fatalf("got %d occurrences %v, want %d", len(occurrences), occurrenceStarts(occurrences), len(tt.want))
+			}
+			for i, occurrence := range occurrences {
+				if !occurrence.Date.Equal(tt.want[i]) {
+					t.Errorf("occurrence %d starts at %s, want %s", i, occurrence.Date.UTC(), tt.want[i])
+				}
+				if got := occurrence.End().Sub(occurrence.Date); got != time.Hour {
+					t.Errorf("occurrence %d lasts %s, want 1h", i, got)
+				}
+				if occurrence.SeriesID == nil || *occurrence.SeriesID != master.EventID {
+					t.Errorf("occurrence %d does not point at its master", i)
+				}
+				if occurrence.RecurrenceID == nil || !occurrence.RecurrenceID.Equal(occurrence.Date) {
+					t.Errorf("occurrence %d has RECURRENCE-ID %v, want its start", i, occurrence.RecurrenceID)
+				}
+			}
+		})
+	}
+}
+
+func TestIsOccurrence(t *testing.T) {
+	berlin := loadLocation(t, "Europe/Berlin")
+	master := &types.EventType{
+		Date:              time.Date(2024, 3, 29, 9, 0, 0, 0, berlin),
+		TimeZone:          berlin.String(),
+		RecurrenceRule:    "FREQ=DAILY",
+		RecurrenceExDates: []time.Time{time.Date(2024, 3, 31, 9, 0, 0, 0, berlin)},
+	}
+
+	tests := []struct {
+		name  string
+		start time.Time
+		want  bool
+	}{
+		{"first occurrence", time.Date(2024, 3, 29, 9, 0, 0, 0, berlin), true},
+		{"after the DST change", time.Date(2024, 4, 1, 9, 0, 0, 0, berlin), true},
+		{"same instant in UTC", time.Date(2024, 4, 1, 7, 0, 0, 0, time.UTC), true},
+		{"excluded date", time.Date(2024, 3, 31, 9, 0, 0, 0, berlin), false},
+		{"wrong hour after the DST change", time.Date(2024, 4, 1, 8, 0, 0, 0, time.UTC), false},
+		{"before DTSTART", time.Date(2024, 3, 28, 9, 0, 0, 0, berlin), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := isOccurrence(master, tt.start)
+			if err != nil {
+				t.Fatalf("isOccurrence returned error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("isOccurrence(%s) = %v, want %v", tt.start, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpandOccurrencesTooMany(t *testing.T) {
+	master := &types.EventType{
+		Date:           time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		RecurrenceRule: "FREQ=HOURLY",
+	}
+	from := master.Date
+	to := from.Add((maxOccurrenceSteps + 1) * time.Hour)
+	if _, err := expandOccurrences(master, from, to, nil); err != ErrTooManyOccurrences {
+		t.Errorf("expandOccurrences error = %v, want %v", err, ErrTooManyOccurrences)
+	}
+}
+
+// Helper function to load a time zone or skip when the zone database is missing
+func loadLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("time zone %s not available: %v", name, err)
+	}
+	return loc
+}
+
+// Helper function to list occurrence starts in failure messages
+func occurrenceStarts(occurrences []*types.EventType) []time.Time {
+	starts := make([]time.Time, 0, len(occurrences))
+	for _, occurrence := range occurrences {
+		starts = append(starts, occurrence.Date.UTC())
+	}
+	return starts
+}
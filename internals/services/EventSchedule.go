@@ -0,0 +1,95 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lordofthemind/EventifyGo/internals/types"
+)
+
+// prepareSchedule resolves the time zone of an Event, falling back to defaultZone and then
+// UTC, checks its end and snaps all-day events to midnight in their zone
+func prepareSchedule(event *types.EventType, defaultZone string) error {
+	if event.TimeZone == "" {
+		event.TimeZone = defaultZone
+	}
+	if event.TimeZone == "" {
+		event.TimeZone = "UTC"
+	}
+	loc, err := loadZone(event.TimeZone)
+	if err != nil {
+		return err
+	}
+
+	if event.AllDay {
+		// The calendar day is taken as written, whatever offset came with it
+		event.Date = startOfDay(event.Date, loc)
+		if event.EndDate == nil {
+			end := event.Date.AddDate(0, 0, 1)
+			event.EndDate = &end
+		} else {
+			end := startOfDay(*event.EndDate, loc)
+			event.EndDate = &end
+		}
+	}
+
+	if event.EndDate != nil && !event.EndDate.After(event.Date) {
+		return errors.New("end_date must be after date")
+	}
+	return nil
+}
+
+// loadZone loads an IANA time zone. "Local" is refused as it depends on the server.
+func loadZone(name string) (*time.Location, error) {
+	if name == "Local" {
+		return nil, fmt.Errorf("unknown time_zone %q", name)
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown time_zone %q", name)
+	}
+	return loc, nil
+}
+
+// startOfDay returns midnight in loc of the calendar day t shows in its own location.
+// time.Date takes care of days that do not start at 00:00 because of DST.
+func startOfDay(t time.Time, loc *time.Location) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, loc)
+}
+
+// upcomingCutoff is the instant an Event has to be scheduled after. All-day events
+// can still be planned on their own day.
+func upcomingCutoff(event *types.EventType) time.Time {
+	if event.AllDay {
+		return event.End()
+	}
+	return event.Date
+}
+
+// occurrenceEnd returns the end of the occurrence of a series starting at start. All-day
+// series keep their length in days, so occurrences stay aligned on midnight across DST.
+func occurrenceEnd(master *types.EventType, start time.Time) *time.Time {
+	if master.EndDate == nil {
+		return nil
+	}
+	var end time.Time
+	if master.AllDay {
+		loc := master.Zone()
+		first := master.Date.In(loc)
+		last := master.EndDate.In(loc)
+		days := int(time.Date(last.Year(), last.Month(), last.Day(), 0, 0, 0, 0, time.UTC).
+			Sub(time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, time.UTC)).Hours() / 24)
+		end = start.In(loc).AddDate(0, 0, days)
+	} else {
+		end = start.Add(master.EndDate.Sub(master.Date))
+	}
+	return &end
+}
+
+// overlaps reports whether an event running from start to end touches [from, to).
+// Events without a length count when they start inside the window.
+func overlaps(start, end, from, to time.Time) bool {
+	return start.Before(to) && (!start.Before(from) || end.After(from))
+}
@@ -0,0 +1,779 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lordofthemind/EventifyGo/internals/apperrors"
+	"github.com/lordofthemind/EventifyGo/internals/rbac"
+	"github.com/lordofthemind/EventifyGo/internals/repositories"
+	"github.com/lordofthemind/EventifyGo/internals/types"
+)
+
+// Errors returned by the event service, shared with the repositories
+var (
+	ErrEventNotFound     = repositories.ErrEventNotFound
+	ErrEventFull         = repositories.ErrEventFull
+	ErrAlreadyRegistered = repositories.ErrAlreadyRegistered
+	ErrNotRegistered     = repositories.ErrNotRegistered
+	ErrAlreadyWaitlisted = repositories.ErrAlreadyWaitlisted
+	ErrNotWaitlisted     = repositories.ErrNotWaitlisted
+
+	ErrDuplicateExternalID = apperrors.Conflict("an event with this external id already exists")
+
+	ErrInvalidStatusTransition = apperrors.Conflict("invalid event status transition")
+	ErrEventNotOpen            = apperrors.Conflict("event is not open for registration")
+	ErrEventClosed             = apperrors.Conflict("event is cancelled or completed")
+
+	ErrEventAccessDenied    = apperrors.Forbidden("only the organizer can change this event")
+	ErrWaitlistAccessDenied = apperrors.Forbidden("only the organizer can see the waitlist of this event")
+)
+
+// Registration statuses reported by RegisterAttendee
+const (
+	RegistrationStatusRegistered = "registered"
+	RegistrationStatusWaitlisted = "waitlisted"
+)
+
+// RegistrationResult tells whether an attendee got a seat or a place on the waitlist
+type RegistrationResult struct {
+	Status   string `json:"status"`
+	Position int    `json:"position,omitempty"`
+}
+
+type EventService struct {
+	repo          repositories.EventRepositoryInterface
+	superUserRepo repositories.SuperUserRepositoryInterface
+	waitlistRepo  repositories.WaitlistRepositoryInterface
+}
+
+func NewEventService(repo repositories.EventRepositoryInterface, superUserRepo repositories.SuperUserRepositoryInterface, waitlistRepo repositories.WaitlistRepositoryInterface) EventServiceInterface {
+	return &EventService{repo: repo, superUserRepo: superUserRepo, waitlistRepo: waitlistRepo}
+}
+
+// Create a new Event, the organizer is the acting SuperUser whatever the input says
+func (s *EventService) CreateEvent(ctx context.Context, organizerID uuid.UUID, event *types.EventType) (*types.EventType, error) {
+	event.OrganizerID = organizerID
+	if err := prepareNewEvent(event); err != nil {
+		return nil, err
+	}
+
+	// Make sure the organizer is a known SuperUser
+	if err := s.checkOrganizer(ctx, event.OrganizerID); err != nil {
+		return nil, err
+	}
+
+	// An external id can only be used once per organizer
+	if event.ExternalID != "" {
+		_, err := s.repo.FindEventByExternalID(ctx, event.OrganizerID, event.ExternalID)
+		if err == nil {
+			return nil, ErrDuplicateExternalID
+		}
+		if !errors.Is(err, ErrEventNotFound) {
+			return nil, fmt.Errorf("failed to check external id: %w", err)
+		}
+	}
+
+	if err := s.insertEvent(ctx, event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// Helper function to validate and normalize an Event before it is created
+func prepareNewEvent(event *types.EventType) error {
+	if err := validateEvent(event); err != nil {
+		return fmt.Errorf("%w: %w", apperrors.ErrValidation, err)
+	}
+	if err := prepareSchedule(event, ""); err != nil {
+		return fmt.Errorf("%w: %w", apperrors.ErrValidation, err)
+	}
+	if !upcomingCutoff(event).After(time.Now()) {
+		return fmt.Errorf("%w: event date must be in the future", apperrors.ErrValidation)
+	}
+	if err := prepareRecurrence(event); err != nil {
+		return fmt.Errorf("%w: %w", apperrors.ErrValidation, err)
+	}
+	// New events start as drafts unless they are published right away
+	switch event.Status {
+	case "":
+		event.Status = types.EventStatusDraft
+	case types.EventStatusDraft, types.EventStatusPublished:
+	default:
+		return fmt.Errorf("%w: a new event must be %s or %s", apperrors.ErrValidation, types.EventStatusDraft, types.EventStatusPublished)
+	}
+	// Overrides of single occurrences are only created through OverrideOccurrence
+	event.SeriesID = nil
+	event.RecurrenceID = nil
+	event.ExternalID = strings.TrimSpace(event.ExternalID)
+	return nil
+}
+
+// Helper function to store a prepared Event with its default values
+func (s *EventService) insertEvent(ctx context.Context, event *types.EventType) error {
+	event.EventID = uuid.New()
+	event.CreatedAt = time.Now()
+	event.UpdatedAt = time.Now()
+	if event.Attendees == nil {
+		event.Attendees = []uuid.UUID{}
+	}
+
+	if err := s.repo.CreateEvent(ctx, event); err != nil {
+		return fmt.Errorf("failed to create event: %w", err)
+	}
+	return nil
+}
+
+// Get Event by ID, an Event the viewer may not see is reported as not found
+func (s *EventService) GetEventByID(ctx context.Context, viewerID, eventID uuid.UUID) (*types.EventType, error) {
+	event, err := s.getEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if !event.IsVisibleTo(viewerID) {
+		return nil, fmt.Errorf("failed to get event: %w", ErrEventNotFound)
+	}
+	return event, nil
+}
+
+// Helper function to load an Event whatever its status
+func (s *EventService) getEvent(ctx context.Context, eventID uuid.UUID) (*types.EventType, error) {
+	event, err := s.repo.GetEventByID(ctx, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get event: %w", err)
+	}
+	return event, nil
+}
+
+// Update an existing Event
+func (s *EventService) UpdateEvent(ctx context.Context, actorID uuid.UUID, event *types.EventType) (*types.EventType, error) {
+	existing, err := s.getEvent(ctx, event.EventID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeEventChange(ctx, actorID, existing); err != nil {
+		return nil, err
+	}
+	if existing.IsClosed() {
+		return nil, ErrEventClosed
+	}
+
+	if err := validateEvent(event); err != nil {
+		return nil, fmt.Errorf("%w: %w", apperrors.ErrValidation, err)
+	}
+	// The time zone is kept unless a new one is given
+	if err := prepareSchedule(event, existing.TimeZone); err != nil {
+		return nil, fmt.Errorf("%w: %w", apperrors.ErrValidation, err)
+	}
+	// Only a rescheduled event has to move into the future
+	if !event.Date.Equal(existing.Date) && !upcomingCutoff(event).After(time.Now()) {
+		return nil, fmt.Errorf("%w: event date must be in the future", apperrors.ErrValidation)
+	}
+	// Attendees are not replaced through an update, so the new capacity has to fit them
+	if event.Capacity < len(existing.Attendees) {
+		return nil, fmt.Errorf("%w: capacity %d is below the %d registered attendees", apperrors.ErrValidation, event.Capacity, len(existing.Attendees))
+	}
+
+	// An override stays attached to the occurrence it replaces
+	event.SeriesID = existing.SeriesID
+	event.RecurrenceID = existing.RecurrenceID
+	if existing.IsOccurrenceOverride() && event.RecurrenceRule != "" {
+		return nil, fmt.Errorf("%w: an occurrence override cannot recur", apperrors.ErrValidation)
+	}
+	if err := prepareRecurrence(event); err != nil {
+		return nil, fmt.Errorf("%w: %w", apperrors.ErrValidation, err)
+	}
+	// Cancelled occurrences survive an update that leaves the rule alone
+	if event.RecurrenceExDates == nil && event.RecurrenceRule == existing.RecurrenceRule {
+		event.RecurrenceExDates = existing.RecurrenceExDates
+	}
+
+	// The status only changes through ChangeEventStatus and the organizer never changes
+	event.Status = existing.Status
+	event.OrganizerID = existing.OrganizerID
+	event.Attendees = existing.Attendees
+	event.ExternalID = existing.ExternalID
+	event.CreatedAt = existing.CreatedAt
+	event.UpdatedAt = time.Now()
+
+	if err := s.repo.UpdateEvent(ctx, event); err != nil {
+		return nil, fmt.Errorf("failed to update event: %w", err)
+	}
+
+	// A larger capacity may have opened seats for the waitlist
+	if event.Capacity > existing.Capacity {
+		s.promoteFromWaitlist(ctx, event.EventID)
+	}
+
+	return event, nil
+}
+
+// Delete Event by ID, deleting a series master also deletes its overrides
+func (s *EventService) DeleteEvent(ctx context.Context, actorID, eventID uuid.UUID) error {
+	event, err := s.getEvent(ctx, eventID)
+	if err != nil {
+		return err
+	}
+	if err := s.authorizeEventChange(ctx, actorID, event); err != nil {
+		return err
+	}
+
+	if event.IsRecurring() {
+		overrides, err := s.repo.ListSeriesOverrides(ctx, eventID)
+		if err != nil {
+			return fmt.Errorf("failed to list occurrence overrides: %w", err)
+		}
+		for _, override := range overrides {
+			if err := s.deleteEventRecord(ctx, override.EventID); err != nil {
+				return err
+			}
+		}
+	}
+
+	return s.deleteEventRecord(ctx, eventID)
+}
+
+// Helper function to delete a single stored event and its waitlist
+func (s *EventService) deleteEventRecord(ctx context.Context, eventID uuid.UUID) error {
+	if err := s.repo.DeleteEvent(ctx, eventID); err != nil {
+		return fmt.Errorf("failed to delete event: %w", err)
+	}
+	if err := s.waitlistRepo.ClearWaitlist(ctx, eventID); err != nil {
+		return fmt.Errorf("failed to clear waitlist: %w", err)
+	}
+	return nil
+}
+
+// List the Events visible to a viewer with pagination and sorting. Others see published
+// Events only, organizers also see their own drafts, cancelled and completed Events.
+func (s *EventService) ListEvents(ctx context.Context, viewerID uuid.UUID, page, limit int, sortBy string) ([]*types.EventType, error) {
+	page, limit = normalizePagination(page, limit)
+	if _, err := repositories.EventSortKey(sortBy); err != nil {
+		return nil, err
+	}
+
+	events, err := s.repo.ListEvents(ctx, viewerID, page, limit, sortBy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list events: %w", err)
+	}
+	return events, nil
+}
+
+// Search Events with pagination and sorting
+func (s *EventService) SearchEvents(ctx context.Context, viewerID uuid.UUID, searchQuery string, page, limit int, sortBy string) ([]*types.EventType, error) {
+	page, limit = normalizePagination(page, limit)
+	if _, err := repositories.EventSortKey(sortBy); err != nil {
+		return nil, err
+	}
+
+	events, err := s.repo.SearchEvents(ctx, viewerID, searchQuery, page, limit, sortBy)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search events: %w", err)
+	}
+	return events, nil
+}
+
+// Count Events matching the search query
+func (s *EventService) CountEvents(ctx context.Context, viewerID uuid.UUID, searchQuery string) (int64, error) {
+	count, err := s.repo.CountEvents(ctx, viewerID, searchQuery)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count events: %w", err)
+	}
+	return count, nil
+}
+
+// List the occurrences of every Event matching the search query that overlap [from, to),
+// expanding recurring series and applying their overrides
+func (s *EventService) ListEventOccurrences(ctx context.Context, viewerID uuid.UUID, searchQuery string, from, to time.Time, page, limit int) ([]*types.EventType, error) {
+	if !from.Before(to) {
+		return nil, fmt.Errorf("%w: from must be before to", apperrors.ErrValidation)
+	}
+	page, limit = normalizePagination(page, limit)
+
+	events, err := s.repo.FindEventsInRange(ctx, viewerID, searchQuery, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find events: %w", err)
+	}
+
+	// Occurrences replaced by an override are skipped when their series is expanded
+	overridden := make(map[uuid.UUID]map[int64]bool)
+	for _, event := range events {
+		if event.IsOccurrenceOverride() {
+			if overridden[*event.SeriesID] == nil {
+				overridden[*event.SeriesID] = make(map[int64]bool)
+			}
+			overridden[*event.SeriesID][occurrenceKey(*event.RecurrenceID)] = true
+		}
+	}
+
+	occurrences := []*types.EventType{}
+	for _, event := range events {
+		switch {
+		case event.IsRecurring():
+			expanded, err := expandOccurrences(event, from, to, overridden[event.EventID])
+			if err != nil {
+				return nil, fmt.Errorf("failed to expand event %s: %w", event.EventID, err)
+			}
+			occurrences = append(occurrences, expanded...)
+		case overlaps(event.Date, event.End(), from, to):
+			occurrences = append(occurrences, event)
+		}
+	}
+
+	sort.SliceStable(occurrences, func(i, j int) bool {
+		return occurrences[i].Date.Before(occurrences[j].Date)
+	})
+
+	start := (page - 1) * limit
+	if start >= len(occurrences) {
+		return []*types.EventType{}, nil
+	}
+	end := start + limit
+	if end > len(occurrences) {
+		end = len(occurrences)
+	}
+	return occurrences[start:end], nil
+}
+
+// List the occurrences of a single recurring Event that overlap [from, to)
+func (s *EventService) ListSeriesOccurrences(ctx context.Context, viewerID, seriesID uuid.UUID, from, to time.Time) ([]*types.EventType, error) {
+	if !from.Before(to) {
+		return nil, fmt.Errorf("%w: from must be before to", apperrors.ErrValidation)
+	}
+
+	master, err := s.getSeriesMaster(ctx, seriesID)
+	if err != nil {
+		return nil, err
+	}
+	if !master.IsVisibleTo(viewerID) {
+		return nil, fmt.Errorf("failed to get event: %w", ErrEventNotFound)
+	}
+
+	overrides, err := s.repo.ListSeriesOverrides(ctx, seriesID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list occurrence overrides: %w", err)
+	}
+
+	overridden := make(map[int64]bool)
+	var occurrences []*types.EventType
+	for _, override := range overrides {
+		overridden[occurrenceKey(*override.RecurrenceID)] = true
+		if overlaps(override.Date, override.End(), from, to) {
+			occurrences = append(occurrences, override)
+		}
+	}
+
+	expanded, err := expandOccurrences(master, from, to, overridden)
+	if err != nil {
+		return nil, fmt.Errorf("failed to expand event %s: %w", seriesID, err)
+	}
+	occurrences = append(occurrences, expanded...)
+
+	sort.SliceStable(occurrences, func(i, j int) bool {
+		return occurrences[i].Date.Before(occurrences[j].Date)
+	})
+	return occurrences, nil
+}
+
+// Override a single occurrence of a recurring Event, identified by its original start
+func (s *EventService) OverrideOccurrence(ctx context.Context, actorID, seriesID uuid.UUID, recurrenceID time.Time, override *types.EventType) (*types.EventType, error) {
+	master, err := s.getSeriesMaster(ctx, seriesID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeEventChange(ctx, actorID, master); err != nil {
+		return nil, err
+	}
+	if master.IsClosed() {
+		return nil, ErrEventClosed
+	}
+	if err := s.checkOccurrence(master, recurrenceID); err != nil {
+		return nil, err
+	}
+
+	if err := validateEvent(override); err != nil {
+		return nil, fmt.Errorf("%w: %w", apperrors.ErrValidation, err)
+	}
+	if err := prepareSchedule(override, master.TimeZone); err != nil {
+		return nil, fmt.Errorf("%w: %w", apperrors.ErrValidation, err)
+	}
+
+	existing, err := s.findOverride(ctx, seriesID, recurrenceID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Nothing may point into or share slices with the master or override records, the
+	// in-memory repository hands out its own records
+	override.SeriesID = &seriesID
+	override.RecurrenceID = &recurrenceID
+	override.OrganizerID = master.OrganizerID
+	override.RecurrenceRule = ""
+	override.RecurrenceExDates = nil
+	override.ExternalID = ""
+	// An override shares the status of its series
+	override.Status = master.Status
+	override.UpdatedAt = time.Now()
+
+	if existing != nil {
+		if override.Capacity < len(existing.Attendees) {
+			return nil, fmt.Errorf("%w: capacity %d is below the %d registered attendees", apperrors.ErrValidation, override.Capacity, len(existing.Attendees))
+		}
+		override.EventID = existing.EventID
+		override.Attendees = append([]uuid.UUID{}, existing.Attendees...)
+		override.CreatedAt = existing.CreatedAt
+		if err := s.repo.UpdateEvent(ctx, override); err != nil {
+			return nil, fmt.Errorf("failed to update occurrence override: %w", err)
+		}
+		return override, nil
+	}
+
+	override.EventID = uuid.New()
+	override.Attendees = []uuid.UUID{}
+	override.CreatedAt = time.Now()
+	if err := s.repo.CreateEvent(ctx, override); err != nil {
+		return nil, fmt.Errorf("failed to create occurrence override: %w", err)
+	}
+	return override, nil
+}
+
+// Cancel a single occurrence of a recurring Event by adding it to the EXDATEs
+func (s *EventService) CancelOccurrence(ctx context.Context, actorID, seriesID uuid.UUID, recurrenceID time.Time) error {
+	master, err := s.getSeriesMaster(ctx, seriesID)
+	if err != nil {
+		return err
+	}
+	if err := s.authorizeEventChange(ctx, actorID, master); err != nil {
+		return err
+	}
+	if master.IsClosed() {
+		return ErrEventClosed
+	}
+	if err := s.checkOccurrence(master, recurrenceID); err != nil {
+		return err
+	}
+
+	existing, err := s.findOverride(ctx, seriesID, recurrenceID)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		if err := s.deleteEventRecord(ctx, existing.EventID); err != nil {
+			return err
+		}
+	}
+
+	// Work on a copy, the in-memory repository hands out its own records
+	updated := *master
+	updated.RecurrenceExDates = append(append([]time.Time{}, master.RecurrenceExDates...), recurrenceID)
+	updated.UpdatedAt = time.Now()
+	if err := s.repo.UpdateEvent(ctx, &updated); err != nil {
+		return fmt.Errorf("failed to cancel occurrence: %w", err)
+	}
+	return nil
+}
+
+// Helper function to load an Event that must be the master of a series
+func (s *EventService) getSeriesMaster(ctx context.Context, seriesID uuid.UUID) (*types.EventType, error) {
+	master, err := s.getEvent(ctx, seriesID)
+	if err != nil {
+		return nil, err
+	}
+	if !master.IsRecurring() {
+		return nil, fmt.Errorf("%w: event is not recurring", apperrors.ErrValidation)
+	}
+	return master, nil
+}
+
+// Helper function to make sure a start time is a scheduled occurrence of a series
+func (s *EventService) checkOccurrence(master *types.EventType, recurrenceID time.Time) error {
+	ok, err := isOccurrence(master, recurrenceID)
+	if err != nil {
+		return fmt.Errorf("failed to expand event %s: %w", master.EventID, err)
+	}
+	if !ok {
+		return fmt.Errorf("%w: no occurrence starts at %s", ErrEventNotFound, recurrenceID.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// Helper function to find the stored override of an occurrence, if there is one
+func (s *EventService) findOverride(ctx context.Context, seriesID uuid.UUID, recurrenceID time.Time) (*types.EventType, error) {
+	overrides, err := s.repo.ListSeriesOverrides(ctx, seriesID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list occurrence overrides: %w", err)
+	}
+	for _, override := range overrides {
+		if occurrenceKey(*override.RecurrenceID) == occurrenceKey(recurrenceID) {
+			return override, nil
+		}
+	}
+	return nil, nil
+}
+
+// Register an attendee for an Event, or put them on the waitlist when it is full
+func (s *EventService) RegisterAttendee(ctx context.Context, eventID, attendeeID uuid.UUID) (*RegistrationResult, error) {
+	event, err := s.getEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if !event.IsPublished() {
+		return nil, ErrEventNotOpen
+	}
+	if !event.Date.After(time.Now()) {
+		return nil, fmt.Errorf("%w: event has already taken place", apperrors.ErrValidation)
+	}
+
+	if _, err := s.superUserRepo.FindByID(ctx, attendeeID); err != nil {
+		return nil, fmt.Errorf("attendee not found: %w", err)
+	}
+
+	// Newcomers queue behind anyone already waiting so freed seats go to the waitlist first
+	_, err = s.waitlistRepo.FirstInWaitlist(ctx, eventID)
+	switch {
+	case errors.Is(err, repositories.ErrWaitlistEmpty):
+		// The repository enforces capacity atomically
+		err = s.repo.RegisterAttendee(ctx, eventID, attendeeID)
+		if err == nil {
+			return &RegistrationResult{Status: RegistrationStatusRegistered}, nil
+		}
+		if !errors.Is(err, repositories.ErrEventFull) {
+			return nil, fmt.Errorf("failed to register attendee: %w", err)
+		}
+	case err != nil:
+		return nil, fmt.Errorf("failed to read waitlist: %w", err)
+	}
+
+	if err := s.joinWaitlist(ctx, event, attendeeID); err != nil {
+		return nil, err
+	}
+
+	// A seat may have opened between the failed registration and joining the waitlist
+	s.promoteFromWaitlist(ctx, eventID)
+
+	entry, err := s.waitlistRepo.GetWaitlistEntry(ctx, eventID, attendeeID)
+	if errors.Is(err, repositories.ErrNotWaitlisted) {
+		return &RegistrationResult{Status: RegistrationStatusRegistered}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read waitlist position: %w", err)
+	}
+	return &RegistrationResult{Status: RegistrationStatusWaitlisted, Position: entry.Position}, nil
+}
+
+// Unregister an attendee from an Event and promote the next person on the waitlist. Only the
+// organizer or a holder of events:write may remove someone else.
+func (s *EventService) UnregisterAttendee(ctx context.Context, actorID, eventID, attendeeID uuid.UUID) error {
+	event, err := s.getEvent(ctx, eventID)
+	if err != nil {
+		return err
+	}
+	if actorID != attendeeID {
+		if err := s.authorizeEventChange(ctx, actorID, event); err != nil {
+			return err
+		}
+	}
+	// The attendee list of a cancelled or completed event is kept as it was
+	if event.IsClosed() {
+		return ErrEventClosed
+	}
+
+	if err := s.repo.UnregisterAttendee(ctx, eventID, attendeeID); err != nil {
+		return fmt.Errorf("failed to unregister attendee: %w", err)
+	}
+
+	s.promoteFromWaitlist(ctx, eventID)
+	return nil
+}
+
+// Get a user's position on an Event's waitlist, the organizer may look up anyone
+func (s *EventService) GetWaitlistPosition(ctx context.Context, actorID, eventID, userID uuid.UUID) (*types.WaitlistEntryType, error) {
+	if err := s.authorizeWaitlistAccess(ctx, actorID, eventID, userID); err != nil {
+		return nil, err
+	}
+
+	entry, err := s.waitlistRepo.GetWaitlistEntry(ctx, eventID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get waitlist position: %w", err)
+	}
+	return entry, nil
+}
+
+// List an Event's waitlist in order, it names who is waiting so only the organizer may see it
+func (s *EventService) ListWaitlist(ctx context.Context, actorID, eventID uuid.UUID) ([]*types.WaitlistEntryType, error) {
+	event, err := s.getEvent(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeEventChange(ctx, actorID, event); err != nil {
+		if errors.Is(err, ErrEventAccessDenied) {
+			return nil, ErrWaitlistAccessDenied
+		}
+		return nil, err
+	}
+
+	entries, err := s.waitlistRepo.ListWaitlist(ctx, eventID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list waitlist: %w", err)
+	}
+	return entries, nil
+}
+
+// Leave an Event's waitlist, the organizer may remove anyone
+func (s *EventService) LeaveWaitlist(ctx context.Context, actorID, eventID, userID uuid.UUID) error {
+	if err := s.authorizeWaitlistAccess(ctx, actorID, eventID, userID); err != nil {
+		return err
+	}
+
+	if err := s.waitlistRepo.RemoveFromWaitlist(ctx, eventID, userID); err != nil {
+		return fmt.Errorf("failed to leave waitlist: %w", err)
+	}
+	return nil
+}
+
+// Helper function to let users act on their own waitlist entry and the organizer or a
+// holder of events:write on anyone's
+func (s *EventService) authorizeWaitlistAccess(ctx context.Context, actorID, eventID, userID uuid.UUID) error {
+	if actorID != uuid.Nil && actorID == userID {
+		return nil
+	}
+	event, err := s.getEvent(ctx, eventID)
+	if err != nil {
+		return err
+	}
+	return s.authorizeEventChange(ctx, actorID, event)
+}
+
+// Helper function to put an attendee at the end of the waitlist
+func (s *EventService) joinWaitlist(ctx context.Context, event *types.EventType, attendeeID uuid.UUID) error {
+	for _, id := range event.Attendees {
+		if id == attendeeID {
+			return fmt.Errorf("failed to register attendee: %w", repositories.ErrAlreadyRegistered)
+		}
+	}
+
+	entry := &types.WaitlistEntryType{
+		ID:        uuid.New(),
+		EventID:   event.EventID,
+		UserID:    attendeeID,
+		CreatedAt: time.Now(),
+	}
+	if err := s.waitlistRepo.AddToWaitlist(ctx, entry); err != nil {
+		return fmt.Errorf("failed to join waitlist: %w", err)
+	}
+	return nil
+}
+
+// promoteFromWaitlist moves people from the head of the waitlist into free seats.
+// Entries are only removed after their registration succeeded, so concurrent
+// promotions can race without losing anyone.
+func (s *EventService) promoteFromWaitlist(ctx context.Context, eventID uuid.UUID) {
+	// Seats are only handed out while the event is published
+	event, err := s.getEvent(ctx, eventID)
+	if err != nil || !event.IsPublished() {
+		return
+	}
+
+	for {
+		entry, err := s.waitlistRepo.FirstInWaitlist(ctx, eventID)
+		if errors.Is(err, repositories.ErrWaitlistEmpty) {
+			return
+		}
+		if err != nil {
+			log.Printf("Failed to read waitlist for event %s: %v", eventID, err)
+			return
+		}
+
+		err = s.repo.RegisterAttendee(ctx, eventID, entry.UserID)
+		if errors.Is(err, repositories.ErrEventFull) {
+			return
+		}
+		if err != nil && !errors.Is(err, repositories.ErrAlreadyRegistered) {
+			log.Printf("Failed to promote %s from the waitlist of event %s: %v", entry.UserID, eventID, err)
+			return
+		}
+
+		err = s.waitlistRepo.RemoveFromWaitlist(ctx, eventID, entry.UserID)
+		if err != nil && !errors.Is(err, repositories.ErrNotWaitlisted) {
+			log.Printf("Failed to remove %s from the waitlist of event %s: %v", entry.UserID, eventID, err)
+			return
+		}
+	}
+}
+
+// Helper function to make sure an actor may change an Event, organizers may change their own
+// Events and SuperUsers holding events:write any Event
+func (s *EventService) authorizeEventChange(ctx context.Context, actorID uuid.UUID, event *types.EventType) error {
+	if actorID != uuid.Nil && actorID == event.OrganizerID {
+		return nil
+	}
+	actor, err := s.superUserRepo.FindByID(ctx, actorID)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return ErrEventAccessDenied
+		}
+		return fmt.Errorf("failed to get acting superuser: %w", err)
+	}
+	if !rbac.Has(actor.Role, actor.PermissionGroups, rbac.PermissionEventsWrite) {
+		return ErrEventAccessDenied
+	}
+	return nil
+}
+
+// Helper function to make sure the organizer exists
+func (s *EventService) checkOrganizer(ctx context.Context, organizerID uuid.UUID) error {
+	if organizerID == uuid.Nil {
+		return fmt.Errorf("%w: organizer_id is required", apperrors.ErrValidation)
+	}
+	if _, err := s.superUserRepo.FindByID(ctx, organizerID); err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return fmt.Errorf("%w: organizer %s does not exist", apperrors.ErrValidation, organizerID)
+		}
+		return fmt.Errorf("failed to check organizer: %w", err)
+	}
+	return nil
+}
+
+// Helper function to normalize and check the recurrence rule of an event
+func prepareRecurrence(event *types.EventType) error {
+	rule, err := normalizeRecurrenceRule(event.RecurrenceRule)
+	if err != nil {
+		return err
+	}
+	event.RecurrenceRule = rule
+	if rule == "" {
+		event.RecurrenceExDates = nil
+	}
+	return nil
+}
+
+// Helper function to validate event input
+func validateEvent(event *types.EventType) error {
+	if strings.TrimSpace(event.Name) == "" {
+		return errors.New("name is required")
+	}
+	if event.Date.IsZero() {
+		return errors.New("date is required")
+	}
+	if event.Capacity < 1 {
+		return errors.New("capacity must be at least 1")
+	}
+	return nil
+}
+
+// Helper function to fall back to sane pagination values
+func normalizePagination(page, limit int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+	return page, limit
+}
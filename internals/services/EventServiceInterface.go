@@ -0,0 +1,61 @@
+package services
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lordofthemind/EventifyGo/internals/types"
+)
+
+type EventServiceInterface interface {
+	// Create a new Event organized by the acting SuperUser
+	CreateEvent(ctx context.Context, organizerID uuid.UUID, event *types.EventType) (*types.EventType, error)
+
+	// Find an Event by its ID, unpublished Events are only found by their organizer
+	GetEventByID(ctx context.Context, viewerID, eventID uuid.UUID) (*types.EventType, error)
+
+	// Update and delete operations, the actor must be the organizer or hold events:write
+	UpdateEvent(ctx context.Context, actorID uuid.UUID, event *types.EventType) (*types.EventType, error)
+	DeleteEvent(ctx context.Context, actorID, eventID uuid.UUID) error
+
+	// List and search Events with pagination and sorting
+	ListEvents(ctx context.Context, viewerID uuid.UUID, page, limit int, sortBy string) ([]*types.EventType, error)
+	SearchEvents(ctx context.Context, viewerID uuid.UUID, searchQuery string, page, limit int, sortBy string) ([]*types.EventType, error)
+
+	// Count Events matching the search query
+	CountEvents(ctx context.Context, viewerID uuid.UUID, searchQuery string) (int64, error)
+
+	// Move an Event through its lifecycle: draft, published, cancelled, completed. The actor
+	// must be the organizer or hold events:write
+	ChangeEventStatus(ctx context.Context, actorID, eventID uuid.UUID, status string) (*types.EventType, error)
+
+	// Expand recurring Events into occurrences overlapping [from, to)
+	ListEventOccurrences(ctx context.Context, viewerID uuid.UUID, searchQuery string, from, to time.Time, page, limit int) ([]*types.EventType, error)
+	ListSeriesOccurrences(ctx context.Context, viewerID, seriesID uuid.UUID, from, to time.Time) ([]*types.EventType, error)
+
+	// Override or cancel a single occurrence of a recurring Event, the actor must be the
+	// organizer or hold events:write
+	OverrideOccurrence(ctx context.Context, actorID, seriesID uuid.UUID, recurrenceID time.Time, override *types.EventType) (*types.EventType, error)
+	CancelOccurrence(ctx context.Context, actorID, seriesID uuid.UUID, recurrenceID time.Time) error
+
+	// Export the Events a viewer may see as RFC 5545 iCalendar documents
+	ExportEventCalendar(ctx context.Context, viewerID, eventID uuid.UUID) ([]byte, error)
+	ExportSuperUserCalendar(ctx context.Context, viewerID, superUserID uuid.UUID) ([]byte, error)
+
+	// Import Events from an iCalendar or CSV file
+	ImportEvents(ctx context.Context, r io.Reader, opts ImportOptions) (*ImportResult, error)
+
+	// Manage attendees, capacity is enforced atomically by the repository and
+	// full events put new registrations on the waitlist. Attendees unregister themselves,
+	// removing someone else takes the organizer or events:write
+	RegisterAttendee(ctx context.Context, eventID, attendeeID uuid.UUID) (*RegistrationResult, error)
+	UnregisterAttendee(ctx context.Context, actorID, eventID, attendeeID uuid.UUID) error
+
+	// Manage the waitlist, users act on their own entry and the organizer on anyone's. Only
+	// the organizer or events:write may list the whole waitlist.
+	GetWaitlistPosition(ctx context.Context, actorID, eventID, userID uuid.UUID) (*types.WaitlistEntryType, error)
+	ListWaitlist(ctx context.Context, actorID, eventID uuid.UUID) ([]*types.WaitlistEntryType, error)
+	LeaveWaitlist(ctx context.Context, actorID, eventID, userID uuid.UUID) error
+}
@@ -0,0 +1,245 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lordofthemind/EventifyGo/internals/apperrors"
+	"github.com/lordofthemind/EventifyGo/internals/repositories"
+	"github.com/lordofthemind/EventifyGo/internals/types"
+)
+
+// LockoutPolicy sets when failed logins slow down and lock an account or a client IP. The
+// first half of a threshold is free, then every failure doubles the delay before the next
+// attempt, starting at BaseDelay, and reaching the threshold blocks for LockoutDuration.
+// A threshold of zero disables its check.
+type LockoutPolicy struct {
+	AccountThreshold int
+	IPThreshold      int
+	BaseDelay        time.Duration
+	LockoutDuration  time.Duration
+	Window           time.Duration
+}
+
+// LoginThrottledError rejects a login while an account or a client IP is blocked
+type LoginThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginThrottledError) Error() string {
+	return fmt.Sprintf("too many failed login attempts, retry in %s", e.RetryAfter)
+}
+
+func (e *LoginThrottledError) Unwrap() error {
+	return apperrors.ErrTooManyRequests
+}
+
+type LockoutService struct {
+	store      repositories.LoginAttemptRepositoryInterface
+	superUsers repositories.SuperUserRepositoryInterface
+	policy     LockoutPolicy
+	locks      *keyLocks
+}
+
+func NewLockoutService(store repositories.LoginAttemptRepositoryInterface, superUsers repositories.SuperUserRepositoryInterface, policy LockoutPolicy) LockoutServiceInterface {
+	return &LockoutService{
+		store:      store,
+		superUsers: superUsers,
+		policy:     policy,
+		locks:      newKeyLocks(),
+	}
+}
+
+// Start a login by taking the locks of its account and client IP, then checking them before
+// the credentials. Without the locks parallel guesses would all pass the check before the
+// first failure is counted.
+func (s *LockoutService) BeginLogin(ctx context.Context, account, clientIP string) (func(), error) {
+	keys := s.keys(account, clientIP)
+	// Always the account first, then the IP, so two logins never wait on each other
+	for i, key := range keys {
+		if err := s.locks.lock(ctx, key); err != nil {
+			s.locks.unlock(keys[:i]...)
+			return nil, err
+		}
+	}
+	release := func() { s.locks.unlock(keys...) }
+
+	if err := s.checkLogin(ctx, keys); err != nil {
+		release()
+		return nil, err
+	}
+	return release, nil
+}
+
+// Helper function to reject a login while one of its keys is blocked
+func (s *LockoutService) checkLogin(ctx context.Context, keys []string) error {
+	now := time.Now()
+	for _, key := range keys {
+		attempts, err := s.store.GetAttempts(ctx, key)
+		if err != nil {
+			return fmt.Errorf("failed to get login attempts: %w", err)
+		}
+		if attempts != nil && attempts.BlockedUntil.After(now) {
+			// Whole seconds, as sent in the Retry-After header
+			retryAfter := attempts.BlockedUntil.Sub(now).Truncate(time.Second) + time.Second
+			return &LoginThrottledError{RetryAfter: retryAfter}
+		}
+	}
+	return nil
+}
+
+// Record a failed login against the account and the client IP
+func (s *LockoutService) RecordFailure(ctx context.Context, account, clientIP string) error {
+	now := time.Now()
+	thresholds := map[string]int{
+		accountKey(account): s.policy.AccountThreshold,
+	}
+	if clientIP != "" {
+		thresholds[ipKey(clientIP)] = s.policy.IPThreshold
+	}
+
+	for key, threshold := range thresholds {
+		if threshold <= 0 {
+			continue
+		}
+		attempts, err := s.store.RecordFailure(ctx, key, now, s.policy.Window)
+		if err != nil {
+			return fmt.Errorf("failed to record login attempt: %w", err)
+		}
+		if delay := s.policy.delay(attempts.Failures, threshold); delay > 0 {
+			if err := s.store.BlockUntil(ctx, key, now.Add(delay)); err != nil {
+				return fmt.Errorf("failed to block logins: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// Reset the account after a successful login. The client IP keeps its failures until they
+// expire, one valid account must not clear the record of an IP guessing others.
+func (s *LockoutService) RecordSuccess(ctx context.Context, account string) error {
+	if err := s.store.ResetAttempts(ctx, accountKey(account)); err != nil {
+		return fmt.Errorf("failed to reset login attempts: %w", err)
+	}
+	return nil
+}
+
+// Get the failed logins of a SuperUser, an empty record when there are none
+func (s *LockoutService) GetSuperUserLockout(ctx context.Context, id uuid.UUID) (*types.LoginAttemptType, error) {
+	if _, err := s.superUsers.FindByID(ctx, id); err != nil {
+		return nil, fmt.Errorf("failed to get superuser: %w", err)
+	}
+
+	key := accountKey(id.String())
+	attempts, err := s.store.GetAttempts(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get login attempts: %w", err)
+	}
+	if attempts == nil {
+		attempts = &types.LoginAttemptType{Key: key}
+	}
+	return attempts, nil
+}
+
+// Unlock a SuperUser, forgetting their failed logins
+func (s *LockoutService) UnlockSuperUser(ctx context.Context, id uuid.UUID) error {
+	if _, err := s.superUsers.FindByID(ctx, id); err != nil {
+		return fmt.Errorf("failed to get superuser: %w", err)
+	}
+	return s.RecordSuccess(ctx, id.String())
+}
+
+// Helper function to list the store keys of a login
+func (s *LockoutService) keys(account, clientIP string) []string {
+	keys := []string{accountKey(account)}
+	if clientIP != "" {
+		keys = append(keys, ipKey(clientIP))
+	}
+	return keys
+}
+
+// Helper function to compute how long to block after a number of failures
+func (p LockoutPolicy) delay(failures, threshold int) time.Duration {
+	if failures >= threshold {
+		return p.LockoutDuration
+	}
+	free := threshold / 2
+	if failures <= free {
+		return 0
+	}
+	delay := p.BaseDelay << (failures - free - 1)
+	if delay <= 0 || delay > p.LockoutDuration {
+		return p.LockoutDuration
+	}
+	return delay
+}
+
+// keyLocks serializes the logins of a store key, a key is forgotten once nobody holds or waits
+// for its lock
+type keyLocks struct {
+	mu    sync.Mutex
+	locks map[string]*keyLock
+}
+
+type keyLock struct {
+	held    chan struct{}
+	waiters int
+}
+
+func newKeyLocks() *keyLocks {
+	return &keyLocks{locks: make(map[string]*keyLock)}
+}
+
+// Helper function to wait for the lock of a key, giving up when ctx is done
+func (l *keyLocks) lock(ctx context.Context, key string) error {
+	l.mu.Lock()
+	lock, exists := l.locks[key]
+	if !exists {
+		lock = &keyLock{held: make(chan struct{}, 1)}
+		l.locks[key] = lock
+	}
+	lock.waiters++
+	l.mu.Unlock()
+
+	select {
+	case lock.held <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		l.forget(key, lock)
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// Helper function to release the locks of keys
+func (l *keyLocks) unlock(keys ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, key := range keys {
+		lock := l.locks[key]
+		<-lock.held
+		l.forget(key, lock)
+	}
+}
+
+// Helper function to drop a key nobody needs anymore, called with mu held
+func (l *keyLocks) forget(key string, lock *keyLock) {
+	lock.waiters--
+	if lock.waiters == 0 {
+		delete(l.locks, key)
+	}
+}
+
+// Helper functions to build the store keys of an account and a client IP
+func accountKey(account string) string {
+	return "account:" + account
+}
+
+func ipKey(clientIP string) string {
+	return "ip:" + clientIP
+}
@@ -0,0 +1,26 @@
+package services
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/lordofthemind/EventifyGo/internals/types"
+)
+
+type LockoutServiceInterface interface {
+	// Start a login, rejecting it with a LoginThrottledError while the account or the client IP
+	// is blocked. account is a SuperUser ID, or the identifier given for an unknown account.
+	// Logins of the same account or client IP run one at a time until the returned function is
+	// called, so the failure or success must be recorded before calling it.
+	BeginLogin(ctx context.Context, account, clientIP string) (func(), error)
+
+	// Count a failed login, blocking the account and client IP once past the policy thresholds
+	RecordFailure(ctx context.Context, account, clientIP string) error
+
+	// Forget the failed logins of an account after a successful login
+	RecordSuccess(ctx context.Context, account string) error
+
+	// Inspect and clear the lockout of a SuperUser
+	GetSuperUserLockout(ctx context.Context, id uuid.UUID) (*types.LoginAttemptType, error)
+	UnlockSuperUser(ctx context.Context, id uuid.UUID) error
+}
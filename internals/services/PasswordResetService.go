@@ -0,0 +1,123 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lordofthemind/EventifyGo/internals/apperrors"
+	"github.com/lordofthemind/EventifyGo/internals/notifiers"
+	"github.com/lordofthemind/EventifyGo/internals/repositories"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// resetTokenBytes is the randomness of a password reset token
+const resetTokenBytes = 32
+
+// Errors returned by the password reset service
+var ErrInvalidResetToken = apperrors.Validation("reset token is invalid or expired")
+
+type PasswordResetService struct {
+	repo          repositories.SuperUserRepositoryInterface
+	sessions      repositories.SessionRepositoryInterface
+	refreshTokens repositories.RefreshTokenRepositoryInterface
+	notifier      notifiers.NotifierInterface
+	tokenDuration time.Duration
+}
+
+func NewPasswordResetService(repo repositories.SuperUserRepositoryInterface, sessions repositories.SessionRepositoryInterface, refreshTokens repositories.RefreshTokenRepositoryInterface, notifier notifiers.NotifierInterface, tokenDuration time.Duration) PasswordResetServiceInterface {
+	return &PasswordResetService{
+		repo:          repo,
+		sessions:      sessions,
+		refreshTokens: refreshTokens,
+		notifier:      notifier,
+		tokenDuration: tokenDuration,
+	}
+}
+
+// Request a password reset, only the hash of the token is stored
+func (s *PasswordResetService) RequestPasswordReset(ctx context.Context, email string) error {
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return fmt.Errorf("%w: email is required", apperrors.ErrValidation)
+	}
+
+	superUser, err := s.repo.FindByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to get superuser by email: %w", err)
+	}
+
+	token, err := generateResetToken()
+	if err != nil {
+		return err
+	}
+	expiresAt := time.Now().Add(s.tokenDuration)
+
+	if err := s.repo.SetResetToken(ctx, superUser.ID, hashResetToken(token), expiresAt); err != nil {
+		return fmt.Errorf("failed to set reset token: %w", err)
+	}
+	if err := s.notifier.SendPasswordReset(ctx, superUser, token, expiresAt); err != nil {
+		return fmt.Errorf("failed to send reset token: %w", err)
+	}
+
+	return nil
+}
+
+// Confirm a password reset with the token and the new password, every session of the
+// SuperUser is revoked so whoever knew the old password is logged out
+func (s *PasswordResetService) ConfirmPasswordReset(ctx context.Context, token, newPassword string) error {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return ErrInvalidResetToken
+	}
+	if len(newPassword) < 8 {
+		return fmt.Errorf("%w: password must be at least 8 characters", apperrors.ErrValidation)
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("password hashing failed: %w", err)
+	}
+
+	superUserID, err := s.repo.ResetPassword(ctx, hashResetToken(token), string(hashedPassword))
+	if err != nil {
+		if errors.Is(err, repositories.ErrResetTokenNotFound) {
+			return ErrInvalidResetToken
+		}
+		return fmt.Errorf("failed to reset password: %w", err)
+	}
+
+	now := time.Now()
+	if err := s.sessions.RevokeAll(ctx, superUserID, now); err != nil {
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+	if err := s.refreshTokens.RevokeAll(ctx, superUserID, now); err != nil {
+		return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+	}
+	return nil
+}
+
+// Helper function to generate a URL safe reset token
+func generateResetToken() (string, error) {
+	raw := make([]byte, resetTokenBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("failed to generate reset token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// Helper function to hash a reset token. The token is random, so a plain SHA-256 is enough
+// and lets the hash be looked up directly.
+func hashResetToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,14 @@
+package services
+
+import (
+	"context"
+)
+
+type PasswordResetServiceInterface interface {
+	// Send a reset token to the SuperUser with this email through the notifier. Unknown emails
+	// are not reported, so the endpoint cannot be used to find accounts.
+	RequestPasswordReset(ctx context.Context, email string) error
+
+	// Set a new password with a reset token, the token is invalidated once used
+	ConfirmPasswordReset(ctx context.Context, token, newPassword string) error
+}
@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lordofthemind/EventifyGo/internals/apperrors"
+	"github.com/lordofthemind/EventifyGo/internals/repositories"
+	"github.com/lordofthemind/EventifyGo/internals/types"
+	"github.com/lordofthemind/mygopher/gophertoken"
+)
+
+// sessionTouchInterval limits how often using a session updates its last seen time
+const sessionTouchInterval = time.Minute
+
+// Errors returned by the session service
+var ErrSessionRevoked = apperrors.Unauthorized("session was revoked or has expired")
+
+type SessionService struct {
+	sessions      repositories.SessionRepositoryInterface
+	refreshTokens repositories.RefreshTokenRepositoryInterface
+	superUsers    repositories.SuperUserRepositoryInterface
+}
+
+func NewSessionService(sessions repositories.SessionRepositoryInterface, refreshTokens repositories.RefreshTokenRepositoryInterface, superUsers repositories.SuperUserRepositoryInterface) SessionServiceInterface {
+	return &SessionService{
+		sessions:      sessions,
+		refreshTokens: refreshTokens,
+		superUsers:    superUsers,
+	}
+}
+
+// Check the session named in the subject of an access token and note that it was used
+func (s *SessionService) Authenticate(ctx context.Context, payload *gophertoken.Payload, clientIP string) (uuid.UUID, uuid.UUID, error) {
+	superUserID, sessionID, err := parseAccessTokenSubject(payload.Username)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+
+	session, err := s.sessions.FindByID(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, repositories.ErrSessionNotFound) {
+			return uuid.Nil, uuid.Nil, ErrSessionRevoked
+		}
+		return uuid.Nil, uuid.Nil, fmt.Errorf("failed to get session: %w", err)
+	}
+
+	now := time.Now()
+	if session.SuperUserID != superUserID || session.RevokedAt != nil || !now.Before(session.ExpiresAt) {
+		return uuid.Nil, uuid.Nil, ErrSessionRevoked
+	}
+
+	if now.Sub(session.LastSeenAt) >= sessionTouchInterval || session.IPAddress != clientIP {
+		if err := s.sessions.Touch(ctx, sessionID, now, clientIP); err != nil {
+			return uuid.Nil, uuid.Nil, fmt.Errorf("failed to update session: %w", err)
+		}
+	}
+
+	return superUserID, sessionID, nil
+}
+
+// List the sessions of a SuperUser
+func (s *SessionService) ListSessions(ctx context.Context, superUserID uuid.UUID) ([]*types.SessionType, error) {
+	if _, err := s.superUsers.FindByID(ctx, superUserID); err != nil {
+		return nil, fmt.Errorf("failed to get superuser: %w", err)
+	}
+
+	sessions, err := s.sessions.ListActive(ctx, superUserID, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	return sessions, nil
+}
+
+// Revoke a session of a SuperUser, sessions of other SuperUsers are not found
+func (s *SessionService) RevokeSession(ctx context.Context, superUserID, sessionID uuid.UUID) error {
+	session, err := s.sessions.FindByID(ctx, sessionID)
+	if err != nil {
+		return fmt.Errorf("failed to get session: %w", err)
+	}
+	if session.SuperUserID != superUserID {
+		return repositories.ErrSessionNotFound
+	}
+
+	return revokeSession(ctx, s.sessions, s.refreshTokens, sessionID, time.Now())
+}
+
+// Revoke every session of a SuperUser
+func (s *SessionService) RevokeAllSessions(ctx context.Context, superUserID uuid.UUID) error {
+	if _, err := s.superUsers.FindByID(ctx, superUserID); err != nil {
+		return fmt.Errorf("failed to get superuser: %w", err)
+	}
+
+	now := time.Now()
+	if err := s.sessions.RevokeAll(ctx, superUserID, now); err != nil {
+		return fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+	if err := s.refreshTokens.RevokeAll(ctx, superUserID, now); err != nil {
+		return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+	}
+	return nil
+}
+
+// Helper function to revoke a session and the refresh tokens of its family
+func revokeSession(ctx context.Context, sessions repositories.SessionRepositoryInterface, refreshTokens repositories.RefreshTokenRepositoryInterface, sessionID uuid.UUID, at time.Time) error {
+	if err := sessions.Revoke(ctx, sessionID, at); err != nil && !errors.Is(err, repositories.ErrSessionNotFound) {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	if err := refreshTokens.RevokeFamily(ctx, sessionID, at); err != nil {
+		return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+	}
+	return nil
+}
+
+// Helper function to build the subject of an access token, the SuperUser and session IDs
+func accessTokenSubject(superUserID, sessionID uuid.UUID) string {
+	return superUserID.String() + ":" + sessionID.String()
+}
+
+// Helper function to read the SuperUser and session IDs back from an access token subject
+func parseAccessTokenSubject(subject string) (uuid.UUID, uuid.UUID, error) {
+	superUserPart, sessionPart, found := strings.Cut(subject, ":")
+	if !found {
+		return uuid.Nil, uuid.Nil, ErrSessionRevoked
+	}
+	superUserID, err := uuid.Parse(superUserPart)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, ErrSessionRevoked
+	}
+	sessionID, err := uuid.Parse(sessionPart)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, ErrSessionRevoked
+	}
+	return superUserID, sessionID, nil
+}
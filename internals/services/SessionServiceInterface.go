@@ -0,0 +1,22 @@
+package services
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/lordofthemind/EventifyGo/internals/types"
+	"github.com/lordofthemind/mygopher/gophertoken"
+)
+
+type SessionServiceInterface interface {
+	// Check that the session of a validated access token is still live, returning the SuperUser
+	// and session it was issued for. A revoked or expired session fails with ErrSessionRevoked.
+	Authenticate(ctx context.Context, payload *gophertoken.Payload, clientIP string) (superUserID, sessionID uuid.UUID, err error)
+
+	// List the active sessions of a SuperUser, most recently seen first
+	ListSessions(ctx context.Context, superUserID uuid.UUID) ([]*types.SessionType, error)
+
+	// Revoke one or every session of a SuperUser, with their refresh tokens
+	RevokeSession(ctx context.Context, superUserID, sessionID uuid.UUID) error
+	RevokeAllSessions(ctx context.Context, superUserID uuid.UUID) error
+}
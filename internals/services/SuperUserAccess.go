@@ -0,0 +1,129 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/lordofthemind/EventifyGo/internals/apperrors"
+	"github.com/lordofthemind/EventifyGo/internals/rbac"
+	"github.com/lordofthemind/EventifyGo/internals/types"
+)
+
+// Errors returned while changing the access of a SuperUser
+var (
+	ErrAccessDenied         = apperrors.Forbidden("not allowed to change roles or permissions")
+	ErrOwnAccessChange      = apperrors.Forbidden("a superuser cannot change their own role or permissions")
+	ErrPermissionEscalation = apperrors.Forbidden("cannot grant or revoke permissions the actor does not hold")
+	ErrTargetOutranksActor  = apperrors.Forbidden("cannot change a superuser holding permissions the actor does not hold")
+)
+
+// Update SuperUser role
+func (s *SuperUserService) UpdateSuperUserRole(ctx context.Context, actorID, id uuid.UUID, role string) error {
+	target, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get superuser: %w", err)
+	}
+	if err := s.authorizeGrant(ctx, actorID, target, role, target.PermissionGroups); err != nil {
+		return err
+	}
+
+	if err := s.repo.UpdateSuperuserRole(ctx, id, role); err != nil {
+		return fmt.Errorf("failed to update superuser role: %w", err)
+	}
+	return nil
+}
+
+// Update SuperUser permissions
+func (s *SuperUserService) UpdateSuperUserPermissions(ctx context.Context, actorID, id uuid.UUID, permissions []string) error {
+	target, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get superuser: %w", err)
+	}
+	if err := s.authorizeGrant(ctx, actorID, target, target.Role, permissions); err != nil {
+		return err
+	}
+
+	if err := s.repo.UpdateField(ctx, id, "permission_groups", permissions); err != nil {
+		return fmt.Errorf("failed to update superuser permissions: %w", err)
+	}
+	return nil
+}
+
+// Helper function to check that an actor may give a SuperUser a role and permission groups.
+// target is nil for a SuperUser being created, creating a guest is always allowed. The actor
+// must hold every permission the target has now and will have afterwards.
+func (s *SuperUserService) authorizeGrant(ctx context.Context, actorID uuid.UUID, target *types.SuperUserType, role string, permissionGroups []string) error {
+	if err := validateGrant(role, permissionGroups); err != nil {
+		return err
+	}
+
+	affected := rbac.Permissions(role, permissionGroups)
+	if target == nil && len(affected) == 0 {
+		return nil
+	}
+	if target != nil {
+		for permission := range rbac.Permissions(target.Role, target.PermissionGroups) {
+			affected[permission] = true
+		}
+	}
+
+	actor, err := s.repo.FindByID(ctx, actorID)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return ErrAccessDenied
+		}
+		return fmt.Errorf("failed to get acting superuser: %w", err)
+	}
+	held := rbac.Permissions(actor.Role, actor.PermissionGroups)
+
+	if !held[rbac.PermissionSuperUsersRoles] {
+		return ErrAccessDenied
+	}
+	if target != nil && target.ID == actor.ID {
+		return ErrOwnAccessChange
+	}
+	for permission := range affected {
+		if !held[permission] {
+			return ErrPermissionEscalation
+		}
+	}
+	return nil
+}
+
+// Helper function to check that an actor may change or delete another SuperUser. SuperUsers
+// may always change themselves, anyone else must not hold a permission the actor lacks.
+func (s *SuperUserService) authorizeTargetChange(ctx context.Context, actorID uuid.UUID, target *types.SuperUserType) error {
+	if actorID == target.ID {
+		return nil
+	}
+
+	actor, err := s.repo.FindByID(ctx, actorID)
+	if err != nil {
+		if errors.Is(err, apperrors.ErrNotFound) {
+			return ErrTargetOutranksActor
+		}
+		return fmt.Errorf("failed to get acting superuser: %w", err)
+	}
+	held := rbac.Permissions(actor.Role, actor.PermissionGroups)
+	for permission := range rbac.Permissions(target.Role, target.PermissionGroups) {
+		if !held[permission] {
+			return ErrTargetOutranksActor
+		}
+	}
+	return nil
+}
+
+// Helper function to check that a role and permission groups exist
+func validateGrant(role string, permissionGroups []string) error {
+	if !rbac.IsRole(role) {
+		return fmt.Errorf("%w: unknown role %q", apperrors.ErrValidation, role)
+	}
+	for _, permission := range permissionGroups {
+		if !rbac.IsPermission(permission) {
+			return fmt.Errorf("%w: unknown permission %q", apperrors.ErrValidation, permission)
+		}
+	}
+	return nil
+}
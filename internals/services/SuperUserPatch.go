@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/lordofthemind/EventifyGo/internals/apperrors"
+	"github.com/lordofthemind/EventifyGo/internals/types"
+	"github.com/lordofthemind/EventifyGo/pkgs/jsonpatch"
+)
+
+// PatchFormat is the format of a SuperUser patch document
+type PatchFormat int
+
+const (
+	// MergePatch is an RFC 7396 JSON Merge Patch, application/merge-patch+json
+	MergePatch PatchFormat = iota
+
+	// JSONPatch is an RFC 6902 JSON Patch, application/json-patch+json
+	JSONPatch
+)
+
+// patchableField is a SuperUser field a patch may write. All of them are strings for now,
+// the field names its Go struct field for the validate tags.
+type patchableField struct {
+	structField string
+	value       func(superUser *types.SuperUserType) *string
+}
+
+// patchableFields is the allowlist of patchable fields by their JSON name, which is also their
+// column name. Passwords, roles, permissions and 2FA state have their own endpoints.
+var patchableFields = map[string]patchableField{
+	"email":     {structField: "Email", value: func(u *types.SuperUserType) *string { return &u.Email }},
+	"full_name": {structField: "FullName", value: func(u *types.SuperUserType) *string { return &u.FullName }},
+	"username":  {structField: "Username", value: func(u *types.SuperUserType) *string { return &u.Username }},
+}
+
+// superUserValidator checks the validate tags of SuperUserType
+var superUserValidator = validator.New()
+
+// Patch a SuperUser with a JSON Merge Patch or a JSON Patch. The patch is applied to the
+// patchable fields only and the result is validated before anything is written.
+func (s *SuperUserService) PatchSuperUser(ctx context.Context, actorID, id uuid.UUID, format PatchFormat, patch []byte) (*types.SuperUserType, error) {
+	superUser, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get superuser: %w", err)
+	}
+	if err := s.authorizeTargetChange(ctx, actorID, superUser); err != nil {
+		return nil, err
+	}
+
+	document := make(map[string]interface{}, len(patchableFields))
+	for name, field := range patchableFields {
+		document[name] = *field.value(superUser)
+	}
+	original, err := json.Marshal(document)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode superuser: %w", err)
+	}
+
+	var patched []byte
+	switch format {
+	case MergePatch:
+		patched, err = jsonpatch.MergePatch(original, patch)
+	case JSONPatch:
+		patched, err = jsonpatch.Apply(original, patch)
+	default:
+		return nil, fmt.Errorf("%w: unsupported patch format", apperrors.ErrValidation)
+	}
+	if errors.Is(err, jsonpatch.ErrTestFailed) {
+		// The SuperUser no longer matches what the client expected
+		return nil, fmt.Errorf("%w: %w", apperrors.ErrConflict, err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", apperrors.ErrValidation, err)
+	}
+
+	var result map[string]interface{}
+	if err := json.Unmarshal(patched, &result); err != nil {
+		return nil, fmt.Errorf("%w: patched superuser must be an object", apperrors.ErrValidation)
+	}
+
+	// Type check every field on a copy, the stored SuperUser is only touched once all is valid
+	updated := *superUser
+	changes := make(map[string]interface{})
+	for name, value := range result {
+		field, ok := patchableFields[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: field %q cannot be patched", apperrors.ErrValidation, name)
+		}
+		text, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: field %q must be a string", apperrors.ErrValidation, name)
+		}
+		if text != *field.value(superUser) {
+			*field.value(&updated) = text
+			changes[name] = text
+		}
+	}
+
+	structFields := make([]string, 0, len(patchableFields))
+	for name, field := range patchableFields {
+		if _, ok := result[name]; !ok {
+			return nil, fmt.Errorf("%w: field %q cannot be removed", apperrors.ErrValidation, name)
+		}
+		structFields = append(structFields, field.structField)
+	}
+	if err := superUserValidator.StructPartial(&updated, structFields...); err != nil {
+		return nil, fmt.Errorf("%w: %w", apperrors.ErrValidation, err)
+	}
+
+	if len(changes) == 0 {
+		return superUser, nil
+	}
+	if err := s.repo.UpdateFields(ctx, id, changes); err != nil {
+		return nil, fmt.Errorf("failed to patch superuser: %w", err)
+	}
+
+	return s.GetSuperUserByID(ctx, id)
+}
@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/lordofthemind/EventifyGo/internals/repositories"
+	"github.com/lordofthemind/EventifyGo/internals/types"
+)
+
+const (
+	// recoveryCodeCount is the number of recovery codes issued at a time
+	recoveryCodeCount = 10
+
+	// recoveryCodeLength is the number of base32 characters of a code, 50 bits of randomness
+	recoveryCodeLength = 10
+)
+
+var recoveryCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// Replace the recovery codes of a SuperUser with a new set, the old codes stop working
+func (s *SuperUserService) RegenerateRecoveryCodes(ctx context.Context, actorID, id uuid.UUID) ([]string, error) {
+	// The plaintext codes are handed out, nobody else may receive them
+	if actorID != id {
+		return nil, ErrNotOwnTwoFactor
+	}
+
+	superUser, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get superuser: %w", err)
+	}
+	if !superUser.Is2FAEnabled {
+		return nil, ErrTwoFactorNotEnabled
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	superUser.RecoveryCodeHashes = hashes
+	if err := s.repo.Update(ctx, superUser); err != nil {
+		return nil, fmt.Errorf("failed to store recovery codes: %w", err)
+	}
+
+	return codes, nil
+}
+
+// Count the unused recovery codes of a SuperUser
+func (s *SuperUserService) CountRecoveryCodes(ctx context.Context, id uuid.UUID) (int, error) {
+	superUser, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get superuser: %w", err)
+	}
+	if !superUser.Is2FAEnabled {
+		return 0, nil
+	}
+	return len(superUser.RecoveryCodeHashes), nil
+}
+
+// Helper function to create a set of recovery codes, formatted as "xxxxx-xxxxx", with their hashes
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for i := 0; i < recoveryCodeCount; i++ {
+		raw := make([]byte, (recoveryCodeLength*5+7)/8)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, nil, fmt.Errorf("failed to generate recovery codes: %w", err)
+		}
+		encoded := strings.ToLower(recoveryCodeEncoding.EncodeToString(raw))[:recoveryCodeLength]
+		code := encoded[:recoveryCodeLength/2] + "-" + encoded[recoveryCodeLength/2:]
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+	return codes, hashes, nil
+}
+
+// Helper function to hash a recovery code. The codes are random, so a plain SHA-256 is enough
+// and keeps checking them cheap. Case, spaces and dashes are ignored.
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
+
+// Helper function to use up a recovery code of a SuperUser, false when it is unknown or was used
+func consumeRecoveryCode(ctx context.Context, repo repositories.SuperUserRepositoryInterface, superUser *types.SuperUserType, code string) (bool, error) {
+	hash := hashRecoveryCode(code)
+	if err := repo.ConsumeRecoveryCode(ctx, superUser.ID, hash); err != nil {
+		if errors.Is(err, repositories.ErrRecoveryCodeNotFound) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to use recovery code: %w", err)
+	}
+
+	// Keep the loaded record in step so a later Update does not write the code back
+	remaining := make([]string, 0, len(superUser.RecoveryCodeHashes))
+	for _, stored := range superUser.RecoveryCodeHashes {
+		if stored != hash {
+			remaining = append(remaining, stored)
+		}
+	}
+	superUser.RecoveryCodeHashes = remaining
+	return true, nil
+}
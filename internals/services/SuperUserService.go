@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lordofthemind/EventifyGo/internals/apperrors"
+	"github.com/lordofthemind/EventifyGo/internals/rbac"
 	"github.com/lordofthemind/EventifyGo/internals/repositories"
 	"github.com/lordofthemind/EventifyGo/internals/types"
 	"golang.org/x/crypto/bcrypt"
@@ -20,13 +22,42 @@ func NewSuperUserService(repo repositories.SuperUserRepositoryInterface) SuperUs
 	return &SuperUserService{repo: repo}
 }
 
-// Create a new SuperUser
-func (s *SuperUserService) CreateSuperUser(ctx context.Context, superUser *types.SuperUserType) (*types.SuperUserType, error) {
+// Create a new SuperUser, a role beyond guest is only granted by an authorized actor
+func (s *SuperUserService) CreateSuperUser(ctx context.Context, actorID uuid.UUID, superUser *types.SuperUserType) (*types.SuperUserType, error) {
 	// Validate fields
 	if err := validateSuperUser(superUser); err != nil {
-		return nil, fmt.Errorf("validation error: %w", err)
+		return nil, fmt.Errorf("%w: %w", apperrors.ErrValidation, err)
 	}
 
+	if superUser.Role == "" {
+		superUser.Role = rbac.RoleGuest
+	}
+	if err := s.authorizeGrant(ctx, actorID, nil, superUser.Role, superUser.PermissionGroups); err != nil {
+		return nil, err
+	}
+
+	return s.createSuperUser(ctx, superUser)
+}
+
+// Create a SuperUser with any role and no acting SuperUser, the first admin can only be made
+// this way. Not reachable over the API.
+func (s *SuperUserService) BootstrapSuperUser(ctx context.Context, superUser *types.SuperUserType) (*types.SuperUserType, error) {
+	if err := validateSuperUser(superUser); err != nil {
+		return nil, fmt.Errorf("%w: %w", apperrors.ErrValidation, err)
+	}
+
+	if superUser.Role == "" {
+		superUser.Role = rbac.RoleAdmin
+	}
+	if err := validateGrant(superUser.Role, superUser.PermissionGroups); err != nil {
+		return nil, err
+	}
+
+	return s.createSuperUser(ctx, superUser)
+}
+
+// Helper function to hash the password of a validated SuperUser and store it
+func (s *SuperUserService) createSuperUser(ctx context.Context, superUser *types.SuperUserType) (*types.SuperUserType, error) {
 	// Hash password
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(superUser.HashedPassword), bcrypt.DefaultCost)
 	if err != nil {
@@ -50,7 +81,7 @@ func (s *SuperUserService) CreateSuperUser(ctx context.Context, superUser *types
 func (s *SuperUserService) GetSuperUserByID(ctx context.Context, id uuid.UUID) (*types.SuperUserType, error) {
 	superUser, err := s.repo.FindByID(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("superuser not found: %w", err)
+		return nil, fmt.Errorf("failed to get superuser: %w", err)
 	}
 	return superUser, nil
 }
@@ -59,7 +90,7 @@ func (s *SuperUserService) GetSuperUserByID(ctx context.Context, id uuid.UUID) (
 func (s *SuperUserService) GetSuperUserByEmail(ctx context.Context, email string) (*types.SuperUserType, error) {
 	superUser, err := s.repo.FindByEmail(ctx, email)
 	if err != nil {
-		return nil, fmt.Errorf("superuser not found by email: %w", err)
+		return nil, fmt.Errorf("failed to get superuser by email: %w", err)
 	}
 	return superUser, nil
 }
@@ -68,48 +99,35 @@ func (s *SuperUserService) GetSuperUserByEmail(ctx context.Context, email string
 func (s *SuperUserService) GetSuperUserByUsername(ctx context.Context, username string) (*types.SuperUserType, error) {
 	superUser, err := s.repo.FindByUsername(ctx, username)
 	if err != nil {
-		return nil, fmt.Errorf("superuser not found by username: %w", err)
+		return nil, fmt.Errorf("failed to get superuser by username: %w", err)
 	}
 	return superUser, nil
 }
 
-// Get SuperUser by reset token
-func (s *SuperUserService) GetSuperUserByResetToken(ctx context.Context, token string) (*types.SuperUserType, error) {
-	superUser, err := s.repo.FindByResetToken(ctx, token)
-	if err != nil {
-		return nil, fmt.Errorf("superuser not found by reset token: %w", err)
-	}
-	return superUser, nil
-}
-
-// Enable 2FA for SuperUser
-func (s *SuperUserService) Enable2FAForSuperUser(ctx context.Context, id uuid.UUID, secret string) error {
+// Disable 2FA for SuperUser. SuperUsers turning off their own 2FA confirm it with a current
+// TOTP or recovery code, so a stolen session alone cannot remove the second factor.
+func (s *SuperUserService) Disable2FAForSuperUser(ctx context.Context, actorID, id uuid.UUID, code string) error {
 	superUser, err := s.repo.FindByID(ctx, id)
 	if err != nil {
-		return fmt.Errorf("superuser not found: %w", err)
+		return fmt.Errorf("failed to get superuser: %w", err)
 	}
-
-	// Update 2FA fields
-	superUser.TwoFactorSecret = &secret
-	superUser.Is2FAEnabled = true
-
-	if err := s.repo.Update(ctx, superUser); err != nil {
-		return fmt.Errorf("failed to enable 2FA: %w", err)
+	if err := s.authorizeTargetChange(ctx, actorID, superUser); err != nil {
+		return err
 	}
-
-	return nil
-}
-
-// Disable 2FA for SuperUser
-func (s *SuperUserService) Disable2FAForSuperUser(ctx context.Context, id uuid.UUID) error {
-	superUser, err := s.repo.FindByID(ctx, id)
-	if err != nil {
-		return fmt.Errorf("superuser not found: %w", err)
+	if actorID == superUser.ID && superUser.Is2FAEnabled {
+		valid, err := consumeSecondFactor(ctx, s.repo, superUser, code)
+		if err != nil {
+			return err
+		}
+		if !valid {
+			return ErrInvalidTwoFactorCode
+		}
 	}
 
 	// Clear 2FA fields
 	superUser.TwoFactorSecret = nil
 	superUser.Is2FAEnabled = false
+	superUser.RecoveryCodeHashes = nil
 
 	if err := s.repo.Update(ctx, superUser); err != nil {
 		return fmt.Errorf("failed to disable 2FA: %w", err)
@@ -127,14 +145,6 @@ func (s *SuperUserService) GetAll2FAEnabledSuperUsers(ctx context.Context) ([]*t
 	return superUsers, nil
 }
 
-// Update SuperUser role
-func (s *SuperUserService) UpdateSuperUserRole(ctx context.Context, id uuid.UUID, role string) error {
-	if err := s.repo.UpdateSuperuserRole(ctx, id, role); err != nil {
-		return fmt.Errorf("failed to update superuser role: %w", err)
-	}
-	return nil
-}
-
 // Get role by SuperUser ID
 func (s *SuperUserService) GetRoleBySuperUserID(ctx context.Context, id uuid.UUID) (string, error) {
 	role, err := s.repo.GetRoleByID(ctx, id)
@@ -144,14 +154,6 @@ func (s *SuperUserService) GetRoleBySuperUserID(ctx context.Context, id uuid.UUI
 	return role, nil
 }
 
-// Update SuperUser permissions
-func (s *SuperUserService) UpdateSuperUserPermissions(ctx context.Context, id uuid.UUID, permissions []string) error {
-	if err := s.repo.UpdateField(ctx, id, "permission_groups", permissions); err != nil {
-		return fmt.Errorf("failed to update superuser permissions: %w", err)
-	}
-	return nil
-}
-
 // Update SuperUser details
 func (s *SuperUserService) UpdateSuperUserDetails(ctx context.Context, superUser *types.SuperUserType) error {
 	superUser.UpdatedAt = time.Now()
@@ -161,33 +163,16 @@ func (s *SuperUserService) UpdateSuperUserDetails(ctx context.Context, superUser
 	return nil
 }
 
-// Update specific SuperUser field
-func (s *SuperUserService) UpdateSuperUserField(ctx context.Context, id uuid.UUID, field string, value interface{}) error {
-	if err := s.repo.UpdateField(ctx, id, field, value); err != nil {
-		return fmt.Errorf("failed to update superuser field: %w", err)
-	}
-	return nil
-}
-
-// Generate and set reset token
-func (s *SuperUserService) GenerateAndSetResetToken(ctx context.Context, id uuid.UUID) (string, error) {
-	token := generateResetToken() // You can implement your token generator
-	if err := s.repo.UpdateResetToken(ctx, id, token); err != nil {
-		return "", fmt.Errorf("failed to set reset token: %w", err)
+// Delete SuperUser by ID
+func (s *SuperUserService) DeleteSuperUserByID(ctx context.Context, actorID, id uuid.UUID) error {
+	superUser, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get superuser: %w", err)
 	}
-	return token, nil
-}
-
-// Clear reset token
-func (s *SuperUserService) ClearResetToken(ctx context.Context, id uuid.UUID) error {
-	if err := s.repo.UpdateResetToken(ctx, id, ""); err != nil {
-		return fmt.Errorf("failed to clear reset token: %w", err)
+	if err := s.authorizeTargetChange(ctx, actorID, superUser); err != nil {
+		return err
 	}
-	return nil
-}
 
-// Delete SuperUser by ID
-func (s *SuperUserService) DeleteSuperUserByID(ctx context.Context, id uuid.UUID) error {
 	if err := s.repo.DeleteByID(ctx, id); err != nil {
 		return fmt.Errorf("failed to delete superuser: %w", err)
 	}
@@ -196,6 +181,9 @@ func (s *SuperUserService) DeleteSuperUserByID(ctx context.Context, id uuid.UUID
 
 // Search SuperUsers with pagination and sorting
 func (s *SuperUserService) SearchSuperUsers(ctx context.Context, searchQuery string, page, limit int, sortBy string) ([]*types.SuperUserType, error) {
+	if _, err := repositories.SuperUserSortKey(sortBy); err != nil {
+		return nil, err
+	}
 	superUsers, err := s.repo.SearchSuperusers(ctx, searchQuery, page, limit, sortBy)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search superusers: %w", err)
@@ -210,12 +198,6 @@ func (s *SuperUserService) GetAllSuperUsers(ctx context.Context) ([]*types.Super
 	if err != nil {
 		return nil, fmt.Errorf("failed to retrieve all superusers: %w", err)
 	}
-
-	// If no super users are found, return an error
-	if len(superUsers) == 0 {
-		return nil, errors.New("no superusers found")
-	}
-
 	return superUsers, nil
 }
 
@@ -225,10 +207,8 @@ func validateSuperUser(superUser *types.SuperUserType) error {
 	if superUser.Email == "" || superUser.Username == "" {
 		return errors.New("email and username are required")
 	}
+	if len(superUser.HashedPassword) < 8 {
+		return errors.New("password must be at least 8 characters")
+	}
 	return nil
 }
-
-// Generate reset token (dummy implementation)
-func generateResetToken() string {
-	return uuid.New().String()
-}
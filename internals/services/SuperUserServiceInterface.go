@@ -8,35 +8,47 @@ import (
 )
 
 type SuperUserServiceInterface interface {
-	// Create a new SuperUser with validation, hashing password, and initializing defaults
-	CreateSuperUser(ctx context.Context, superUser *types.SuperUserType) (*types.SuperUserType, error)
+	// Create a new SuperUser with validation, hashing password, and initializing defaults. Any
+	// role but guest, or permission groups, are granted under the same rules as a role change.
+	CreateSuperUser(ctx context.Context, actorID uuid.UUID, superUser *types.SuperUserType) (*types.SuperUserType, error)
+
+	// Create a SuperUser with any role without an acting SuperUser, to bootstrap the first admin
+	// from the command line. Defaults to the admin role.
+	BootstrapSuperUser(ctx context.Context, superUser *types.SuperUserType) (*types.SuperUserType, error)
 
 	// Find SuperUser by different identifiers
 	GetSuperUserByID(ctx context.Context, id uuid.UUID) (*types.SuperUserType, error)
 	GetSuperUserByEmail(ctx context.Context, email string) (*types.SuperUserType, error)
 	GetSuperUserByUsername(ctx context.Context, username string) (*types.SuperUserType, error)
-	GetSuperUserByResetToken(ctx context.Context, token string) (*types.SuperUserType, error)
 
-	// Manage 2FA
-	Enable2FAForSuperUser(ctx context.Context, id uuid.UUID, secret string) error
-	Disable2FAForSuperUser(ctx context.Context, id uuid.UUID) error
+	// Manage 2FA, setup generates the secret and enabling confirms it with a first code. Only
+	// the SuperUser may set up and enable their own 2FA. Disabling your own 2FA takes a TOTP
+	// or recovery code, disabling it for another SuperUser needs every permission they hold.
+	Setup2FAForSuperUser(ctx context.Context, actorID, id uuid.UUID) (*TwoFactorSetup, error)
+	Enable2FAForSuperUser(ctx context.Context, actorID, id uuid.UUID, code string) ([]string, error)
+	Disable2FAForSuperUser(ctx context.Context, actorID, id uuid.UUID, code string) error
 	GetAll2FAEnabledSuperUsers(ctx context.Context) ([]*types.SuperUserType, error)
 
-	// Manage SuperUser roles and permissions
-	UpdateSuperUserRole(ctx context.Context, id uuid.UUID, role string) error
+	// Manage 2FA recovery codes, only their hashes are stored and only the SuperUser may
+	// regenerate their own
+	RegenerateRecoveryCodes(ctx context.Context, actorID, id uuid.UUID) ([]string, error)
+	CountRecoveryCodes(ctx context.Context, id uuid.UUID) (int, error)
+
+	// Manage SuperUser roles and permissions. The actor needs the superusers:roles permission,
+	// cannot change their own access and cannot grant permissions they do not hold.
+	UpdateSuperUserRole(ctx context.Context, actorID, id uuid.UUID, role string) error
 	GetRoleBySuperUserID(ctx context.Context, id uuid.UUID) (string, error)
-	UpdateSuperUserPermissions(ctx context.Context, id uuid.UUID, permissions []string) error
+	UpdateSuperUserPermissions(ctx context.Context, actorID, id uuid.UUID, permissions []string) error
 
-	// Update specific fields for a SuperUser
+	// Update the details of a SuperUser
 	UpdateSuperUserDetails(ctx context.Context, superUser *types.SuperUserType) error
-	UpdateSuperUserField(ctx context.Context, id uuid.UUID, field string, value interface{}) error
 
-	// Reset token management
-	GenerateAndSetResetToken(ctx context.Context, id uuid.UUID) (string, error)
-	ClearResetToken(ctx context.Context, id uuid.UUID) error
+	// Patch the allowlisted fields of a SuperUser with a JSON Merge Patch or a JSON Patch.
+	// Patching another SuperUser needs every permission they hold.
+	PatchSuperUser(ctx context.Context, actorID, id uuid.UUID, format PatchFormat, patch []byte) (*types.SuperUserType, error)
 
-	// Delete operations
-	DeleteSuperUserByID(ctx context.Context, id uuid.UUID) error
+	// Delete operations, deleting another SuperUser needs every permission they hold
+	DeleteSuperUserByID(ctx context.Context, actorID, id uuid.UUID) error
 
 	// Search SuperUsers with pagination and sorting
 	SearchSuperUsers(ctx context.Context, searchQuery string, page, limit int, sortBy string) ([]*types.SuperUserType, error)
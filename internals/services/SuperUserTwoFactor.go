@@ -0,0 +1,143 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lordofthemind/EventifyGo/internals/apperrors"
+	"github.com/lordofthemind/EventifyGo/internals/repositories"
+	"github.com/lordofthemind/EventifyGo/internals/types"
+	"github.com/lordofthemind/EventifyGo/pkgs/totp"
+)
+
+// totpIssuer names the service in authenticator apps
+const totpIssuer = "EventifyGo"
+
+// Errors returned while managing two-factor authentication
+var (
+	ErrTwoFactorAlreadyEnabled = apperrors.Conflict("two-factor authentication is already enabled")
+	ErrTwoFactorNotStarted     = apperrors.Conflict("two-factor setup has not been started")
+	ErrTwoFactorNotEnabled     = apperrors.Conflict("two-factor authentication is not enabled")
+	ErrInvalidTwoFactorCode    = apperrors.Validation("invalid or already used two-factor code")
+	ErrNotOwnTwoFactor         = apperrors.Forbidden("only the superuser can set up their own 2FA or recovery codes")
+)
+
+// TwoFactorSetup is handed to the SuperUser once to configure an authenticator app
+type TwoFactorSetup struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+// Start 2FA setup for a SuperUser. A new secret is stored but not enforced until a first
+// code confirms it, starting over replaces the secret.
+func (s *SuperUserService) Setup2FAForSuperUser(ctx context.Context, actorID, id uuid.UUID) (*TwoFactorSetup, error) {
+	// The secret is handed out, nobody else may receive it
+	if actorID != id {
+		return nil, ErrNotOwnTwoFactor
+	}
+
+	superUser, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get superuser: %w", err)
+	}
+	if superUser.Is2FAEnabled {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate 2FA secret: %w", err)
+	}
+	superUser.TwoFactorSecret = &secret
+
+	if err := s.repo.Update(ctx, superUser); err != nil {
+		return nil, fmt.Errorf("failed to start 2FA setup: %w", err)
+	}
+
+	return &TwoFactorSetup{
+		Secret: secret,
+		URI:    totp.URI(totpIssuer, superUser.Email, secret),
+	}, nil
+}
+
+// Enable 2FA for SuperUser once the first code of the pending secret is confirmed. The
+// returned recovery codes are shown once, only their hashes are kept.
+func (s *SuperUserService) Enable2FAForSuperUser(ctx context.Context, actorID, id uuid.UUID, code string) ([]string, error) {
+	if actorID != id {
+		return nil, ErrNotOwnTwoFactor
+	}
+
+	superUser, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get superuser: %w", err)
+	}
+	if superUser.Is2FAEnabled {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+	if superUser.TwoFactorSecret == nil {
+		return nil, ErrTwoFactorNotStarted
+	}
+
+	valid, err := consumeTOTPCode(ctx, s.repo, superUser, code)
+	if err != nil {
+		return nil, err
+	}
+	if !valid {
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	superUser.Is2FAEnabled = true
+	superUser.RecoveryCodeHashes = hashes
+	if err := s.repo.Update(ctx, superUser); err != nil {
+		return nil, fmt.Errorf("failed to enable 2FA: %w", err)
+	}
+
+	return codes, nil
+}
+
+// Helper function to accept either a TOTP code or, failing that, one of the recovery codes
+func consumeSecondFactor(ctx context.Context, repo repositories.SuperUserRepositoryInterface, superUser *types.SuperUserType, code string) (bool, error) {
+	code = strings.TrimSpace(code)
+	if len(code) == totp.Digits {
+		valid, err := consumeTOTPCode(ctx, repo, superUser, code)
+		if err != nil || valid {
+			return valid, err
+		}
+	}
+	return consumeRecoveryCode(ctx, repo, superUser, code)
+}
+
+// Helper function to check a TOTP code of a SuperUser and use up its time step, so a code
+// is accepted once. It reports false for a wrong or replayed code.
+func consumeTOTPCode(ctx context.Context, repo repositories.SuperUserRepositoryInterface, superUser *types.SuperUserType, code string) (bool, error) {
+	if superUser.TwoFactorSecret == nil {
+		return false, nil
+	}
+
+	step, valid, err := totp.Validate(*superUser.TwoFactorSecret, code, time.Now())
+	if err != nil {
+		return false, fmt.Errorf("failed to verify 2FA code: %w", err)
+	}
+	if !valid {
+		return false, nil
+	}
+
+	if err := repo.AdvanceTOTPStep(ctx, superUser.ID, step); err != nil {
+		if errors.Is(err, repositories.ErrTOTPStepUsed) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to record 2FA code: %w", err)
+	}
+	// Keep the loaded record in step so a later Update does not write the old value back
+	superUser.LastTOTPStep = step
+	return true, nil
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lordofthemind/EventifyGo/internals/repositories/inmemorydb"
+	"github.com/lordofthemind/EventifyGo/internals/types"
+	"github.com/lordofthemind/EventifyGo/pkgs/totp"
+)
+
+func TestConsumeTOTPCodeRejectsReplay(t *testing.T) {
+	ctx := context.Background()
+	repo := inmemorydb.NewInMemorySuperUserRepository()
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret returned error: %v", err)
+	}
+	superUser := &types.SuperUserType{Email: "totp@example.com", Username: "totp", TwoFactorSecret: &secret}
+	if err := repo.Create(ctx, superUser); err != nil {
+		t.Fatalf("Create returned error: %v", err)
+	}
+
+	current := totp.Step(time.Now())
+	code := func(step int64) string {
+		c, err := totp.Code(secret, step)
+		if err != nil {
+			t.Fatalf("Code returned error: %v", err)
+		}
+		return c
+	}
+
+	tests := []struct {
+		name  string
+		code  string
+		valid bool
+	}{
+		{"current code", code(current), true},
+		{"same code again", code(current), false},
+		{"earlier code within skew", code(current - 1), false},
+		{"later code within skew", code(current + 1), true},
+		{"later code again", code(current + 1), false},
+	}
+
+	for _, tt := range tests {
+		// Each attempt loads the record afresh, like a login does
+		loaded, err := repo.FindByID(ctx, superUser.ID)
+		if err != nil {
+			t.Fatalf("FindByID returned error: %v", err)
+		}
+		valid, err := consumeTOTPCode(ctx, repo, loaded, tt.code)
+		if err != nil {
+			t.Fatalf("%s: consumeTOTPCode returned error: %v", tt.name, err)
+		}
+		if valid != tt.valid {
+			t.Errorf("%s: valid = %v, want %v", tt.name, valid, tt.valid)
+		}
+	}
+}
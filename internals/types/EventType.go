@@ -6,6 +6,14 @@ import (
 	"github.com/google/uuid"
 )
 
+// Lifecycle statuses of an event
+const (
+	EventStatusDraft     = "draft"
+	EventStatusPublished = "published"
+	EventStatusCancelled = "cancelled"
+	EventStatusCompleted = "completed"
+)
+
 type EventType struct {
 	EventID     uuid.UUID   `bson:"_id,omitempty" json:"event_id,omitempty" gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
 	Name        string      `bson:"name" json:"name" validate:"required" gorm:"not null"`
@@ -17,4 +25,102 @@ type EventType struct {
 	UpdatedAt   time.Time   `bson:"updated_at" json:"updated_at" gorm:"autoUpdateTime"`
 	OrganizerID uuid.UUID   `bson:"organizer_id" json:"organizer_id" gorm:"type:uuid;not null"`
 	Attendees   []uuid.UUID `bson:"attendees" json:"attendees" gorm:"type:uuid[]"`
+
+	// Status is the lifecycle status, events stored before it existed count as published
+	Status string `bson:"status,omitempty" json:"status" gorm:"type:varchar(16);index"`
+
+	// Schedule. Date is the start and EndDate the exclusive end, both stored as instants.
+	// TimeZone is the IANA zone the event is planned in, all-day events start at midnight
+	// there and end at midnight after their last day.
+	EndDate  *time.Time `bson:"end_date,omitempty" json:"end_date,omitempty"`
+	TimeZone string     `bson:"time_zone,omitempty" json:"time_zone,omitempty" gorm:"type:varchar(64)"`
+	AllDay   bool       `bson:"all_day" json:"all_day" gorm:"not null;default:false"`
+
+	// ExternalID identifies an imported event in its source (iCal UID or CSV id), per organizer
+	ExternalID string `bson:"external_id,omitempty" json:"external_id,omitempty" gorm:"type:varchar(255);index:idx_event_external_id"`
+
+	// Recurrence (RFC 5545). A series master carries the RRULE and EXDATEs, Date is its DTSTART.
+	RecurrenceRule    string      `bson:"recurrence_rule,omitempty" json:"recurrence_rule,omitempty" gorm:"type:text"`
+	RecurrenceExDates []time.Time `bson:"recurrence_exdates,omitempty" json:"recurrence_exdates,omitempty" gorm:"type:jsonb;serializer:json"`
+
+	// An occurrence of a series points at its master through SeriesID, RecurrenceID is the
+	// originally scheduled start. Stored records with both set override that single occurrence.
+	SeriesID     *uuid.UUID `bson:"series_id,omitempty" json:"series_id,omitempty" gorm:"type:uuid;index"`
+	RecurrenceID *time.Time `bson:"recurrence_id,omitempty" json:"recurrence_id,omitempty"`
+}
+
+// Zone returns the time zone of the event, UTC when none or an unknown one is set
+func (e *EventType) Zone() *time.Location {
+	if e.TimeZone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(e.TimeZone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// End returns the end of the event, its start when it has no end
+func (e *EventType) End() time.Time {
+	if e.EndDate == nil {
+		return e.Date
+	}
+	return *e.EndDate
+}
+
+// In returns a copy of the event with its times rendered in loc. All-day events keep
+// their own zone so their dates do not shift.
+func (e *EventType) In(loc *time.Location) *EventType {
+	rendered := *e
+	if e.AllDay || loc == nil {
+		loc = e.Zone()
+	}
+	rendered.Date = e.Date.In(loc)
+	if e.EndDate != nil {
+		end := e.EndDate.In(loc)
+		rendered.EndDate = &end
+	}
+	if e.RecurrenceID != nil {
+		recurrenceID := e.RecurrenceID.In(loc)
+		rendered.RecurrenceID = &recurrenceID
+	}
+	rendered.CreatedAt = e.CreatedAt.In(loc)
+	rendered.UpdatedAt = e.UpdatedAt.In(loc)
+	return &rendered
+}
+
+// LifecycleStatus returns the status of the event, published for events without one
+func (e *EventType) LifecycleStatus() string {
+	if e.Status == "" {
+		return EventStatusPublished
+	}
+	return e.Status
+}
+
+// IsPublished reports whether the event is visible to everyone and open for registration
+func (e *EventType) IsPublished() bool {
+	return e.LifecycleStatus() == EventStatusPublished
+}
+
+// IsVisibleTo reports whether a viewer may see the event. Published events are public, the
+// organizer also sees their own drafts, cancelled and completed events. uuid.Nil is anonymous.
+func (e *EventType) IsVisibleTo(viewerID uuid.UUID) bool {
+	return e.IsPublished() || (viewerID != uuid.Nil && e.OrganizerID == viewerID)
+}
+
+// IsClosed reports whether the event was cancelled or completed and can no longer change
+func (e *EventType) IsClosed() bool {
+	status := e.LifecycleStatus()
+	return status == EventStatusCancelled || status == EventStatusCompleted
+}
+
+// IsRecurring reports whether the event is the master of a recurring series
+func (e *EventType) IsRecurring() bool {
+	return e.RecurrenceRule != ""
+}
+
+// IsOccurrenceOverride reports whether the event replaces a single occurrence of a series
+func (e *EventType) IsOccurrenceOverride() bool {
+	return e.SeriesID != nil && e.RecurrenceID != nil
 }
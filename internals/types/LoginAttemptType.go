@@ -0,0 +1,11 @@
+package types
+
+import "time"
+
+// LoginAttemptType counts the failed logins of one key, an account or a client IP
+type LoginAttemptType struct {
+	Key          string    `bson:"_id" json:"key"`
+	Failures     int       `bson:"failures" json:"failures"`
+	LastFailure  time.Time `bson:"last_failure" json:"last_failure"`
+	BlockedUntil time.Time `bson:"blocked_until" json:"blocked_until"`
+}
@@ -0,0 +1,20 @@
+package types
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RefreshTokenType is a long-lived token traded for a new access token. Each refresh rotates
+// it into a new token of the same family, only the hash of the token is stored.
+type RefreshTokenType struct {
+	ID          uuid.UUID  `bson:"_id,omitempty" json:"id" gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	FamilyID    uuid.UUID  `bson:"family_id" json:"family_id" gorm:"type:uuid;not null;index"`
+	SuperUserID uuid.UUID  `bson:"superuser_id" json:"superuser_id" gorm:"type:uuid;not null;index"`
+	TokenHash   string     `bson:"token_hash" json:"-" gorm:"type:text;not null;uniqueIndex"`
+	CreatedAt   time.Time  `bson:"created_at" json:"created_at" gorm:"not null"`
+	ExpiresAt   time.Time  `bson:"expires_at" json:"expires_at" gorm:"not null"`
+	RotatedAt   *time.Time `bson:"rotated_at" json:"rotated_at,omitempty"`
+	RevokedAt   *time.Time `bson:"revoked_at" json:"revoked_at,omitempty"`
+}
@@ -0,0 +1,21 @@
+package types
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SessionType is a login of a SuperUser from a device. Its ID is also the family of its refresh
+// tokens, refreshing keeps it alive and revoking it rejects its access and refresh tokens.
+type SessionType struct {
+	ID          uuid.UUID  `bson:"_id,omitempty" json:"id" gorm:"type:uuid;primaryKey"`
+	SuperUserID uuid.UUID  `bson:"superuser_id" json:"superuser_id" gorm:"type:uuid;not null;index"`
+	UserAgent   string     `bson:"user_agent" json:"user_agent" gorm:"type:text"`
+	IPAddress   string     `bson:"ip_address" json:"ip_address" gorm:"type:text"`
+	CreatedAt   time.Time  `bson:"created_at" json:"created_at" gorm:"not null"`
+	LastSeenAt  time.Time  `bson:"last_seen_at" json:"last_seen_at" gorm:"not null"`
+	ExpiresAt   time.Time  `bson:"expires_at" json:"expires_at" gorm:"not null"`
+	RevokedAt   *time.Time `bson:"revoked_at" json:"revoked_at,omitempty"`
+	Current     bool       `bson:"-" json:"current" gorm:"-"`
+}
@@ -0,0 +1,16 @@
+package types
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WaitlistEntryType is a place in an event's waitlist, ordered by CreatedAt
+type WaitlistEntryType struct {
+	ID        uuid.UUID `bson:"_id,omitempty" json:"id,omitempty" gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	EventID   uuid.UUID `bson:"event_id" json:"event_id" gorm:"type:uuid;not null;uniqueIndex:idx_waitlist_event_user;index:idx_waitlist_event_order,priority:1"`
+	UserID    uuid.UUID `bson:"user_id" json:"user_id" gorm:"type:uuid;not null;uniqueIndex:idx_waitlist_event_user"`
+	CreatedAt time.Time `bson:"created_at" json:"created_at" gorm:"not null;index:idx_waitlist_event_order,priority:2"`
+	Position  int       `bson:"-" json:"position,omitempty" gorm:"-"`
+}
@@ -1,11 +1,19 @@
 package main
 
-import "github.com/lordofthemind/EventifyGo/cmd"
+import (
+	"log"
+	"os"
 
-// func main() {
-// 	cmd.FiberServer()
-// }
+	"github.com/lordofthemind/EventifyGo/cmd"
+
+	// Embed the IANA time zone database so event time zones resolve on any host
+	_ "time/tzdata"
+)
 
 func main() {
-	cmd.GinServer()
+	// serve, seed, migrate and superuser share the configuration from config.yaml, then
+	// EVENTIFY_* variables, then flags
+	if err := cmd.Execute(os.Args[1:]); err != nil {
+		log.Fatal(err)
+	}
 }
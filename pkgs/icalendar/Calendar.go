@@ -0,0 +1,310 @@
+package icalendar
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// dateTimeFormat is the RFC 5545 UTC DATE-TIME form
+	dateTimeFormat = "20060102T150405Z"
+
+	// localDateTimeFormat and dateFormat are the floating DATE-TIME and the DATE forms
+	localDateTimeFormat = "20060102T150405"
+	dateFormat          = "20060102"
+
+	// maxLineOctets is the longest content line allowed before folding
+	maxLineOctets = 75
+)
+
+// Calendar is a VCALENDAR object
+type Calendar struct {
+	ProdID string
+	Name   string
+	Events []Event
+}
+
+// Event is a VEVENT component
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Location    string
+	Start       time.Time
+	End         time.Time
+	AllDay      bool
+	// TimeZone is the zone Start and End are written in, UTC when nil
+	TimeZone     *time.Location
+	Organizer    *Organizer
+	RRule        string
+	ExDates      []time.Time
+	RecurrenceID *time.Time
+	Status       string
+	Created      time.Time
+	LastModified time.Time
+
+	// Line is the line of BEGIN:VEVENT and Err why the component could not be
+	// decoded. Both are only set by Decode.
+	Line int
+	Err  error
+}
+
+// Organizer is the ORGANIZER of an event
+type Organizer struct {
+	Name  string
+	Email string
+}
+
+// Encode writes the calendar as an RFC 5545 iCalendar stream
+func (c *Calendar) Encode(w io.Writer) error {
+	enc := &encoder{w: w}
+	stamp := time.Now()
+
+	enc.line("BEGIN", "VCALENDAR")
+	enc.line("VERSION", "2.0")
+	enc.line("PRODID", c.ProdID)
+	enc.line("CALSCALE", "GREGORIAN")
+	enc.line("METHOD", "PUBLISH")
+	if c.Name != "" {
+		enc.line("X-WR-CALNAME", EscapeText(c.Name))
+	}
+
+	for _, zone := range c.timeZones(stamp) {
+		enc.timeZone(zone.loc, zone.from, zone.to)
+	}
+
+	for _, event := range c.Events {
+		enc.line("BEGIN", "VEVENT")
+		enc.line("UID", event.UID)
+		enc.line("DTSTAMP", formatDateTime(stamp))
+		enc.dateTime("DTSTART", event.Start, &event)
+		if !event.End.IsZero() {
+			enc.dateTime("DTEND", event.End, &event)
+		}
+		if event.RecurrenceID != nil {
+			enc.dateTime("RECURRENCE-ID", *event.RecurrenceID, &event)
+		}
+		if event.RRule != "" {
+			enc.line("RRULE", event.RRule)
+		}
+		for _, exdate := range event.ExDates {
+			enc.dateTime("EXDATE", exdate, &event)
+		}
+		enc.line("SUMMARY", EscapeText(event.Summary))
+		if event.Description != "" {
+			enc.line("DESCRIPTION", EscapeText(event.Description))
+		}
+		if event.Location != "" {
+			enc.line("LOCATION", EscapeText(event.Location))
+		}
+		if event.Organizer != nil && event.Organizer.Email != "" {
+			name := ""
+			if event.Organizer.Name != "" {
+				name = ";CN=" + quoteParam(event.Organizer.Name)
+			}
+			enc.line("ORGANIZER"+name, "mailto:"+event.Organizer.Email)
+		}
+		if event.Status != "" {
+			enc.line("STATUS", event.Status)
+		}
+		if !event.Created.IsZero() {
+			enc.line("CREATED", formatDateTime(event.Created))
+		}
+		if !event.LastModified.IsZero() {
+			enc.line("LAST-MODIFIED", formatDateTime(event.LastModified))
+		}
+		enc.line("END", "VEVENT")
+	}
+
+	enc.line("END", "VCALENDAR")
+	return enc.err
+}
+
+// Marshal returns the calendar as an RFC 5545 iCalendar document
+func (c *Calendar) Marshal() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := c.Encode(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// EscapeText escapes a TEXT value as described in RFC 5545 section 3.3.11
+func EscapeText(value string) string {
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", `\n`,
+	)
+	return replacer.Replace(value)
+}
+
+// quoteParam quotes a parameter value when it contains characters that need it
+func quoteParam(value string) string {
+	value = strings.ReplaceAll(value, `"`, "'")
+	if strings.ContainsAny(value, ":;,") {
+		return `"` + value + `"`
+	}
+	return value
+}
+
+func formatDateTime(t time.Time) string {
+	return t.UTC().Format(dateTimeFormat)
+}
+
+// zoned reports whether the times of an event are written with a TZID
+func (e *Event) zoned() bool {
+	return !e.AllDay && e.TimeZone != nil && e.TimeZone.String() != "UTC"
+}
+
+// zoneRange is a time zone used by a calendar and the years it has to describe
+type zoneRange struct {
+	loc      *time.Location
+	from, to int
+}
+
+// timeZones lists the zones referenced through TZID, covering the years of their events
+// and the following ten so open-ended series resolve correctly
+func (c *Calendar) timeZones(now time.Time) []zoneRange {
+	var zones []zoneRange
+	index := make(map[string]int)
+	for _, event := range c.Events {
+		if !event.zoned() {
+			continue
+		}
+		name := event.TimeZone.String()
+		i, seen := index[name]
+		if !seen {
+			i = len(zones)
+			index[name] = i
+			zones = append(zones, zoneRange{loc: event.TimeZone, from: event.Start.Year(), to: now.Year()})
+		}
+		if year := event.Start.Year(); year < zones[i].from {
+			zones[i].from = year
+		}
+		if year := event.Start.Year(); year > zones[i].to {
+			zones[i].to = year
+		}
+	}
+	for i := range zones {
+		zones[i].to += 10
+	}
+	return zones
+}
+
+// encoder writes folded CRLF content lines and remembers the first error
+type encoder struct {
+	w   io.Writer
+	err error
+}
+
+// dateTime writes a DATE-TIME property of an event as a DATE for all-day events,
+// as local time with a TZID for zoned events and in UTC otherwise
+func (e *encoder) dateTime(name string, t time.Time, event *Event) {
+	switch {
+	case event.AllDay:
+		loc := event.TimeZone
+		if loc == nil {
+			loc = time.UTC
+		}
+		e.line(name+";VALUE=DATE", t.In(loc).Format(dateFormat))
+	case event.zoned():
+		e.line(name+";TZID="+quoteParam(event.TimeZone.String()), t.In(event.TimeZone).Format(localDateTimeFormat))
+	default:
+		e.line(name, formatDateTime(t))
+	}
+}
+
+// timeZone writes a VTIMEZONE with one observance per offset change between the
+// start of year from and the end of year to, taken from the Go time zone database
+func (e *encoder) timeZone(loc *time.Location, from, to int) {
+	start := time.Date(from, 1, 1, 0, 0, 0, 0, loc)
+	end := time.Date(to+1, 1, 1, 0, 0, 0, 0, loc)
+
+	e.line("BEGIN", "VTIMEZONE")
+	e.line("TZID", loc.String())
+
+	// The observance in force at the start of the range
+	_, offset := start.Zone()
+	e.observance(start, offset)
+
+	for t := start; ; {
+		_, next := t.ZoneBounds()
+		if next.IsZero() || !next.Before(end) {
+			break
+		}
+		e.observance(next, offset)
+		_, offset = next.Zone()
+		t = next
+	}
+
+	e.line("END", "VTIMEZONE")
+}
+
+// observance writes the STANDARD or DAYLIGHT component that starts at t
+func (e *encoder) observance(t time.Time, offsetFrom int) {
+	kind := "STANDARD"
+	if t.IsDST() {
+		kind = "DAYLIGHT"
+	}
+	name, offsetTo := t.Zone()
+
+	e.line("BEGIN", kind)
+	// DTSTART is the local time according to the offset before the change
+	e.line("DTSTART", t.UTC().Add(time.Duration(offsetFrom)*time.Second).Format(localDateTimeFormat))
+	e.line("TZOFFSETFROM", formatOffset(offsetFrom))
+	e.line("TZOFFSETTO", formatOffset(offsetTo))
+	if name != "" {
+		e.line("TZNAME", EscapeText(name))
+	}
+	e.line("END", kind)
+}
+
+// formatOffset formats a UTC offset in seconds as RFC 5545 UTC-OFFSET
+func formatOffset(seconds int) string {
+	sign := "+"
+	if seconds < 0 {
+		sign = "-"
+		seconds = -seconds
+	}
+	formatted := fmt.Sprintf("%s%02d%02d", sign, seconds/3600, seconds/60%60)
+	if seconds%60 != 0 {
+		formatted += fmt.Sprintf("%02d", seconds%60)
+	}
+	return formatted
+}
+
+func (e *encoder) line(name, value string) {
+	if e.err != nil {
+		return
+	}
+	_, e.err = io.WriteString(e.w, fold(name+":"+value))
+}
+
+// fold splits a content line into chunks of at most 75 octets without breaking
+// UTF-8 sequences, continuation lines start with a single space
+func fold(line string) string {
+	var b strings.Builder
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		// The leading space counts towards the next line
+		limit = maxLineOctets - 1
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+	return b.String()
+}
@@ -0,0 +1,234 @@
+package icalendar
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestEscapeTextRoundTrip(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		escaped string
+	}{
+		{"plain", "Team meeting", "Team meeting"},
+		{"comma and semicolon", "Room 1, Floor 2; East", `Room 1\, Floor 2\; East`},
+		{"backslash", `C:\events`, `C:\\events`},
+		{"newline", "first\nsecond", `first\nsecond`},
+		{"escape sequence in text", `a\nb`, `a\\nb`},
+		{"colon is not escaped", "Time: 10:00", "Time: 10:00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			escaped := EscapeText(tt.value)
+			if escaped != tt.escaped {
+				t.Errorf("EscapeText(%q) = %q, want %q", tt.value, escaped, tt.escaped)
+			}
+			if got := UnescapeText(escaped); got != tt.value {
+				t.Errorf("UnescapeText(%q) = %q, want %q", escaped, got, tt.value)
+			}
+		})
+	}
+}
+
+func TestEscapeTextNormalizesLineEndings(t *testing.T) {
+	if got := UnescapeText(EscapeText("a\r\nb\rc")); got != "a\nb\nc" {
+		t.Errorf("round trip = %q, want %q", got, "a\nb\nc")
+	}
+}
+
+func TestFold(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+	}{
+		{"short", "SUMMARY:short"},
+		{"exactly 75 octets", "SUMMARY:" + strings.Repeat("a", 67)},
+		{"76 octets", "SUMMARY:" + strings.Repeat("a", 68)},
+		{"long ascii", "DESCRIPTION:" + strings.Repeat("abcdefghij", 30)},
+		{"multi byte", "SUMMARY:" + strings.Repeat("äöü€日本", 40)},
+		{"four byte runes", "SUMMARY:" + strings.Repeat("🎉", 50)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			folded := fold(tt.line)
+			if !strings.HasSuffix(folded, "\r\n") {
+				t.Fatalf("folded line does not end with CRLF: %q", folded)
+			}
+
+			physical := strings.Split(strings.TrimSuffix(folded, "\r\n"), "\r\n")
+			for i, line := range physical {
+				if len(line) > maxLineOctets {
+					t.Errorf("line %d is %d octets, longer than %d", i, len(line), maxLineOctets)
+				}
+				if !utf8.ValidString(line) {
+					t.Errorf("line %d splits a UTF-8 sequence: %q", i, line)
+				}
+				if i > 0 && !strings.HasPrefix(line, " ") {
+					t.Errorf("continuation line %d does not start with a space", i)
+				}
+			}
+			if len(tt.line) <= maxLineOctets && len(physical) != 1 {
+				t.Errorf("a line of %d octets was folded into %d lines", len(tt.line), len(physical))
+			}
+
+			lines, err := unfold(strings.NewReader(folded))
+			if err != nil {
+				t.Fatalf("unfold returned error: %v", err)
+			}
+			if len(lines) != 1 || lines[0].text != tt.line {
+				t.Errorf("unfold did not restore the line, got %+v", lines)
+			}
+		})
+	}
+}
+
+func TestMarshalRoundTrip(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("time zone database not available: %v", err)
+	}
+	recurrenceID := time.Date(2024, 3, 26, 9, 0, 0, 0, berlin)
+
+	calendar := &Calendar{
+		ProdID: "-//EventifyGo//Test//EN",
+		Name:   "Team, events; and more",
+		Events: []Event{
+			{
+				UID:         "utc@example.com",
+				Summary:     "Kick-off, part 1; " + strings.Repeat("long summary ", 10),
+				Description: "Line one\nLine two with a \\ backslash",
+				Location:    "Room 1, Floor 2",
+				Start:       time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC),
+				End:         time.Date(2024, 3, 1, 11, 30, 0, 0, time.UTC),
+				Organizer:   &Organizer{Name: "Doe, Jane", Email: "jane@example.com"},
+				Status:      "CONFIRMED",
+			},
+			{
+				UID:      "series@example.com",
+				Summary:  "Stand-up",
+				Start:    time.Date(2024, 3, 25, 9, 0, 0, 0, berlin),
+				End:      time.Date(2024, 3, 25, 9, 15, 0, 0, berlin),
+				TimeZone: berlin,
+				RRule:    "FREQ=DAILY;COUNT=10",
+				ExDates:  []time.Time{time.Date(2024, 3, 27, 9, 0, 0, 0, berlin)},
+			},
+			{
+				UID:          "series@example.com",
+				Summary:      "Stand-up, moved",
+				Start:        time.Date(2024, 3, 26, 10, 0, 0, 0, berlin),
+				End:          time.Date(2024, 3, 26, 10, 15, 0, 0, berlin),
+				TimeZone:     berlin,
+				RecurrenceID: &recurrenceID,
+			},
+			{
+				UID:     "allday@example.com",
+				Summary: "Holiday",
+				Start:   time.Date(2024, 12, 25, 0, 0, 0, 0, time.UTC),
+				End:     time.Date(2024, 12, 26, 0, 0, 0, 0, time.UTC),
+				AllDay:  true,
+			},
+		},
+	}
+
+	data, err := calendar.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal returned error: %v", err)
+	}
+	for i, line := range strings.Split(string(data), "\r\n") {
+		if len(line) > maxLineOctets {
+			t.Errorf("line %d is %d octets: %q", i, len(line), line)
+		}
+	}
+
+	decoded, err := Unmarshal(data)
+	if err != nil {
+		t.Fatalf("Unmarshal returned error: %v", err)
+	}
+	if decoded.ProdID != calendar.ProdID || decoded.Name != calendar.Name {
+		t.Errorf("calendar = %q %q, want %q %q", decoded.ProdID, decoded.Name, calendar.ProdID, calendar.Name)
+	}
+	if len(decoded.Events) != len(calendar.Events) {
+		t.Fatalf("decoded %d events, want %d", len(decoded.Events), len(calendar.Events))
+	}
+
+	for i, want := range calendar.Events {
+		got := decoded.Events[i]
+		if got.Err != nil {
+			t.Errorf("event %d: decode error %v", i, got.Err)
+			continue
+		}
+		if got.UID != want.UID || got.Summary != want.Summary || got.Description != want.Description || got.Location != want.Location {
+			t.Errorf("event %d: text = %q %q %q %q, want %q %q %q %q", i,
+				got.UID, got.Summary, got.Description, got.Location,
+				want.UID, want.Summary, want.Description, want.Location)
+		}
+		if !got.Start.Equal(want.Start) || !got.End.Equal(want.End) {
+			t.Errorf("event %d: times = %s - %s, want %s - %s", i, got.Start, got.End, want.Start, want.End)
+		}
+		if got.AllDay != want.AllDay || got.RRule != want.RRule || got.Status != want.Status {
+			t.Errorf("event %d: all day %v rule %q status %q, want %v %q %q", i, got.AllDay, got.RRule, got.Status, want.AllDay, want.RRule, want.Status)
+		}
+		if want.TimeZone != nil && (got.TimeZone == nil || got.TimeZone.String() != want.TimeZone.String()) {
+			t.Errorf("event %d: time zone = %v, want %v", i, got.TimeZone, want.TimeZone)
+		}
+		if len(got.ExDates) != len(want.ExDates) {
+			t.Errorf("event %d: %d EXDATEs, want %d", i, len(got.ExDates), len(want.ExDates))
+		} else {
+			for j := range want.ExDates {
+				if !got.ExDates[j].Equal(want.ExDates[j]) {
+					t.Errorf("event %d: EXDATE %d = %s, want %s", i, j, got.ExDates[j], want.ExDates[j])
+				}
+			}
+		}
+		if (got.RecurrenceID == nil) != (want.RecurrenceID == nil) ||
+			(want.RecurrenceID != nil && !got.RecurrenceID.Equal(*want.RecurrenceID)) {
+			t.Errorf("event %d: RECURRENCE-ID = %v, want %v", i, got.RecurrenceID, want.RecurrenceID)
+		}
+		if (got.Organizer == nil) != (want.Organizer == nil) ||
+			(want.Organizer != nil && *got.Organizer != *want.Organizer) {
+			t.Errorf("event %d: organizer = %+v, want %+v", i, got.Organizer, want.Organizer)
+		}
+	}
+}
+
+func TestDecodeLenientInput(t *testing.T) {
+	input := "\ufeffBEGIN:VCALENDAR\n" +
+		"VERSION:2.0\n" +
+		"BEGIN:VEVENT\n" +
+		"UID:lf@example.com\n" +
+		"DTSTART:20240301T100000Z\n" +
+		"SUMMARY:Folded with a tab\n" +
+		"\t and LF line endings\n" +
+		"BEGIN:VALARM\n" +
+		"SUMMARY:ignored alarm\n" +
+		"END:VALARM\n" +
+		"END:VEVENT\n" +
+		"BEGIN:VEVENT\n" +
+		"UID:broken@example.com\n" +
+		"SUMMARY:No start\n" +
+		"END:VEVENT\n" +
+		"END:VCALENDAR\n"
+
+	calendar, err := Unmarshal([]byte(input))
+	if err != nil {
+		t.Fatalf("Unmarshal returned error: %v", err)
+	}
+	if len(calendar.Events) != 2 {
+		t.Fatalf("decoded %d events, want 2", len(calendar.Events))
+	}
+	if got := calendar.Events[0].Summary; got != "Folded with a tab and LF line endings" {
+		t.Errorf("summary = %q", got)
+	}
+	if calendar.Events[1].Err == nil {
+		t.Errorf("event without DTSTART decoded without error")
+	}
+
+	if _, err := Unmarshal([]byte("BEGIN:VEVENT\nEND:VEVENT\n")); err != ErrNoCalendar {
+		t.Errorf("Unmarshal without VCALENDAR error = %v, want %v", err, ErrNoCalendar)
+	}
+}
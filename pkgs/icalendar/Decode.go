@@ -0,0 +1,290 @@
+package icalendar
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// maxLineBytes bounds a single unfolded content line
+const maxLineBytes = 1 << 20
+
+// ErrNoCalendar is returned when the stream holds no VCALENDAR object
+var ErrNoCalendar = errors.New("icalendar: no VCALENDAR object found")
+
+// property is a decoded content line
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// Decode reads an RFC 5545 iCalendar stream. Structural problems fail the
+// whole stream, a VEVENT with an unreadable property is returned with Err set
+// so callers can report it and carry on with the rest.
+func Decode(r io.Reader) (*Calendar, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	calendar := &Calendar{}
+	var (
+		inCalendar bool
+		found      bool
+		event      *Event
+		// depth counts components nested in a VEVENT, such as VALARM
+		depth int
+	)
+
+	for _, l := range lines {
+		prop, err := parseProperty(l.text)
+		if err != nil {
+			if event != nil && event.Err == nil {
+				event.Err = fmt.Errorf("line %d: %w", l.number, err)
+			}
+			continue
+		}
+
+		switch {
+		case prop.name == "BEGIN" && strings.EqualFold(prop.value, "VCALENDAR") && !inCalendar:
+			inCalendar = true
+			found = true
+		case !inCalendar:
+			continue
+		case prop.name == "BEGIN" && event == nil && strings.EqualFold(prop.value, "VEVENT"):
+			event = &Event{Line: l.number}
+		case prop.name == "BEGIN" && event != nil:
+			depth++
+		case prop.name == "END" && event != nil && depth > 0:
+			depth--
+		case prop.name == "END" && event != nil && strings.EqualFold(prop.value, "VEVENT"):
+			if event.Err == nil && event.Start.IsZero() {
+				event.Err = errors.New("DTSTART is required")
+			}
+			calendar.Events = append(calendar.Events, *event)
+			event = nil
+		case prop.name == "END" && strings.EqualFold(prop.value, "VCALENDAR"):
+			if event != nil {
+				return nil, fmt.Errorf("icalendar: line %d: VEVENT started on line %d is not terminated", l.number, event.Line)
+			}
+			inCalendar = false
+		case event != nil && depth == 0:
+			if err := event.set(prop); err != nil && event.Err == nil {
+				event.Err = fmt.Errorf("line %d: %s: %w", l.number, prop.name, err)
+			}
+		case event == nil:
+			calendar.set(prop)
+		}
+	}
+
+	if !found {
+		return nil, ErrNoCalendar
+	}
+	if inCalendar {
+		return nil, errors.New("icalendar: VCALENDAR is not terminated")
+	}
+	return calendar, nil
+}
+
+// Unmarshal decodes an iCalendar document
+func Unmarshal(data []byte) (*Calendar, error) {
+	return Decode(bytes.NewReader(data))
+}
+
+func (c *Calendar) set(prop property) {
+	switch prop.name {
+	case "PRODID":
+		c.ProdID = prop.value
+	case "X-WR-CALNAME":
+		c.Name = UnescapeText(prop.value)
+	}
+}
+
+func (e *Event) set(prop property) error {
+	var err error
+	switch prop.name {
+	case "UID":
+		e.UID = prop.value
+	case "SUMMARY":
+		e.Summary = UnescapeText(prop.value)
+	case "DESCRIPTION":
+		e.Description = UnescapeText(prop.value)
+	case "LOCATION":
+		e.Location = UnescapeText(prop.value)
+	case "STATUS":
+		e.Status = strings.ToUpper(prop.value)
+	case "RRULE":
+		e.RRule = prop.value
+	case "DTSTART":
+		e.Start, err = parseDateTime(prop.value, prop.params)
+		e.AllDay = prop.params["VALUE"] == "DATE" || len(strings.TrimSpace(prop.value)) == len(dateFormat)
+		if tzid := prop.params["TZID"]; tzid != "" && err == nil {
+			e.TimeZone = e.Start.Location()
+		}
+	case "DTEND":
+		e.End, err = parseDateTime(prop.value, prop.params)
+	case "RECURRENCE-ID":
+		var t time.Time
+		if t, err = parseDateTime(prop.value, prop.params); err == nil {
+			e.RecurrenceID = &t
+		}
+	case "EXDATE":
+		for _, value := range strings.Split(prop.value, ",") {
+			t, perr := parseDateTime(value, prop.params)
+			if perr != nil {
+				return perr
+			}
+			e.ExDates = append(e.ExDates, t)
+		}
+	case "CREATED":
+		e.Created, err = parseDateTime(prop.value, prop.params)
+	case "LAST-MODIFIED":
+		e.LastModified, err = parseDateTime(prop.value, prop.params)
+	case "ORGANIZER":
+		e.Organizer = &Organizer{
+			Name:  prop.params["CN"],
+			Email: strings.TrimPrefix(strings.TrimPrefix(prop.value, "mailto:"), "MAILTO:"),
+		}
+	}
+	return err
+}
+
+// UnescapeText reverses EscapeText
+func UnescapeText(value string) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] != '\\' || i == len(value)-1 {
+			b.WriteByte(value[i])
+			continue
+		}
+		i++
+		switch value[i] {
+		case 'n', 'N':
+			b.WriteByte('\n')
+		default:
+			b.WriteByte(value[i])
+		}
+	}
+	return b.String()
+}
+
+// parseDateTime reads a DATE or DATE-TIME value. UTC and TZID times are
+// honoured, floating times and dates are taken as UTC.
+func parseDateTime(value string, params map[string]string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if params["VALUE"] == "DATE" || len(value) == len(dateFormat) {
+		return time.Parse(dateFormat, value)
+	}
+	if strings.HasSuffix(value, "Z") {
+		return time.Parse(dateTimeFormat, value)
+	}
+
+	location := time.UTC
+	if tzid := params["TZID"]; tzid != "" {
+		loc, err := time.LoadLocation(tzid)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("unknown TZID %q", tzid)
+		}
+		location = loc
+	}
+	return time.ParseInLocation(localDateTimeFormat, value, location)
+}
+
+// parseProperty splits a content line into its name, parameters and value
+func parseProperty(line string) (property, error) {
+	prop := property{params: map[string]string{}}
+
+	// The value starts at the first colon outside a quoted parameter value
+	quoted := false
+	colon := -1
+	for i := 0; i < len(line) && colon < 0; i++ {
+		switch line[i] {
+		case '"':
+			quoted = !quoted
+		case ':':
+			if !quoted {
+				colon = i
+			}
+		}
+	}
+	if colon < 0 {
+		return prop, fmt.Errorf("malformed content line %q", line)
+	}
+	prop.value = line[colon+1:]
+
+	parts := splitParams(line[:colon])
+	prop.name = strings.ToUpper(strings.TrimSpace(parts[0]))
+	if prop.name == "" {
+		return prop, fmt.Errorf("malformed content line %q", line)
+	}
+	for _, param := range parts[1:] {
+		key, value, ok := strings.Cut(param, "=")
+		if !ok {
+			return prop, fmt.Errorf("malformed parameter %q", param)
+		}
+		prop.params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+	}
+	return prop, nil
+}
+
+// splitParams splits "NAME;A=1;B="x;y"" on the semicolons outside quotes
+func splitParams(s string) []string {
+	var parts []string
+	quoted := false
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"':
+			quoted = !quoted
+		case ';':
+			if !quoted {
+				parts = append(parts, s[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// contentLine is an unfolded line with the number of its first physical line
+type contentLine struct {
+	number int
+	text   string
+}
+
+// unfold joins folded lines back together, accepting both CRLF and LF endings
+func unfold(r io.Reader) ([]contentLine, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineBytes)
+
+	var lines []contentLine
+	number := 0
+	for scanner.Scan() {
+		number++
+		text := strings.TrimSuffix(scanner.Text(), "\r")
+		if number == 1 {
+			text = strings.TrimPrefix(text, "\ufeff")
+		}
+		if (strings.HasPrefix(text, " ") || strings.HasPrefix(text, "\t")) && len(lines) > 0 {
+			last := &lines[len(lines)-1]
+			if len(last.text)+len(text) > maxLineBytes {
+				return nil, fmt.Errorf("icalendar: line %d: content line too long", number)
+			}
+			last.text += text[1:]
+			continue
+		}
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		lines = append(lines, contentLine{number: number, text: text})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("icalendar: %w", err)
+	}
+	return lines, nil
+}
@@ -0,0 +1,301 @@
+package jsonpatch
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// The examples of RFC 6902 Appendix A, A.13 is left out as Go's decoder keeps the last of
+// duplicate members
+func TestApplyRFC6902Examples(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     string
+		patch   string
+		want    string
+		wantErr error
+	}{
+		{
+			name:  "A.1 adding an object member",
+			doc:   `{"foo": "bar"}`,
+			patch: `[{"op": "add", "path": "/baz", "value": "qux"}]`,
+			want:  `{"baz": "qux", "foo": "bar"}`,
+		},
+		{
+			name:  "A.2 adding an array element",
+			doc:   `{"foo": ["bar", "baz"]}`,
+			patch: `[{"op": "add", "path": "/foo/1", "value": "qux"}]`,
+			want:  `{"foo": ["bar", "qux", "baz"]}`,
+		},
+		{
+			name:  "A.3 removing an object member",
+			doc:   `{"baz": "qux", "foo": "bar"}`,
+			patch: `[{"op": "remove", "path": "/baz"}]`,
+			want:  `{"foo": "bar"}`,
+		},
+		{
+			name:  "A.4 removing an array element",
+			doc:   `{"foo": ["bar", "qux", "baz"]}`,
+			patch: `[{"op": "remove", "path": "/foo/1"}]`,
+			want:  `{"foo": ["bar", "baz"]}`,
+		},
+		{
+			name:  "A.5 replacing a value",
+			doc:   `{"baz": "qux", "foo": "bar"}`,
+			patch: `[{"op": "replace", "path": "/baz", "value": "boo"}]`,
+			want:  `{"baz": "boo", "foo": "bar"}`,
+		},
+		{
+			name:  "A.6 moving a value",
+			doc:   `{"foo": {"bar": "baz", "waldo": "fred"}, "qux": {"corge": "grault"}}`,
+			patch: `[{"op": "move", "from": "/foo/waldo", "path": "/qux/thud"}]`,
+			want:  `{"foo": {"bar": "baz"}, "qux": {"corge": "grault", "thud": "fred"}}`,
+		},
+		{
+			name:  "A.7 moving an array element",
+			doc:   `{"foo": ["all", "grass", "cows", "eat"]}`,
+			patch: `[{"op": "move", "from": "/foo/1", "path": "/foo/3"}]`,
+			want:  `{"foo": ["all", "cows", "eat", "grass"]}`,
+		},
+		{
+			name: "A.8 testing a value, success",
+			doc:  `{"baz": "qux", "foo": ["a", 2, "c"]}`,
+			patch: `[
+				{"op": "test", "path": "/baz", "value": "qux"},
+				{"op": "test", "path": "/foo/1", "value": 2}
+			]`,
+			want: `{"baz": "qux", "foo": ["a", 2, "c"]}`,
+		},
+		{
+			name:    "A.9 testing a value, error",
+			doc:     `{"baz": "qux"}`,
+			patch:   `[{"op": "test", "path": "/baz", "value": "bar"}]`,
+			wantErr: ErrTestFailed,
+		},
+		{
+			name:  "A.10 adding a nested member object",
+			doc:   `{"foo": "bar"}`,
+			patch: `[{"op": "add", "path": "/child", "value": {"grandchild": {}}}]`,
+			want:  `{"foo": "bar", "child": {"grandchild": {}}}`,
+		},
+		{
+			name:  "A.11 ignoring unrecognized elements",
+			doc:   `{"foo": "bar"}`,
+			patch: `[{"op": "add", "path": "/baz", "value": "qux", "xyz": 123}]`,
+			want:  `{"foo": "bar", "baz": "qux"}`,
+		},
+		{
+			name:    "A.12 adding to a nonexistent target",
+			doc:     `{"foo": "bar"}`,
+			patch:   `[{"op": "add", "path": "/baz/bat", "value": "qux"}]`,
+			wantErr: ErrPathNotFound,
+		},
+		{
+			name: "A.14 ~ escape ordering",
+			doc:  `{"/": 9, "~1": 10}`,
+			patch: `[
+				{"op": "test", "path": "/~01", "value": 10}
+			]`,
+			want: `{"/": 9, "~1": 10}`,
+		},
+		{
+			name:    "A.15 comparing strings and numbers",
+			doc:     `{"/": 9, "~1": 10}`,
+			patch:   `[{"op": "test", "path": "/~01", "value": "10"}]`,
+			wantErr: ErrTestFailed,
+		},
+		{
+			name:  "A.16 adding an array value",
+			doc:   `{"foo": ["bar"]}`,
+			patch: `[{"op": "add", "path": "/foo/-", "value": ["abc", "def"]}]`,
+			want:  `{"foo": ["bar", ["abc", "def"]]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertApply(t, tt.doc, tt.patch, tt.want, tt.wantErr)
+		})
+	}
+}
+
+func TestApplyPointersAndIndexes(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     string
+		patch   string
+		want    string
+		wantErr error
+	}{
+		{
+			name:  "dash appends to an array",
+			doc:   `{"foo": [1, 2]}`,
+			patch: `[{"op": "add", "path": "/foo/-", "value": 3}]`,
+			want:  `{"foo": [1, 2, 3]}`,
+		},
+		{
+			name:  "index equal to the length appends",
+			doc:   `{"foo": [1, 2]}`,
+			patch: `[{"op": "add", "path": "/foo/2", "value": 3}]`,
+			want:  `{"foo": [1, 2, 3]}`,
+		},
+		{
+			name:    "dash does not name an element to remove",
+			doc:     `{"foo": [1, 2]}`,
+			patch:   `[{"op": "remove", "path": "/foo/-"}]`,
+			wantErr: ErrPathNotFound,
+		},
+		{
+			name:    "index past the end",
+			doc:     `{"foo": [1, 2]}`,
+			patch:   `[{"op": "add", "path": "/foo/3", "value": 3}]`,
+			wantErr: ErrPathNotFound,
+		},
+		{
+			name:    "leading zero index",
+			doc:     `{"foo": [1, 2]}`,
+			patch:   `[{"op": "replace", "path": "/foo/01", "value": 3}]`,
+			wantErr: ErrPathNotFound,
+		},
+		{
+			name:    "negative index",
+			doc:     `{"foo": [1, 2]}`,
+			patch:   `[{"op": "remove", "path": "/foo/-1"}]`,
+			wantErr: ErrPathNotFound,
+		},
+		{
+			name:    "pointer without leading slash",
+			doc:     `{"foo": "bar"}`,
+			patch:   `[{"op": "remove", "path": "foo"}]`,
+			wantErr: ErrInvalidPatch,
+		},
+		{
+			name:    "missing path",
+			doc:     `{"foo": "bar"}`,
+			patch:   `[{"op": "remove"}]`,
+			wantErr: ErrInvalidPatch,
+		},
+		{
+			name:    "missing value",
+			doc:     `{"foo": "bar"}`,
+			patch:   `[{"op": "add", "path": "/baz"}]`,
+			wantErr: ErrInvalidPatch,
+		},
+		{
+			name:  "null value is a value",
+			doc:   `{"foo": "bar"}`,
+			patch: `[{"op": "add", "path": "/baz", "value": null}]`,
+			want:  `{"foo": "bar", "baz": null}`,
+		},
+		{
+			name:    "unknown operation",
+			doc:     `{"foo": "bar"}`,
+			patch:   `[{"op": "frobnicate", "path": "/foo"}]`,
+			wantErr: ErrInvalidPatch,
+		},
+		{
+			name:    "move into its own child",
+			doc:     `{"foo": {"bar": 1}}`,
+			patch:   `[{"op": "move", "from": "/foo", "path": "/foo/bar"}]`,
+			wantErr: ErrInvalidPatch,
+		},
+		{
+			name:    "remove a missing member",
+			doc:     `{"foo": "bar"}`,
+			patch:   `[{"op": "remove", "path": "/baz"}]`,
+			wantErr: ErrPathNotFound,
+		},
+		{
+			name:    "path through a scalar",
+			doc:     `{"foo": "bar"}`,
+			patch:   `[{"op": "add", "path": "/foo/baz", "value": 1}]`,
+			wantErr: ErrPathNotFound,
+		},
+		{
+			name:  "empty path replaces the document",
+			doc:   `{"foo": "bar"}`,
+			patch: `[{"op": "replace", "path": "", "value": {"baz": 1}}]`,
+			want:  `{"baz": 1}`,
+		},
+		{
+			name:  "copy does not alias its source",
+			doc:   `{"foo": {"bar": 1}}`,
+			patch: `[{"op": "copy", "from": "/foo", "path": "/baz"}, {"op": "replace", "path": "/baz/bar", "value": 2}]`,
+			want:  `{"foo": {"bar": 1}, "baz": {"bar": 2}}`,
+		},
+		{
+			name: "a failing operation fails the whole patch",
+			doc:  `{"foo": "bar"}`,
+			patch: `[
+				{"op": "add", "path": "/baz", "value": 1},
+				{"op": "test", "path": "/foo", "value": "qux"}
+			]`,
+			wantErr: ErrTestFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertApply(t, tt.doc, tt.patch, tt.want, tt.wantErr)
+		})
+	}
+}
+
+func TestMergePatch(t *testing.T) {
+	tests := []struct {
+		name  string
+		doc   string
+		patch string
+		want  string
+	}{
+		{"replace a member", `{"a": "b"}`, `{"a": "c"}`, `{"a": "c"}`},
+		{"add a member", `{"a": "b"}`, `{"b": "c"}`, `{"a": "b", "b": "c"}`},
+		{"null removes a member", `{"a": "b"}`, `{"a": null}`, `{}`},
+		{"nested members merge", `{"a": {"b": "c", "d": "e"}}`, `{"a": {"d": null, "f": "g"}}`, `{"a": {"b": "c", "f": "g"}}`},
+		{"arrays are replaced", `{"a": [1, 2]}`, `{"a": [3]}`, `{"a": [3]}`},
+		{"a non object patch replaces the document", `{"a": "b"}`, `["c"]`, `["c"]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MergePatch([]byte(tt.doc), []byte(tt.patch))
+			if err != nil {
+				t.Fatalf("MergePatch returned error: %v", err)
+			}
+			assertJSONEqual(t, got, tt.want)
+		})
+	}
+}
+
+// Helper function to apply a patch and check its result or error
+func assertApply(t *testing.T, doc, patch, want string, wantErr error) {
+	t.Helper()
+	got, err := Apply([]byte(doc), []byte(patch))
+	if wantErr != nil {
+		if !errors.Is(err, wantErr) {
+			t.Fatalf("Apply error = %v, want %v", err, wantErr)
+		}
+		return
+	}
+	if err != nil {
+		t.Fatalf("Apply returned error: %v", err)
+	}
+	assertJSONEqual(t, got, want)
+}
+
+// Helper function to compare two JSON documents regardless of member order
+func assertJSONEqual(t *testing.T, got []byte, want string) {
+	t.Helper()
+	var gotValue, wantValue interface{}
+	if err := json.Unmarshal(got, &gotValue); err != nil {
+		t.Fatalf("result is not JSON: %v", err)
+	}
+	if err := json.Unmarshal([]byte(want), &wantValue); err != nil {
+		t.Fatalf("expected value is not JSON: %v", err)
+	}
+	if !reflect.DeepEqual(gotValue, wantValue) {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
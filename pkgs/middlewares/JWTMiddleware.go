@@ -1,17 +1,37 @@
 package middlewares
 
 import (
+	"context"
+	"fmt"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/google/uuid"
+	"github.com/lordofthemind/EventifyGo/internals/apperrors"
 	"github.com/lordofthemind/EventifyGo/internals/responses"
+	"github.com/lordofthemind/EventifyGo/internals/services"
 	"github.com/lordofthemind/mygopher/gophertoken"
 )
 
-func AuthTokenMiddleware(tokenManager gophertoken.TokenManager) gin.HandlerFunc {
+// AuthCookieName is the cookie carrying the access token of a SuperUser
+const AuthCookieName = "SuperUserAuthorization"
+
+// SuperUserIDKey is the context key holding the ID of the authenticated SuperUser
+const SuperUserIDKey = "superUserID"
+
+// TokenIDKey is the context key holding the ID of the access token of the request
+const TokenIDKey = "tokenID"
+
+// SessionIDKey is the context key holding the ID of the session of the access token
+const SessionIDKey = "sessionID"
+
+// AuthTokenMiddleware requires a valid access token whose session was not revoked
+func AuthTokenMiddleware(tokenManager gophertoken.TokenManager, sessions services.SessionServiceInterface) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		token, err := c.Cookie("SuperUserAuthorization")
-		if err != nil {
+		token, err := c.Cookie(AuthCookieName)
+		if err != nil || token == "" {
 			response := responses.NewGinResponse(
 				c,
 				http.StatusUnauthorized,
@@ -24,22 +44,96 @@ func AuthTokenMiddleware(tokenManager gophertoken.TokenManager) gin.HandlerFunc
 			return
 		}
 
-		payload, err := tokenManager.ValidateToken(token)
+		identity, err := authenticate(c.Request.Context(), tokenManager, sessions, token, c.ClientIP())
 		if err != nil {
+			status := responses.ErrorStatus(err)
 			response := responses.NewGinResponse(
 				c,
-				http.StatusUnauthorized,
+				status,
 				"Invalid token",
 				nil,
 				err.Error(),
 			)
-			c.JSON(http.StatusUnauthorized, response)
+			c.JSON(status, response)
 			c.Abort()
 			return
 		}
 
-		c.Set("userID", payload.ID)         // Use payload ID or other necessary field
-		c.Set("username", payload.Username) // Optionally set username if needed
+		c.Set(TokenIDKey, identity.tokenID)
+		c.Set(SuperUserIDKey, identity.superUserID)
+		c.Set(SessionIDKey, identity.sessionID)
+		c.Next()
+	}
+}
+
+// OptionalAuthTokenMiddleware identifies the SuperUser when a valid token is present and
+// lets anonymous requests through otherwise
+func OptionalAuthTokenMiddleware(tokenManager gophertoken.TokenManager, sessions services.SessionServiceInterface) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token, err := c.Cookie(AuthCookieName); err == nil && token != "" {
+			if identity, err := authenticate(c.Request.Context(), tokenManager, sessions, token, c.ClientIP()); err == nil {
+				c.Set(TokenIDKey, identity.tokenID)
+				c.Set(SuperUserIDKey, identity.superUserID)
+				c.Set(SessionIDKey, identity.sessionID)
+			}
+		}
 		c.Next()
 	}
 }
+
+// AuthTokenFiberMiddleware is AuthTokenMiddleware for Fiber
+func AuthTokenFiberMiddleware(tokenManager gophertoken.TokenManager, sessions services.SessionServiceInterface) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		token := c.Cookies(AuthCookieName)
+		if token == "" {
+			return c.Status(fiber.StatusUnauthorized).JSON(responses.NewFiberResponse(c, fiber.StatusUnauthorized, "Unauthorized", nil, "Failed to get token from cookie"))
+		}
+
+		identity, err := authenticate(c.UserContext(), tokenManager, sessions, token, utils.CopyString(c.IP()))
+		if err != nil {
+			status := responses.ErrorStatus(err)
+			return c.Status(status).JSON(responses.NewFiberResponse(c, status, "Invalid token", nil, err.Error()))
+		}
+
+		c.Locals(TokenIDKey, identity.tokenID)
+		c.Locals(SuperUserIDKey, identity.superUserID)
+		c.Locals(SessionIDKey, identity.sessionID)
+		return c.Next()
+	}
+}
+
+// OptionalAuthTokenFiberMiddleware identifies the SuperUser when a valid token is present and
+// lets anonymous requests through otherwise
+func OptionalAuthTokenFiberMiddleware(tokenManager gophertoken.TokenManager, sessions services.SessionServiceInterface) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if token := c.Cookies(AuthCookieName); token != "" {
+			if identity, err := authenticate(c.UserContext(), tokenManager, sessions, token, utils.CopyString(c.IP())); err == nil {
+				c.Locals(TokenIDKey, identity.tokenID)
+				c.Locals(SuperUserIDKey, identity.superUserID)
+				c.Locals(SessionIDKey, identity.sessionID)
+			}
+		}
+		return c.Next()
+	}
+}
+
+// tokenIdentity is what an access token says about its request
+type tokenIdentity struct {
+	tokenID     uuid.UUID
+	superUserID uuid.UUID
+	sessionID   uuid.UUID
+}
+
+// Helper function to validate a token and check that its session is still live
+func authenticate(ctx context.Context, tokenManager gophertoken.TokenManager, sessions services.SessionServiceInterface, token, clientIP string) (*tokenIdentity, error) {
+	payload, err := tokenManager.ValidateToken(token)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", apperrors.ErrUnauthorized, err)
+	}
+
+	superUserID, sessionID, err := sessions.Authenticate(ctx, payload, clientIP)
+	if err != nil {
+		return nil, err
+	}
+	return &tokenIdentity{tokenID: payload.ID, superUserID: superUserID, sessionID: sessionID}, nil
+}
@@ -0,0 +1,96 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/lordofthemind/EventifyGo/internals/responses"
+	"github.com/lordofthemind/EventifyGo/internals/services"
+)
+
+// RequirePermission lets a request through when the authenticated SuperUser holds permission.
+// It runs after AuthTokenMiddleware.
+func RequirePermission(authorizer services.AuthorizationServiceInterface, permission string) gin.HandlerFunc {
+	return requireGinPermission(authorizer, permission, false)
+}
+
+// RequireSelfOrPermission is RequirePermission, also letting SuperUsers act on their own
+// record, the one named by the "id" route parameter
+func RequireSelfOrPermission(authorizer services.AuthorizationServiceInterface, permission string) gin.HandlerFunc {
+	return requireGinPermission(authorizer, permission, true)
+}
+
+// RequirePermissionFiber is RequirePermission for Fiber, it runs after AuthTokenFiberMiddleware
+func RequirePermissionFiber(authorizer services.AuthorizationServiceInterface, permission string) fiber.Handler {
+	return requireFiberPermission(authorizer, permission, false)
+}
+
+// RequireSelfOrPermissionFiber is RequireSelfOrPermission for Fiber
+func RequireSelfOrPermissionFiber(authorizer services.AuthorizationServiceInterface, permission string) fiber.Handler {
+	return requireFiberPermission(authorizer, permission, true)
+}
+
+func requireGinPermission(authorizer services.AuthorizationServiceInterface, permission string, allowSelf bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		superUserID, ok := c.Get(SuperUserIDKey)
+		id, _ := superUserID.(uuid.UUID)
+		if !ok || id == uuid.Nil {
+			response := responses.NewGinResponse(c, http.StatusUnauthorized, "Unauthorized", nil, "No authenticated superuser")
+			c.JSON(http.StatusUnauthorized, response)
+			c.Abort()
+			return
+		}
+
+		if allowSelf && isSelf(c.Param("id"), id) {
+			c.Next()
+			return
+		}
+
+		allowed, err := authorizer.HasPermission(c.Request.Context(), id, permission)
+		if err != nil {
+			response := responses.NewGinResponse(c, http.StatusInternalServerError, "Failed to check permissions", nil, err.Error())
+			c.JSON(http.StatusInternalServerError, response)
+			c.Abort()
+			return
+		}
+		if !allowed {
+			response := responses.NewGinResponse(c, http.StatusForbidden, "Forbidden", nil, "Missing permission "+permission)
+			c.JSON(http.StatusForbidden, response)
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func requireFiberPermission(authorizer services.AuthorizationServiceInterface, permission string, allowSelf bool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, ok := c.Locals(SuperUserIDKey).(uuid.UUID)
+		if !ok || id == uuid.Nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(responses.NewFiberResponse(c, fiber.StatusUnauthorized, "Unauthorized", nil, "No authenticated superuser"))
+		}
+
+		if allowSelf && isSelf(c.Params("id"), id) {
+			return c.Next()
+		}
+
+		allowed, err := authorizer.HasPermission(c.UserContext(), id, permission)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(responses.NewFiberResponse(c, fiber.StatusInternalServerError, "Failed to check permissions", nil, err.Error()))
+		}
+		if !allowed {
+			return c.Status(fiber.StatusForbidden).JSON(responses.NewFiberResponse(c, fiber.StatusForbidden, "Forbidden", nil, "Missing permission "+permission))
+		}
+
+		return c.Next()
+	}
+}
+
+// Helper function to check whether a route parameter names the authenticated SuperUser
+func isSelf(param string, superUserID uuid.UUID) bool {
+	id, err := uuid.Parse(param)
+	return err == nil && id == superUserID
+}
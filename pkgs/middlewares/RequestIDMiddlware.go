@@ -2,26 +2,48 @@ package middlewares
 
 import (
 	"github.com/gin-gonic/gin"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
 	"github.com/google/uuid"
+	"github.com/lordofthemind/EventifyGo/internals/responses"
 )
 
+// RequestIDHeader carries the request ID in both directions
+const RequestIDHeader = "X-Request-ID"
+
 // RequestIDMiddleware generates a request ID and adds it to the context and response
 func RequestIDGinMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Check if client sent a request ID
-		requestID := c.Request.Header.Get("X-Request-ID")
+		requestID := c.Request.Header.Get(RequestIDHeader)
 		if requestID == "" {
 			// Generate a new UUID if not provided by the client
 			requestID = uuid.New().String()
 		}
 
 		// Add the request ID to the context
-		c.Set("RequestID", requestID)
+		c.Set(responses.RequestIDKey, requestID)
 
 		// Add the request ID to the response header
-		c.Writer.Header().Set("X-Request-ID", requestID)
+		c.Writer.Header().Set(RequestIDHeader, requestID)
 
 		// Continue processing
 		c.Next()
 	}
 }
+
+// RequestIDFiberMiddleware is RequestIDGinMiddleware for Fiber, the ID is kept in c.Locals
+func RequestIDFiberMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// Fiber reuses the memory of request headers, copy the ID to keep it past the request
+		requestID := utils.CopyString(c.Get(RequestIDHeader))
+		if requestID == "" {
+			requestID = uuid.New().String()
+		}
+
+		c.Locals(responses.RequestIDKey, requestID)
+		c.Set(RequestIDHeader, requestID)
+
+		return c.Next()
+	}
+}
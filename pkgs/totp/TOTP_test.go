@@ -0,0 +1,116 @@
+package totp
+
+import (
+	"testing"
+	"time"
+)
+
+// rfcSecret is the SHA-1 seed of RFC 6238 Appendix B, "12345678901234567890" in base32
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+// The RFC lists 8 digit codes, a 6 digit code is the same value modulo 10^6
+func TestCodeRFC6238Vectors(t *testing.T) {
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	for _, tt := range tests {
+		code, err := Code(rfcSecret, Step(time.Unix(tt.unix, 0)))
+		if err != nil {
+			t.Fatalf("Code(%d) returned error: %v", tt.unix, err)
+		}
+		if code != tt.code {
+			t.Errorf("Code(%d) = %s, want %s", tt.unix, code, tt.code)
+		}
+	}
+}
+
+func TestValidateSkew(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	current := Step(now)
+
+	tests := []struct {
+		name  string
+		delta int64
+		valid bool
+	}{
+		{"current step", 0, true},
+		{"previous step", -1, true},
+		{"next step", 1, true},
+		{"two steps behind", -2, false},
+		{"two steps ahead", 2, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, err := Code(rfcSecret, current+tt.delta)
+			if err != nil {
+				t.Fatalf("Code returned error: %v", err)
+			}
+			step, valid, err := Validate(rfcSecret, code, now)
+			if err != nil {
+				t.Fatalf("Validate returned error: %v", err)
+			}
+			if valid != tt.valid {
+				t.Fatalf("Validate valid = %v, want %v", valid, tt.valid)
+			}
+			if valid && step != current+tt.delta {
+				t.Errorf("Validate step = %d, want %d", step, current+tt.delta)
+			}
+		})
+	}
+}
+
+func TestValidateInput(t *testing.T) {
+	now := time.Unix(59, 0)
+
+	tests := []struct {
+		name    string
+		secret  string
+		code    string
+		valid   bool
+		wantErr bool
+	}{
+		{"spaces in code", rfcSecret, " 287 082 ", true, false},
+		{"lower case secret", "gezdgnbvgy3tqojqgezdgnbvgy3tqojq", "287082", true, false},
+		{"wrong code", rfcSecret, "287083", false, false},
+		{"short code", rfcSecret, "28708", false, false},
+		{"long code", rfcSecret, "2870820", false, false},
+		{"invalid secret", "not base32!", "287082", false, true},
+		{"empty secret", "", "287082", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, valid, err := Validate(tt.secret, tt.code, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if valid != tt.valid {
+				t.Errorf("Validate valid = %v, want %v", valid, tt.valid)
+			}
+		})
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret returned error: %v", err)
+	}
+	key, err := decodeSecret(secret)
+	if err != nil {
+		t.Fatalf("generated secret does not decode: %v", err)
+	}
+	if len(key) != secretSize {
+		t.Errorf("generated key is %d bytes, want %d", len(key), secretSize)
+	}
+}